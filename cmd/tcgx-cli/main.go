@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/peterkuimelis/tcgx/internal/game"
 	tcgxnet "github.com/peterkuimelis/tcgx/internal/net"
 )
 
@@ -21,6 +22,8 @@ func main() {
 		runHost(os.Args[2:])
 	case "join":
 		runJoin(os.Args[2:])
+	case "watch":
+		runWatch(os.Args[2:])
 	default:
 		printUsage()
 		os.Exit(1)
@@ -29,12 +32,14 @@ func main() {
 
 func printUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  tcgx host [--deck N] [--port P] [--decks FILE]")
-	fmt.Println("  tcgx join [--deck N] [--addr ADDR] ")
+	fmt.Println("  tcgx host [--deck N] [--port P] [--decks FILE] [--host-decks FILE] [--debug-rewind] [--seed N] [--reconnect-grace D] [--match bo3]")
+	fmt.Println("  tcgx join [--deck N] [--addr ADDR] [--decks FILE] [--token TOKEN]")
+	fmt.Println("  tcgx watch [--addr ADDR]")
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  host    Start a game server and play as Player 1")
 	fmt.Println("  join    Connect to a game server and play as Player 2")
+	fmt.Println("  watch   Connect to a game server as a spectator")
 }
 
 func runHost(args []string) {
@@ -42,12 +47,29 @@ func runHost(args []string) {
 	deck := fs.Int("deck", 1, "deck number to use (from decks.yaml)")
 	port := fs.String("port", "9000", "TCP port to listen on")
 	decksFile := fs.String("decks", "decks.yaml", "path to decks file")
+	hostDecksFile := fs.String("host-decks", "", "path to the host's own decks file (defaults to --decks)")
+	debugRewind := fs.Bool("debug-rewind", false, "allow players to rewind to the start of the current turn (debug)")
+	seed := fs.Int64("seed", 0, "duel RNG seed, to replay a game (0 picks one at random)")
+	match := fs.String("match", "bo1", "match format: bo1 plays a single duel, bo3 a best-of-three match with side decks")
+	reconnectGrace := fs.Duration("reconnect-grace", tcgxnet.DefaultReconnectGrace, "how long to wait for a disconnected opponent to rejoin")
 	fs.Parse(args)
 
+	bestOf, err := tcgxnet.ParseBestOf(*match)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	srv := &tcgxnet.Server{
-		DeckFile: *decksFile,
-		Port:     *port,
-		HostDeck: *deck,
+		DeckFile:     *decksFile,
+		Port:         *port,
+		HostDeck:     *deck,
+		HostDeckFile: *hostDecksFile,
+		DebugRewind:  *debugRewind,
+		Seed:         *seed,
+		BestOf:       bestOf,
+
+		ReconnectGrace: *reconnectGrace,
 	}
 
 	if err := srv.Run(context.Background()); err != nil {
@@ -60,9 +82,42 @@ func runJoin(args []string) {
 	fs := flag.NewFlagSet("join", flag.ExitOnError)
 	deck := fs.Int("deck", 2, "deck number to use (from decks.yaml)")
 	addr := fs.String("addr", "localhost:9000", "server address to connect to")
+	decksFile := fs.String("decks", "", "path to a local decks file (deck is sent inline; default uses the host's file)")
+	token := fs.String("token", "", "session token, to rejoin a duel after disconnecting")
+	fs.Parse(args)
+
+	if *token != "" {
+		if err := tcgxnet.Reconnect(context.Background(), *addr, *token); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	source := tcgxnet.DeckSource{File: *decksFile, Number: *deck}
+	if *decksFile != "" {
+		// Check the deck here rather than have the host turn it away
+		_, cards, err := source.Load(*decksFile)
+		if err == nil {
+			err = game.ValidateDeck(cards)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: deck %d: %v\n", *deck, err)
+			os.Exit(1)
+		}
+	}
+	if err := tcgxnet.Connect(context.Background(), *addr, source); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:9000", "server address to connect to")
 	fs.Parse(args)
 
-	if err := tcgxnet.Connect(context.Background(), *addr, *deck); err != nil {
+	if err := tcgxnet.Spectate(context.Background(), *addr); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
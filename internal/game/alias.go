@@ -0,0 +1,37 @@
+package game
+
+// treatedAs returns the card a face-up agent's NameAs effect says it is
+// treated as, if any.
+func (d *Duel) treatedAs(card *CardInstance) (*Card, bool) {
+	if card.Card.CardType != CardTypeAgent || card.Face != FaceUp || card.Zone != ZoneAgent {
+		return nil, false
+	}
+	for _, eff := range card.Card.Effects {
+		if eff.NameAs == nil {
+			continue
+		}
+		if as, ok := eff.NameAs(d, card); ok {
+			return as, true
+		}
+	}
+	return nil, false
+}
+
+// agentType returns the agent type card is treated as having.
+func (d *Duel) agentType(card *CardInstance) string {
+	if as, ok := d.treatedAs(card); ok {
+		return as.AgentType
+	}
+	return card.Card.AgentType
+}
+
+// hasName reports whether card is treated as having the given name, counting
+// the second name a card also has (AlsoNamed). Name-based checks ("Tribute 1
+// Abyssal Circuit Leviathan") should go through it.
+func (d *Duel) hasName(card *CardInstance, name string) bool {
+	as, ok := d.treatedAs(card)
+	if !ok {
+		as = card.Card
+	}
+	return as.Name == name || as.AlsoNamed == name
+}
@@ -14,9 +14,11 @@ func (d *Duel) computeBattlePhaseActions() []Action {
 	opp := gs.Players[gs.Opponent(tp)]
 	var actions []Action
 
-	// Eligible attackers: face-up ATK position agents that haven't attacked
+	// Eligible attackers: face-up ATK position agents with an attack left,
+	// unless the player has used up a board-wide limit (Single Strike Doctrine)
+	limited := d.attackLimitReached(tp)
 	for _, m := range p.AgentZones {
-		if m == nil || m.Face != FaceUp || m.Position != PositionATK || m.AttackedThisTurn {
+		if limited || m == nil || m.Face != FaceUp || m.Position != PositionATK || !d.hasAttackLeft(m) {
 			continue
 		}
 
@@ -34,6 +36,7 @@ func (d *Duel) computeBattlePhaseActions() []Action {
 					targetable = append(targetable, target)
 				}
 			}
+			targetable = d.tauntTargets(targetable)
 
 			if len(targetable) > 0 {
 				for _, target := range targetable {
@@ -52,7 +55,7 @@ func (d *Duel) computeBattlePhaseActions() []Action {
 			}
 
 			// Check for conditional direct attack (Raging Plasma Sprite, etc.)
-			if d.canDirectAttackWithDefenders(m) {
+			if d.canDirectAttackWithDefenders(m) && !d.cannotDirectAttack(m) {
 				actions = append(actions, Action{
 					Type:   ActionDirectAttack,
 					Player: tp,
@@ -62,7 +65,7 @@ func (d *Duel) computeBattlePhaseActions() []Action {
 			}
 
 			// If all opponents are untargetable but no direct attack, offer direct attack
-			if len(targetable) == 0 && !d.canDirectAttackWithDefenders(m) {
+			if len(targetable) == 0 && !d.canDirectAttackWithDefenders(m) && !d.cannotDirectAttack(m) {
 				actions = append(actions, Action{
 					Type:   ActionDirectAttack,
 					Player: tp,
@@ -70,7 +73,7 @@ func (d *Duel) computeBattlePhaseActions() []Action {
 					Desc:   fmt.Sprintf("Direct attack with %s (ATK %d)", m.Card.Name, m.CurrentATK()),
 				})
 			}
-		} else {
+		} else if !d.cannotDirectAttack(m) {
 			// Direct attack (no opponent agents)
 			actions = append(actions, Action{
 				Type:   ActionDirectAttack,
@@ -86,6 +89,7 @@ func (d *Duel) computeBattlePhaseActions() []Action {
 		Type: ActionEnterMainPhase2,
 		Desc: "Enter Main Phase 2",
 	})
+	actions = append(actions, concedeAction(tp))
 
 	return actions
 }
@@ -100,8 +104,11 @@ func (d *Duel) executeAttack(action Action) error {
 	defender := action.Targets[0]
 
 	attacker.AttackedThisTurn = true
+	attacker.AttacksThisTurn++
+	gs.TotalAttacksDeclared[tp]++
 	gs.CurrentAttacker = attacker
 	gs.CurrentTarget = defender
+	gs.AttackNegated = false
 
 	// Log attack declaration
 	defenderName := defender.Card.Name
@@ -111,7 +118,7 @@ func (d *Duel) executeAttack(action Action) error {
 	d.log(log.NewAttackDeclareEvent(gs.Turn, tp, attacker.Card.Name, defenderName))
 
 	// Response window after attack declaration (e.g. Reflector Array, Reactive Plating)
-	if err := d.openResponseWindow(opp); err != nil {
+	if err := d.openResponseWindow(opp, log.EventAttackDeclare); err != nil {
 		return err
 	}
 	if gs.Chain != nil {
@@ -122,6 +129,10 @@ func (d *Duel) executeAttack(action Action) error {
 	if gs.Over {
 		return nil
 	}
+	if gs.AttackNegated {
+		d.stopNegatedAttack(tp, attacker)
+		return nil
+	}
 
 	// Check if attacker was removed during response
 	if !d.isOnField(attacker) {
@@ -130,7 +141,14 @@ func (d *Duel) executeAttack(action Action) error {
 	// Re-check attack restrictions (e.g. Gravity Clamp activated during response)
 	if !d.canAgentAttack(attacker) {
 		d.log(log.NewAttackStoppedEvent(gs.Turn, tp, attacker.Card.Name, "restriction"))
-		attacker.AttackedThisTurn = false
+		d.undoAttack(attacker)
+		gs.CurrentAttacker = nil
+		gs.CurrentTarget = nil
+		return nil
+	}
+	// A forced switch to DEF during the response (Knockdown Pulse) ends the attack
+	if attacker.Position != PositionATK {
+		d.log(log.NewAttackStoppedEvent(gs.Turn, tp, attacker.Card.Name, "no longer in ATK position"))
 		gs.CurrentAttacker = nil
 		gs.CurrentTarget = nil
 		return nil
@@ -140,6 +158,12 @@ func (d *Duel) executeAttack(action Action) error {
 		d.log(log.NewReplayEvent(gs.Turn, tp, attacker.Card.Name))
 
 		oppAgents := gs.Players[opp].Agents()
+		if len(oppAgents) == 0 && d.cannotDirectAttack(attacker) {
+			// No targets and no direct attacks (Dual-Fang Unit): the attack ends
+			gs.CurrentAttacker = nil
+			gs.CurrentTarget = nil
+			return nil
+		}
 		if len(oppAgents) == 0 {
 			// No targets: attacker can do a direct attack or cancel
 			replayActions := []Action{
@@ -160,7 +184,7 @@ func (d *Duel) executeAttack(action Action) error {
 			atkVal := attacker.CurrentATK()
 			d.log(log.NewDamageCalcEvent(gs.Turn, tp,
 				fmt.Sprintf("Direct attack: %s (ATK %d) → P%d", attacker.Card.Name, atkVal, opp+1)))
-			d.applyDamage(opp, atkVal, fmt.Sprintf("direct attack by %s (replay)", attacker.Card.Name))
+			d.applyBattleDamage(opp, atkVal, fmt.Sprintf("direct attack by %s (replay)", attacker.Card.Name))
 			gs.CurrentAttacker = nil
 			gs.CurrentTarget = nil
 			return nil
@@ -205,6 +229,9 @@ func (d *Duel) executeAttack(action Action) error {
 		d.log(log.NewFlipEvent(gs.Turn, gs.Phase.String(), opp, defender.Card.Name))
 	}
 
+	attacker.LastBattled, attacker.LastBattledTurn = defender, gs.Turn
+	defender.LastBattled, defender.LastBattledTurn = attacker, gs.Turn
+
 	// Damage calculation
 	atkVal := attacker.CurrentATK()
 
@@ -219,29 +246,40 @@ func (d *Duel) executeAttack(action Action) error {
 		if atkVal > defATK {
 			// Attacker wins: defender destroyed, opponent takes damage
 			damage := atkVal - defATK
-			d.destroyByBattle(defender, opp)
-			destroyedAgents = append(destroyedAgents, defender)
-			d.applyDamage(opp, damage, fmt.Sprintf("battle: %s vs %s", attacker.Card.Name, defender.Card.Name))
-			battleDamageDealt = true
+			if !d.shieldedFromBattle(defender) {
+				d.destroyByBattle(defender, opp)
+				destroyedAgents = append(destroyedAgents, defender)
+				battleDamageDealt = d.applyBattleDamage(opp, damage, fmt.Sprintf("battle: %s vs %s", attacker.Card.Name, defender.Card.Name))
+				if !gs.Over && d.hasOverrun(attacker) {
+					d.applyBattleDamage(opp, damage, fmt.Sprintf("overrun: %s", attacker.Card.Name))
+				}
+			}
 		} else if defATK > atkVal {
 			// Defender wins: attacker destroyed, turn player takes damage
 			damage := defATK - atkVal
-			d.destroyByBattle(attacker, tp)
-			destroyedAgents = append(destroyedAgents, attacker)
-			d.applyDamage(tp, damage, fmt.Sprintf("battle: %s vs %s", attacker.Card.Name, defender.Card.Name))
+			if !d.shieldedFromBattle(attacker) {
+				d.destroyByBattle(attacker, tp)
+				destroyedAgents = append(destroyedAgents, attacker)
+				d.applyBattleDamage(tp, damage, fmt.Sprintf("battle: %s vs %s", attacker.Card.Name, defender.Card.Name))
+			}
 		} else {
 			// Tie: both destroyed, no damage
-			d.destroyByBattle(attacker, tp)
-			d.destroyByBattle(defender, opp)
-			destroyedAgents = append(destroyedAgents, attacker, defender)
+			if !d.shieldedFromBattle(attacker) {
+				d.destroyByBattle(attacker, tp)
+				destroyedAgents = append(destroyedAgents, attacker)
+			}
+			if !d.shieldedFromBattle(defender) {
+				d.destroyByBattle(defender, opp)
+				destroyedAgents = append(destroyedAgents, defender)
+			}
 		}
 		if battleDamageDealt && d.isOnField(attacker) && attacker.Card.IsEffect {
 			d.checkBattleDamageTrigger(attacker, tp)
 		}
 		// "Destroys by battle" triggers (separate from dealing damage)
-		if atkVal > defATK && attacker.Card.IsEffect {
+		if atkVal > defATK && !d.isOnField(defender) && attacker.Card.IsEffect {
 			d.checkDestroyByBattleTrigger(attacker, tp)
-		} else if defATK > atkVal && defender.Card.IsEffect {
+		} else if defATK > atkVal && !d.isOnField(attacker) && defender.Card.IsEffect {
 			d.checkDestroyByBattleTrigger(defender, opp)
 		}
 	} else {
@@ -250,7 +288,9 @@ func (d *Duel) executeAttack(action Action) error {
 		d.log(log.NewDamageCalcEvent(gs.Turn, tp,
 			fmt.Sprintf("Damage calc: %s (ATK %d) vs %s (DEF %d)", attacker.Card.Name, atkVal, defender.Card.Name, defDEF)))
 
-		if atkVal > defDEF {
+		if atkVal > defDEF && d.shieldedFromBattle(defender) {
+			// Shielded: defender survives and piercing damage is negated
+		} else if atkVal > defDEF {
 			d.destroyByBattle(defender, opp)
 			destroyedAgents = append(destroyedAgents, defender)
 			// "Destroys by battle" trigger
@@ -260,15 +300,15 @@ func (d *Duel) executeAttack(action Action) error {
 			// Piercing damage check
 			if d.hasPiercing(attacker) {
 				pierceDmg := atkVal - defDEF
-				d.applyDamage(opp, pierceDmg, fmt.Sprintf("piercing: %s vs %s", attacker.Card.Name, defender.Card.Name))
-				if d.isOnField(attacker) && attacker.Card.IsEffect {
+				dealt := d.applyBattleDamage(opp, pierceDmg, fmt.Sprintf("piercing: %s vs %s", attacker.Card.Name, defender.Card.Name))
+				if dealt && d.isOnField(attacker) && attacker.Card.IsEffect {
 					d.checkBattleDamageTrigger(attacker, tp)
 				}
 			}
 		} else if defDEF > atkVal {
 			// Defender wins: no destruction, attacker takes damage
 			damage := defDEF - atkVal
-			d.applyDamage(tp, damage, fmt.Sprintf("battle: %s vs %s", attacker.Card.Name, defender.Card.Name))
+			d.applyBattleDamage(tp, damage, fmt.Sprintf("battle: %s vs %s", attacker.Card.Name, defender.Card.Name))
 		}
 		// Tie: nothing happens
 	}
@@ -303,13 +343,16 @@ func (d *Duel) executeDirectAttack(action Action) error {
 	attacker := action.Card
 
 	attacker.AttackedThisTurn = true
+	attacker.AttacksThisTurn++
+	gs.TotalAttacksDeclared[tp]++
 	gs.CurrentAttacker = attacker
 	gs.CurrentTarget = nil
+	gs.AttackNegated = false
 
 	d.log(log.NewDirectAttackDeclareEvent(gs.Turn, tp, attacker.Card.Name))
 
 	// Response window after direct attack declaration
-	if err := d.openResponseWindow(opp); err != nil {
+	if err := d.openResponseWindow(opp, log.EventDirectAttackDeclare); err != nil {
 		return err
 	}
 	if gs.Chain != nil {
@@ -320,6 +363,10 @@ func (d *Duel) executeDirectAttack(action Action) error {
 	if gs.Over {
 		return nil
 	}
+	if gs.AttackNegated {
+		d.stopNegatedAttack(tp, attacker)
+		return nil
+	}
 	// Check if attacker was removed
 	if !d.isOnField(attacker) {
 		return nil
@@ -327,7 +374,14 @@ func (d *Duel) executeDirectAttack(action Action) error {
 	// Re-check attack restrictions (e.g. Gravity Clamp activated during response)
 	if !d.canAgentAttack(attacker) {
 		d.log(log.NewAttackStoppedEvent(gs.Turn, tp, attacker.Card.Name, "restriction"))
-		attacker.AttackedThisTurn = false
+		d.undoAttack(attacker)
+		gs.CurrentAttacker = nil
+		gs.CurrentTarget = nil
+		return nil
+	}
+	// A forced switch to DEF during the response (Knockdown Pulse) ends the attack
+	if attacker.Position != PositionATK {
+		d.log(log.NewAttackStoppedEvent(gs.Turn, tp, attacker.Card.Name, "no longer in ATK position"))
 		gs.CurrentAttacker = nil
 		gs.CurrentTarget = nil
 		return nil
@@ -337,10 +391,10 @@ func (d *Duel) executeDirectAttack(action Action) error {
 	d.log(log.NewDamageCalcEvent(gs.Turn, tp,
 		fmt.Sprintf("Direct attack: %s (ATK %d) → P%d", attacker.Card.Name, atkVal, opp+1)))
 
-	d.applyDamage(opp, atkVal, fmt.Sprintf("direct attack by %s", attacker.Card.Name))
+	dealt := d.applyBattleDamage(opp, atkVal, fmt.Sprintf("direct attack by %s", attacker.Card.Name))
 
 	// Check for battle damage triggers (e.g. Aero-Knight Parshath draw)
-	if d.isOnField(attacker) && attacker.Card.IsEffect {
+	if dealt && d.isOnField(attacker) && attacker.Card.IsEffect {
 		d.checkBattleDamageTrigger(attacker, tp)
 	}
 
@@ -350,12 +404,47 @@ func (d *Duel) executeDirectAttack(action Action) error {
 	return nil
 }
 
+// negateAttack makes the current attack end once its response window closes,
+// before any battle or damage (Bodyguard Splice).
+func (d *Duel) negateAttack() {
+	d.State.AttackNegated = true
+}
+
+// stopNegatedAttack ends a negated attack. The attacker still counts as having attacked.
+func (d *Duel) stopNegatedAttack(tp int, attacker *CardInstance) {
+	gs := d.State
+	d.log(log.NewAttackStoppedEvent(gs.Turn, tp, attacker.Card.Name, "negated"))
+	gs.AttackNegated = false
+	gs.CurrentAttacker = nil
+	gs.CurrentTarget = nil
+}
+
+// shieldedFromBattle reports whether a face-up card (Emergency Shields) saves
+// agent from being destroyed by battle; its controller then takes no battle damage.
+func (d *Duel) shieldedFromBattle(agent *CardInstance) bool {
+	gs := d.State
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.BattleShield != nil && eff.BattleShield(d, c, agent) {
+				d.log(log.NewActivateEvent(gs.Turn, gs.Phase.String(), c.Controller, c.Card.Name))
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // destroyByBattle sends a agent to its owner's scrapheap as a result of battle destruction.
 func (d *Duel) destroyByBattle(card *CardInstance, controller int) {
 	gs := d.State
 	p := gs.Players[controller]
 
+	if d.survivesDestruction(card, "destruction by battle") {
+		return
+	}
+
 	d.log(log.NewBattleDestroyEvent(gs.Turn, controller, card.Card.Name))
+	gs.DestroyedByBattle[controller] = true
 
 	// Destroy equips attached to this agent
 	d.destroyEquips(card)
@@ -363,10 +452,8 @@ func (d *Duel) destroyByBattle(card *CardInstance, controller int) {
 	p.RemoveAgent(card)
 
 	// Cards go to owner's scrapheap, not controller's
-	owner := gs.Players[card.Owner]
-	owner.SendToScrapheap(card)
-
-	d.log(log.NewSendToScrapheapEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, "destroyed by battle"))
+	d.sendDestroyed(card, "destroyed by battle")
+	d.triggerAgentDestroyed(card, controller)
 }
 
 // isOnField checks if a card instance is still on the field (agent, tech, or OS zone).
@@ -396,13 +483,25 @@ func (d *Duel) hasPiercing(attacker *CardInstance) bool {
 		return false
 	}
 	for _, eff := range attacker.Card.Effects {
-		if eff.HasPiercing {
+		if eff.HasPiercing || (eff.Piercing != nil && eff.Piercing(d, attacker)) {
 			return true
 		}
 	}
 	return false
 }
 
+// hasOverrun checks if a face-up card grants the attacker follow-through damage (Overrun Protocol).
+func (d *Duel) hasOverrun(attacker *CardInstance) bool {
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.Overrun != nil && eff.Overrun(d, c, attacker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // checkBattleDamageTrigger fires any "when this card deals battle damage" triggers.
 func (d *Duel) checkBattleDamageTrigger(attacker *CardInstance, controller int) {
 	for _, eff := range attacker.Card.Effects {
@@ -421,10 +520,17 @@ func (d *Duel) checkDestroyByBattleTrigger(victor *CardInstance, controller int)
 	}
 }
 
-// checkBattleDestructionTriggers checks if agents destroyed by battle have triggers.
+// checkBattleDestructionTriggers checks if agents destroyed by battle have triggers,
+// and gives set traps that respond to battle destruction a chance to activate.
 func (d *Duel) checkBattleDestructionTriggers(destroyed []*CardInstance) {
 	gs := d.State
+	gs.LastBattleDestroyed = destroyed
+	defer func() { gs.LastBattleDestroyed = nil }()
+
 	for _, card := range destroyed {
+		if d.isOnField(card) {
+			continue // a replacement effect kept it on the field
+		}
 		for _, eff := range card.Card.Effects {
 			if eff.OnBattleDestruction != nil {
 				gs.PendingTriggers = append(gs.PendingTriggers, PendingTrigger{
@@ -444,9 +550,20 @@ func (d *Duel) checkBattleDestructionTriggers(destroyed []*CardInstance) {
 			}
 		}
 	}
-	if len(gs.PendingTriggers) > 0 {
-		_ = d.processEffectSerialization(log.EventBattleDestroy)
+	_ = d.processEffectSerialization(log.EventBattleDestroy)
+}
+
+// battleOpponent returns the agent that battled the given one in the current
+// damage step, or nil if it was not part of the battle.
+func (d *Duel) battleOpponent(card *CardInstance) *CardInstance {
+	gs := d.State
+	switch {
+	case gs.CurrentAttacker != nil && gs.CurrentAttacker.ID == card.ID:
+		return gs.CurrentTarget
+	case gs.CurrentTarget != nil && gs.CurrentTarget.ID == card.ID:
+		return gs.CurrentAttacker
 	}
+	return nil
 }
 
 // canAgentAttack checks if a agent is allowed to attack (level restrictions, etc.).
@@ -476,6 +593,23 @@ func (d *Duel) canAgentAttack(agent *CardInstance) bool {
 	return true
 }
 
+// attackLimitReached reports whether an opponent's face-up card caps how many
+// attacks player may declare in total this turn, and that cap is used up.
+func (d *Duel) attackLimitReached(player int) bool {
+	gs := d.State
+	for _, c := range d.faceUpCards() {
+		if c.Controller == player || d.activationPending(c) {
+			continue
+		}
+		for _, eff := range c.Card.Effects {
+			if eff.OpponentAttackLimit > 0 && gs.TotalAttacksDeclared[player] >= eff.OpponentAttackLimit {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // canAgentBeAttacked checks if a agent can be targeted for an attack.
 func (d *Duel) canAgentBeAttacked(agent *CardInstance) bool {
 	for _, eff := range agent.Card.Effects {
@@ -486,6 +620,64 @@ func (d *Duel) canAgentBeAttacked(agent *CardInstance) bool {
 	return true
 }
 
+// tauntTargets restricts attack targets to taunting agents (Aggro Beacon, etc.)
+// when any are among them. With several taunts, any of them may be chosen.
+func (d *Duel) tauntTargets(targets []*CardInstance) []*CardInstance {
+	var taunting []*CardInstance
+	for _, target := range targets {
+		for _, eff := range target.Card.Effects {
+			if eff.Taunt != nil && eff.Taunt(d, target, target.Controller) {
+				taunting = append(taunting, target)
+				break
+			}
+		}
+	}
+	if len(taunting) > 0 {
+		return taunting
+	}
+	return targets
+}
+
+// cannotDirectAttack reports whether a agent is barred from attacking directly
+// (Dual-Fang Unit), even when the opponent controls no agents.
+func (d *Duel) cannotDirectAttack(agent *CardInstance) bool {
+	for _, eff := range agent.Card.Effects {
+		if eff.CannotDirectAttack {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAttackLeft reports whether a agent may still declare an attack this turn:
+// one attack plus any ExtraAttacks its effects grant. A agent marked as having
+// attacked without declaring one (Chrome Paladin) gets none.
+func (d *Duel) hasAttackLeft(agent *CardInstance) bool {
+	if !agent.AttackedThisTurn {
+		return true
+	}
+	if agent.AttacksThisTurn == 0 {
+		return false
+	}
+	extra := 0
+	for _, eff := range agent.Card.Effects {
+		if eff.ExtraAttacks != nil {
+			extra += eff.ExtraAttacks(d, agent, agent.Controller)
+		}
+	}
+	return agent.AttacksThisTurn <= extra
+}
+
+// undoAttack takes back an attack declaration that was stopped by a restriction,
+// so it doesn't count toward the agent's or its controller's attacks this turn.
+func (d *Duel) undoAttack(attacker *CardInstance) {
+	if attacker.AttacksThisTurn > 0 {
+		attacker.AttacksThisTurn--
+		d.State.TotalAttacksDeclared[attacker.Controller]--
+	}
+	attacker.AttackedThisTurn = attacker.AttacksThisTurn > 0
+}
+
 // canDirectAttackWithDefenders checks if a agent can attack directly even when opponent has agents.
 func (d *Duel) canDirectAttackWithDefenders(agent *CardInstance) bool {
 	for _, eff := range agent.Card.Effects {
@@ -496,6 +688,18 @@ func (d *Duel) canDirectAttackWithDefenders(agent *CardInstance) bool {
 	return false
 }
 
+// applyBattleDamage applies battle damage unless the player is protected from it
+// this turn (Aegis Pulse). It reports whether the damage was dealt.
+func (d *Duel) applyBattleDamage(player int, amount int, reason string) bool {
+	gs := d.State
+	if gs.NoBattleDamage[player] {
+		d.log(log.NewDamageBlockedEvent(gs.Turn, gs.Phase.String(), player, amount, reason))
+		return false
+	}
+	d.applyDamage(player, amount, reason)
+	return true
+}
+
 // applyDamage reduces a player's HP and checks win conditions.
 func (d *Duel) applyDamage(player int, amount int, reason string) {
 	gs := d.State
@@ -507,13 +711,53 @@ func (d *Duel) applyDamage(player int, amount int, reason string) {
 		p.HP = 0
 	}
 
+	// A failsafe (Failsafe Circuit, etc.) turns a lethal hit into 1 HP, then is destroyed
+	var failsafe *CardInstance
+	if p.HP == 0 {
+		if failsafe = d.findFailsafe(player); failsafe != nil {
+			p.HP = 1
+		}
+	}
+
 	d.log(log.NewHPChangeEvent(gs.Turn, gs.Phase.String(), player, oldHP, p.HP, reason))
 
+	if failsafe != nil {
+		d.destroyByEffect(failsafe, failsafe, failsafe.Card.Name)
+	}
+
 	if gs.CheckWinCondition() {
 		d.log(log.NewWinEvent(gs.Turn, gs.Phase.String(), gs.Winner, gs.Result))
 	}
 }
 
+// gainHP raises a player's HP, clamped at the duel's MaxHP when one is set.
+func (d *Duel) gainHP(player int, amount int, reason string) {
+	gs := d.State
+	p := gs.Players[player]
+
+	oldHP := p.HP
+	p.HP += amount
+	if d.maxHP > 0 && p.HP > d.maxHP {
+		p.HP = max(oldHP, d.maxHP)
+	}
+	d.log(log.NewHPChangeEvent(gs.Turn, gs.Phase.String(), player, oldHP, p.HP, reason))
+}
+
+// findFailsafe returns a face-up card that prevents the player's lethal damage, or nil.
+func (d *Duel) findFailsafe(player int) *CardInstance {
+	for _, st := range d.State.Players[player].TechCards() {
+		if st.Face != FaceUp {
+			continue
+		}
+		for _, eff := range st.Card.Effects {
+			if eff.Failsafe != nil && eff.Failsafe(d, st, player) {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
 // applyEffectDamage reduces HP and also triggers Dark Room of Nightmare type effects.
 func (d *Duel) applyEffectDamage(player int, amount int, reason string) {
 	d.applyDamage(player, amount, reason)
@@ -552,15 +796,18 @@ func (d *Duel) destroyOS(player int) {
 	d.recalculateContinuousEffects()
 }
 
+// isNetGrid reports whether a card is "NetGrid" or treated as "NetGrid".
+func (d *Duel) isNetGrid(c *CardInstance) bool {
+	return d.hasName(c, "NetGrid")
+}
+
+// controlsNetGrid checks if the player controls a face-up "NetGrid" (or a card treated as "NetGrid").
+func (d *Duel) controlsNetGrid(player int) bool {
+	fs := d.State.Players[player].OS
+	return fs != nil && fs.Face == FaceUp && d.isNetGrid(fs)
+}
+
 // isNetGridOnField checks if "NetGrid" (or a card treated as "NetGrid") is face-up on the field.
 func (d *Duel) isNetGridOnField() bool {
-	gs := d.State
-	for p := 0; p < 2; p++ {
-		if fs := gs.Players[p].OS; fs != nil && fs.Face == FaceUp {
-			if fs.Card.Name == "NetGrid" || fs.Card.Name == "The Undercity Grid" {
-				return true
-			}
-		}
-	}
-	return false
+	return d.controlsNetGrid(0) || d.controlsNetGrid(1)
 }
@@ -9,8 +9,9 @@ import (
 // GreedProtocol — SS1 Normal Program. Draw 2 cards.
 func GreedProtocol() *Card {
 	eff := &CardEffect{
-		Name:      "Greed Protocol",
-		ExecSpeed: ExecSpeed1,
+		Name:            "Greed Protocol",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
 			return d.State.Players[player].DeckCount() >= 2
 		},
@@ -50,7 +51,7 @@ func VoidPurge() *Card {
 			return false
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			d.destroyAllAgents("Void Purge")
+			d.destroyAllAgents(card, "Void Purge")
 			return nil
 		},
 	}
@@ -85,7 +86,7 @@ func EMPCascade() *Card {
 			for p := 0; p < 2; p++ {
 				for _, st := range gs.Players[p].TechCards() {
 					if st.ID != card.ID {
-						d.destroyByEffect(st, "EMP Cascade")
+						d.destroyByEffect(st, card, "EMP Cascade")
 					}
 				}
 			}
@@ -110,7 +111,7 @@ func ICEBreaker() *Card {
 			// Must be a Tech on field to target (not itself)
 			for p := 0; p < 2; p++ {
 				for _, st := range d.State.Players[p].TechCards() {
-					if st.ID != card.ID {
+					if st.ID != card.ID && d.canTarget(card, st) {
 						return true
 					}
 				}
@@ -126,6 +127,7 @@ func ICEBreaker() *Card {
 					}
 				}
 			}
+			candidates = d.targetableBy(card, candidates)
 			if len(candidates) == 0 {
 				return nil, nil
 			}
@@ -140,7 +142,7 @@ func ICEBreaker() *Card {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			for _, t := range targets {
 				if d.isOnField(t) {
-					d.destroyByEffect(t, "ICE Breaker")
+					d.destroyByEffect(t, card, "ICE Breaker")
 				}
 			}
 			return nil
@@ -215,7 +217,7 @@ func ReactivePlating() *Card {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			gs := d.State
 			if gs.CurrentAttacker != nil && d.isOnField(gs.CurrentAttacker) {
-				d.destroyByEffect(gs.CurrentAttacker, "Reactive Plating")
+				d.destroyByEffect(gs.CurrentAttacker, card, "Reactive Plating")
 			}
 			return nil
 		},
@@ -245,7 +247,7 @@ func ReflectorArray() *Card {
 			gs := d.State
 			opp := gs.Opponent(player)
 			for _, m := range gs.Players[opp].FaceUpATKAgents() {
-				d.destroyByEffect(m, "Reflector Array")
+				d.destroyByEffect(m, card, "Reflector Array")
 			}
 			return nil
 		},
@@ -262,20 +264,11 @@ func ReflectorArray() *Card {
 // CascadeFailure — SS2 Normal Trap. When a agent is summoned: destroy all agents.
 func CascadeFailure() *Card {
 	eff := &CardEffect{
-		Name:         "Cascade Failure",
-		ExecSpeed:    ExecSpeed2,
-		IsTrigger:    true,
-		TriggerEvent: log.EventNormalSummon,
-		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
-			// Can activate when any agent is summoned (tracked by LastSummonEvent)
-			return d.State.LastSummonEvent != nil
-		},
-		TriggerFilter: func(d *Duel, card *CardInstance, event log.GameEvent) bool {
-			return event.Type == log.EventNormalSummon || event.Type == log.EventSacrificeSummon ||
-				event.Type == log.EventFlipSummon || event.Type == log.EventSpecialSummon
-		},
+		Name:      "Cascade Failure",
+		ExecSpeed: ExecSpeed2,
+		Trigger:   &Trigger{On: SummonEvents, Window: WindowSerialization},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			d.destroyAllAgents("Cascade Failure")
+			d.destroyAllAgents(card, "Cascade Failure")
 			return nil
 		},
 	}
@@ -295,7 +288,7 @@ func SelfDestructCircuit() *Card {
 		ExecSpeed: ExecSpeed2,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
 			for p := 0; p < 2; p++ {
-				if len(d.State.Players[p].FaceUpAgents()) > 0 {
+				if len(d.targetableBy(card, d.State.Players[p].FaceUpAgents())) > 0 {
 					return true
 				}
 			}
@@ -304,7 +297,7 @@ func SelfDestructCircuit() *Card {
 		Target: func(d *Duel, card *CardInstance, player int) ([]*CardInstance, error) {
 			var candidates []*CardInstance
 			for p := 0; p < 2; p++ {
-				candidates = append(candidates, d.State.Players[p].FaceUpAgents()...)
+				candidates = append(candidates, d.targetableBy(card, d.State.Players[p].FaceUpAgents())...)
 			}
 			chosen, err := d.Controllers[player].ChooseCards(
 				d.ctx, d.State, "Choose 1 face-up agent to destroy", candidates, 1, 1,
@@ -318,7 +311,7 @@ func SelfDestructCircuit() *Card {
 			for _, t := range targets {
 				if d.isOnField(t) {
 					atk := t.CurrentATK()
-					d.destroyByEffect(t, "Self-Destruct Circuit")
+					d.destroyByEffect(t, card, "Self-Destruct Circuit")
 					// Both players take damage equal to its ATK
 					// Goat format: turn player takes damage first
 					gs := d.State
@@ -369,9 +362,7 @@ func RootOverride() *Card {
 			if cost <= 0 {
 				return false, nil
 			}
-			oldHP := p.HP
-			p.HP -= cost
-			d.log(log.NewHPChangeEvent(gs.Turn, gs.Phase.String(), player, oldHP, p.HP, "Root Override cost"))
+			d.payHP(player, cost, card)
 			return true, nil
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
@@ -381,30 +372,14 @@ func RootOverride() *Card {
 			// so we need a different approach)
 			// Actually in LIFO, Solemn resolves FIRST (it's higher CL).
 			// So we need to mark the negated link. For simplicity, we destroy the CL1 card.
-			gs := d.State
-			if gs.Chain != nil && len(gs.Chain.Links) > 0 {
-				// Find the link we're negating (the one below us)
-				myIndex := -1
-				for i, link := range gs.Chain.Links {
-					if link.Card.ID == card.ID {
-						myIndex = i
-						break
-					}
-				}
-				if myIndex > 0 {
-					negated := gs.Chain.Links[myIndex-1]
-					// Destroy the negated card
-					if d.isOnField(negated.Card) {
-						d.destroyByEffect(negated.Card, "negated by Root Override")
-					}
-					// Mark the link as negated by nilling out its resolve
-					gs.Chain.Links[myIndex-1].Effect = &CardEffect{
-						Name: "Negated",
-						Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-							return nil // does nothing
-						},
-					}
+			// Find the link we're negating (the one below us)
+			if myIndex := d.chainIndexOf(card); myIndex > 0 {
+				negated := d.State.Chain.Links[myIndex-1]
+				// Destroy the negated card
+				if d.isOnField(negated.Card) {
+					d.destroyByEffect(negated.Card, card, "negated by Root Override")
 				}
+				d.negateChainLink(myIndex - 1)
 			}
 			return nil
 		},
@@ -470,7 +445,7 @@ func BreakerTheChromeWarrior() *Card {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			for _, t := range targets {
 				if d.isOnField(t) {
-					d.destroyByEffect(t, "Breaker the Chrome Warrior")
+					d.destroyByEffect(t, card, "Breaker the Chrome Warrior")
 				}
 			}
 			return nil
@@ -518,9 +493,7 @@ func PolymorphicVirus() *Card {
 			if err != nil {
 				return false, err
 			}
-			p.RemoveFromHand(chosen[0])
-			p.SendToScrapheap(chosen[0])
-			d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), player, chosen[0].Card.Name))
+			d.discardAsCost(player, chosen, card)
 			return true, nil
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
@@ -535,11 +508,11 @@ func PolymorphicVirus() *Card {
 			if err != nil {
 				return err
 			}
-			declaredType := chosen[0].Card.AgentType
+			declaredType := d.agentType(chosen[0])
 			for p := 0; p < 2; p++ {
 				for _, m := range d.State.Players[p].FaceUpAgents() {
-					if m.Card.AgentType == declaredType {
-						d.destroyByEffect(m, "Polymorphic Virus")
+					if d.agentType(m) == declaredType {
+						d.destroyByEffect(m, card, "Polymorphic Virus")
 					}
 				}
 			}
@@ -571,14 +544,11 @@ func RecursiveWorm() *Card {
 		IsMandatory:  false,
 		TriggerEvent: log.EventPhaseChange,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
-			return card.Zone == ZoneScrapheap && d.State.Phase == PhaseStandby && d.State.TurnPlayer == player
+			return card.Zone == ZoneScrapheap && d.State.Phase == PhaseStandby && d.State.TurnPlayer == player &&
+				d.canAddFromScrapheap(player)
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			gs := d.State
-			d.removeFromScrapheap(player, card)
-			card.Zone = ZoneHand
-			gs.Players[player].Hand = append(gs.Players[player].Hand, card)
-			d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), player, card.Card.Name, "Recursive Worm effect"))
+			d.addFromScrapheapToHand(player, card, "Recursive Worm effect")
 			return nil
 		},
 	}
@@ -606,6 +576,9 @@ func Datamancer() *Card {
 		IsTrigger:   true,
 		IsMandatory: false,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			if !d.canAddFromScrapheap(player) {
+				return false
+			}
 			for _, c := range d.State.Players[player].Scrapheap {
 				if c.Card.CardType == CardTypeProgram {
 					return true
@@ -623,21 +596,8 @@ func Datamancer() *Card {
 			return d.Controllers[player].ChooseCards(d.ctx, d.State, "Choose 1 Program from Scrapheap to add to hand", candidates, 1, 1)
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			gs := d.State
 			for _, t := range targets {
-				inScrapheap := false
-				for _, c := range gs.Players[player].Scrapheap {
-					if c.ID == t.ID {
-						inScrapheap = true
-						break
-					}
-				}
-				if inScrapheap {
-					d.removeFromScrapheap(player, t)
-					t.Zone = ZoneHand
-					gs.Players[player].Hand = append(gs.Players[player].Hand, t)
-					d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), player, t.Card.Name, "Datamancer"))
-				}
+				d.addFromScrapheapToHand(player, t, "Datamancer")
 			}
 			return nil
 		},
@@ -765,7 +725,6 @@ func ChromePaladinEnvoy() *Card {
 			if err != nil {
 				return false, err
 			}
-			d.purgeFromScrapheap(player, lightChosen[0], "Chrome Paladin cost")
 
 			var darkCandidates []*CardInstance
 			for _, c := range p.Scrapheap {
@@ -777,7 +736,7 @@ func ChromePaladinEnvoy() *Card {
 			if err != nil {
 				return false, err
 			}
-			d.purgeFromScrapheap(player, darkChosen[0], "Chrome Paladin cost")
+			d.purgeAsCost(player, append(lightChosen, darkChosen...), card)
 
 			return true, nil
 		},
@@ -788,13 +747,11 @@ func ChromePaladinEnvoy() *Card {
 	}
 
 	purgeEffect := &CardEffect{
-		Name:       "Chrome Paladin Purge",
-		ExecSpeed:  ExecSpeed1,
-		EffectType: EffectIgnition,
+		Name:        "Chrome Paladin Purge",
+		ExecSpeed:   ExecSpeed1,
+		EffectType:  EffectIgnition,
+		OncePerTurn: true,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
-			if card.Counters["bls_used"] > 0 {
-				return false
-			}
 			for p := 0; p < 2; p++ {
 				for _, m := range d.State.Players[p].Agents() {
 					if m.ID != card.ID {
@@ -816,7 +773,6 @@ func ChromePaladinEnvoy() *Card {
 			return d.Controllers[player].ChooseCards(d.ctx, d.State, "Choose 1 agent to purge", candidates, 1, 1)
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			card.Counters["bls_used"]++
 			card.AttackedThisTurn = true
 			for _, t := range targets {
 				if d.isOnField(t) {
@@ -872,15 +828,8 @@ func HostileTakeover() *Card {
 			d.attachEquip(card, target, 0, 0)
 			return nil
 		},
-		OnFieldEffect: func(d *Duel, card *CardInstance, player int) {
-			gs := d.State
-			if gs.Phase != PhaseStandby {
-				return
-			}
-			opp := gs.Opponent(card.Controller)
-			oldHP := gs.Players[opp].HP
-			gs.Players[opp].HP += 1000
-			d.log(log.NewHPChangeEvent(gs.Turn, gs.Phase.String(), opp, oldHP, gs.Players[opp].HP, "Hostile Takeover"))
+		OnStandby: func(d *Duel, card *CardInstance, player int) {
+			d.gainHP(d.State.Opponent(card.Controller), 1000, "Hostile Takeover")
 		},
 		OnLeaveField: func(d *Duel, card *CardInstance, player int) {
 			if card.EquippedTo != nil {
@@ -907,7 +856,7 @@ func EmergencyReboot() *Card {
 		Name:      "Emergency Reboot",
 		ExecSpeed: ExecSpeed1,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
-			if d.State.Players[player].HP <= 800 {
+			if d.State.Players[player].HP <= 800 || d.State.Players[player].FreeAgentZone() == -1 {
 				return false
 			}
 			for _, c := range d.State.Players[player].Scrapheap {
@@ -918,11 +867,7 @@ func EmergencyReboot() *Card {
 			return false
 		},
 		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
-			gs := d.State
-			p := gs.Players[player]
-			oldHP := p.HP
-			p.HP -= 800
-			d.log(log.NewHPChangeEvent(gs.Turn, gs.Phase.String(), player, oldHP, p.HP, "Emergency Reboot cost"))
+			d.payHP(player, 800, card)
 			return true, nil
 		},
 		Target: func(d *Duel, card *CardInstance, player int) ([]*CardInstance, error) {
@@ -946,7 +891,7 @@ func EmergencyReboot() *Card {
 					break
 				}
 			}
-			if !inScrapheap {
+			if !inScrapheap || d.State.Players[player].FreeAgentZone() == -1 {
 				return nil
 			}
 			d.removeFromScrapheap(player, target)
@@ -960,7 +905,7 @@ func EmergencyReboot() *Card {
 			if card.EquippedTo != nil {
 				target := card.EquippedTo
 				if d.isOnField(target) {
-					d.destroyByEffect(target, "Emergency Reboot destroyed")
+					d.destroyByEffect(target, card, "Emergency Reboot destroyed")
 				}
 			}
 		},
@@ -978,8 +923,9 @@ func EmergencyReboot() *Card {
 // NeuralSiphon — Normal Program: Draw 3, discard 2.
 func NeuralSiphon() *Card {
 	eff := &CardEffect{
-		Name:      "Neural Siphon",
-		ExecSpeed: ExecSpeed1,
+		Name:            "Neural Siphon",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
 			return d.State.Players[player].DeckCount() >= 3
 		},
@@ -1033,11 +979,7 @@ func MemoryCorruption() *Card {
 			return d.State.Players[player].HP > 1000 && len(d.State.Players[opp].Hand) > 0
 		},
 		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
-			gs := d.State
-			p := gs.Players[player]
-			oldHP := p.HP
-			p.HP -= 1000
-			d.log(log.NewHPChangeEvent(gs.Turn, gs.Phase.String(), player, oldHP, p.HP, "Memory Corruption cost"))
+			d.payHP(player, 1000, card)
 			return true, nil
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
@@ -1047,10 +989,7 @@ func MemoryCorruption() *Card {
 			if len(oppP.Hand) == 0 {
 				return nil
 			}
-			random := oppP.Hand[0]
-			oppP.RemoveFromHand(random)
-			oppP.SendToScrapheap(random)
-			d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), opp, random.Card.Name))
+			d.discardRandom(opp, "Memory Corruption")
 			if len(oppP.Hand) > 0 {
 				chosen, err := d.Controllers[opp].ChooseCards(d.ctx, gs, "Choose 1 card to discard", oppP.Hand, 1, 1)
 				if err != nil {
@@ -1083,7 +1022,7 @@ func TraceAndTerminate() *Card {
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
 			for p := 0; p < 2; p++ {
 				for _, m := range d.State.Players[p].AgentZones {
-					if m != nil && m.Face == FaceDown {
+					if m != nil && m.Face == FaceDown && d.canTarget(card, m) {
 						return true
 					}
 				}
@@ -1099,6 +1038,7 @@ func TraceAndTerminate() *Card {
 					}
 				}
 			}
+			candidates = d.targetableBy(card, candidates)
 			return d.Controllers[player].ChooseCards(d.ctx, d.State, "Choose 1 face-down agent to purge", candidates, 1, 1)
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
@@ -1160,7 +1100,7 @@ func ResurrectionProtocol() *Card {
 					break
 				}
 			}
-			if !inScrapheap {
+			if !inScrapheap || d.State.Players[player].FreeAgentZone() == -1 {
 				return nil
 			}
 			d.removeFromScrapheap(player, target)
@@ -1174,7 +1114,7 @@ func ResurrectionProtocol() *Card {
 			if card.EquippedTo != nil {
 				target := card.EquippedTo
 				if d.isOnField(target) {
-					d.destroyByEffect(target, "Resurrection Protocol destroyed")
+					d.destroyByEffect(target, card, "Resurrection Protocol destroyed")
 				}
 			}
 		},
@@ -1219,7 +1159,7 @@ func StaticDischarge() *Card {
 			gs := d.State
 			for _, t := range targets {
 				if d.isOnField(t) {
-					d.destroyByEffect(t, "Static Discharge")
+					d.destroyByEffect(t, card, "Static Discharge")
 				}
 			}
 			p := gs.Players[player]
@@ -1279,15 +1219,7 @@ func DecoyHolograms() *Card {
 				if zone == -1 {
 					break
 				}
-				token := gs.CreateCardInstance(&Card{
-					Name:      "Holo-Decoy Token",
-					CardType:  CardTypeAgent,
-					Level:     1,
-					Attribute: AttrEARTH,
-					AgentType: "Bioweapon",
-					ATK:       0,
-					DEF:       0,
-				}, player)
+				token := gs.CreateCardInstance(HoloDecoyToken(), player)
 				token.Face = FaceUp
 				token.Position = PositionDEF
 				token.TurnPlaced = gs.Turn
@@ -1309,6 +1241,20 @@ func DecoyHolograms() *Card {
 	}
 }
 
+// HoloDecoyToken — the token Decoy Holograms summons.
+func HoloDecoyToken() *Card {
+	return &Card{
+		Name:      "Holo-Decoy Token",
+		CardType:  CardTypeAgent,
+		Level:     1,
+		Attribute: AttrEARTH,
+		AgentType: "Bioweapon",
+		ATK:       0,
+		DEF:       0,
+		IsToken:   true,
+	}
+}
+
 // ======== Phase 4: New card implementations ========
 
 // --- Vanilla Agents ---
@@ -1394,20 +1340,8 @@ func HeadshotRoutine() *Card {
 			return false
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			// Find highest ATK among face-up ATK position agents
-			var highest []*CardInstance
-			maxATK := -1
-			for p := 0; p < 2; p++ {
-				for _, m := range d.State.Players[p].FaceUpATKAgents() {
-					atk := m.CurrentATK()
-					if atk > maxATK {
-						maxATK = atk
-						highest = []*CardInstance{m}
-					} else if atk == maxATK {
-						highest = append(highest, m)
-					}
-				}
-			}
+			// Ties include the activating player's own agents; they must still pick one
+			highest := d.highestATKAgents()
 			if len(highest) == 0 {
 				return nil
 			}
@@ -1422,7 +1356,7 @@ func HeadshotRoutine() *Card {
 				toDestroy = chosen[0]
 			}
 			if d.isOnField(toDestroy) {
-				d.destroyByEffect(toDestroy, "Headshot Routine")
+				d.destroyByEffect(toDestroy, card, "Headshot Routine")
 			}
 			return nil
 		},
@@ -1492,9 +1426,7 @@ func FlatlineCommand() *Card {
 			if err != nil {
 				return false, err
 			}
-			p.RemoveFromHand(chosen[0])
-			p.SendToScrapheap(chosen[0])
-			d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), player, chosen[0].Card.Name))
+			d.discardAsCost(player, chosen, card)
 			return true, nil
 		},
 		Target: func(d *Duel, card *CardInstance, player int) ([]*CardInstance, error) {
@@ -1507,7 +1439,7 @@ func FlatlineCommand() *Card {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			for _, t := range targets {
 				if d.isOnField(t) {
-					d.destroyByEffect(t, "Flatline Command")
+					d.destroyByEffect(t, card, "Flatline Command")
 				}
 			}
 			return nil
@@ -1528,6 +1460,9 @@ func ScrapheapRecovery() *Card {
 		Name:      "Scrapheap Recovery",
 		ExecSpeed: ExecSpeed1,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			if !d.canAddFromScrapheap(player) {
+				return false
+			}
 			count := 0
 			for _, c := range d.State.Players[player].Scrapheap {
 				if c.Card.CardType == CardTypeAgent && c.Card.Attribute == AttrWATER && c.Card.ATK <= 1500 {
@@ -1546,21 +1481,8 @@ func ScrapheapRecovery() *Card {
 			return d.Controllers[player].ChooseCards(d.ctx, d.State, "Choose 2 WATER agents to add to hand", candidates, 2, 2)
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			gs := d.State
 			for _, t := range targets {
-				inScrapheap := false
-				for _, c := range gs.Players[player].Scrapheap {
-					if c.ID == t.ID {
-						inScrapheap = true
-						break
-					}
-				}
-				if inScrapheap {
-					d.removeFromScrapheap(player, t)
-					t.Zone = ZoneHand
-					gs.Players[player].Hand = append(gs.Players[player].Hand, t)
-					d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), player, t.Card.Name, "Scrapheap Recovery"))
-				}
+				d.addFromScrapheapToHand(player, t, "Scrapheap Recovery")
 			}
 			return nil
 		},
@@ -1577,8 +1499,9 @@ func ScrapheapRecovery() *Card {
 // CoreDump — Quick-Play Program. Shuffle hand into deck, draw same number.
 func CoreDump() *Card {
 	eff := &CardEffect{
-		Name:      "Core Dump",
-		ExecSpeed: ExecSpeed2,
+		Name:            "Core Dump",
+		ExecSpeed:       ExecSpeed2,
+		DrawsOrSearches: true,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
 			count := 0
 			for _, c := range d.State.Players[player].Hand {
@@ -1599,7 +1522,7 @@ func CoreDump() *Card {
 				c.Zone = ZoneDeck
 				p.Deck = append(p.Deck, c)
 			}
-			p.ShuffleDeck()
+			p.ShuffleDeck(d.rng)
 			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
 			// Draw same number
 			for i := 0; i < count; i++ {
@@ -1650,7 +1573,7 @@ func SurgeOverride() *Card {
 			count := 0
 			for _, m := range p.FaceUpAgents() {
 				if m.Card.Attribute == AttrWATER {
-					d.destroyByEffect(m, "Surge Override")
+					d.destroyByEffect(m, card, "Surge Override")
 					count++
 				}
 			}
@@ -1826,6 +1749,12 @@ func TheUndercityGrid() *Card {
 				}
 			}
 		},
+		LevelMod: func(d *Duel, card *CardInstance, target *CardInstance) int {
+			if target.Card.Attribute == AttrWATER {
+				return -1
+			}
+			return 0
+		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			d.recalculateContinuousEffects()
 			return nil
@@ -1833,6 +1762,7 @@ func TheUndercityGrid() *Card {
 	}
 	return &Card{
 		Name:        "The Undercity Grid",
+		AlsoNamed:   "NetGrid",
 		Description: "This card's name is also treated as \"NetGrid\". All WATER agents on the field gain 200 ATK and DEF. All WATER agents in your hand and on the field have their Level reduced by 1.",
 		CardType:    CardTypeProgram,
 		ProgramSub:  ProgramOS,
@@ -1864,6 +1794,7 @@ func TortureSubnet() *Card {
 }
 
 // SectorLockdownZoneB — Continuous Program. All face-up L4+ agents to DEF.
+// Agents it switched return to ATK Position when it leaves the field.
 func SectorLockdownZoneB() *Card {
 	eff := &CardEffect{
 		Name:       "Sector Lockdown - Zone B",
@@ -1875,6 +1806,22 @@ func SectorLockdownZoneB() *Card {
 				for _, m := range gs.Players[p].FaceUpAgents() {
 					if m.Card.Level >= 4 && m.Position == PositionATK {
 						m.Position = PositionDEF
+						m.Counters["lockdown_source"] = card.ID
+					}
+				}
+			}
+		},
+		OnLeaveField: func(d *Duel, card *CardInstance, player int) {
+			gs := d.State
+			for p := 0; p < 2; p++ {
+				for _, m := range gs.Players[p].FaceUpAgents() {
+					if m.Counters["lockdown_source"] != card.ID {
+						continue
+					}
+					delete(m.Counters, "lockdown_source")
+					if m.Position == PositionDEF {
+						m.Position = PositionATK
+						d.log(log.NewChangePositionEvent(gs.Turn, gs.Phase.String(), m.Controller, m.Card.Name, m.Position.String()))
 					}
 				}
 			}
@@ -1937,12 +1884,8 @@ func NeuralShackle() *Card {
 					if drawn != nil {
 						d.log(log.NewDrawEvent(gs.Turn, gs.Phase.String(), player, drawn.Card.Name))
 					}
-				} else if len(gs.Players[opp].Hand) > 0 {
-					// Random discard
-					c := gs.Players[opp].Hand[0]
-					gs.Players[opp].RemoveFromHand(c)
-					gs.Players[opp].SendToScrapheap(c)
-					d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), opp, c.Card.Name))
+				} else {
+					d.discardRandom(opp, "Neural Shackle")
 				}
 			}
 		},
@@ -2000,33 +1943,16 @@ func FirewallSentinel() *Card {
 			if err != nil {
 				return false, err
 			}
-			p.RemoveFromHand(chosen[0])
-			p.SendToScrapheap(chosen[0])
-			d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), player, chosen[0].Card.Name))
+			d.discardAsCost(player, chosen, card)
 			return true, nil
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-			gs := d.State
-			if gs.Chain != nil && len(gs.Chain.Links) > 0 {
-				myIndex := -1
-				for i, link := range gs.Chain.Links {
-					if link.Card.ID == card.ID {
-						myIndex = i
-						break
-					}
-				}
-				if myIndex > 0 {
-					negated := gs.Chain.Links[myIndex-1]
-					if d.isOnField(negated.Card) {
-						d.destroyByEffect(negated.Card, "negated by Firewall Sentinel")
-					}
-					gs.Chain.Links[myIndex-1].Effect = &CardEffect{
-						Name: "Negated",
-						Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
-							return nil
-						},
-					}
+			if myIndex := d.chainIndexOf(card); myIndex > 0 {
+				negated := d.State.Chain.Links[myIndex-1]
+				if d.isOnField(negated.Card) {
+					d.destroyByEffect(negated.Card, card, "negated by Firewall Sentinel")
 				}
+				d.negateChainLink(myIndex - 1)
 			}
 			return nil
 		},
@@ -2045,23 +1971,17 @@ func FirewallSentinel() *Card {
 // CounterHack — Continuous Trap. When a FIRE you control is destroyed, 500 damage to opponent.
 func CounterHack() *Card {
 	eff := &CardEffect{
-		Name:         "Counter-Hack",
-		ExecSpeed:    ExecSpeed2,
-		EffectType:   EffectTrigger,
-		IsTrigger:    true,
-		IsMandatory:  true,
-		TriggerEvent: log.EventDestroy,
-		TriggerFilter: func(d *Duel, card *CardInstance, event log.GameEvent) bool {
-			return event.Type == log.EventDestroy || event.Type == log.EventBattleDestroy
-		},
-		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
-			// Simplified: check recent events for FIRE agent destruction
-			// This is handled via OnFieldEffect approach instead
-			return false
+		Name:       "Counter-Hack",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectContinuous,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up
 		},
-		OnFieldEffect: func(d *Duel, card *CardInstance, player int) {
-			// Called after a FIRE agent owned by player is destroyed
-			// Actual trigger handled inline by the engine
+		OnAgentDestroyed: func(d *Duel, trap *CardInstance, destroyed *CardInstance, controller int) {
+			if controller != trap.Controller || destroyed.Card.Attribute != AttrFIRE {
+				return
+			}
+			d.applyEffectDamage(d.State.Opponent(controller), 500, "Counter-Hack")
 		},
 	}
 	return &Card{
@@ -2107,11 +2027,11 @@ func SurgeBarrier() *Card {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			return nil // stays face-up
 		},
-		// Surge Barrier is destroyed when NetGrid leaves the field
-		// We check this in recalculateContinuousEffects
-		OnFieldEffect: func(d *Duel, card *CardInstance, player int) {
+		// Surge Barrier is destroyed when NetGrid leaves the field; checked
+		// each Standby Phase
+		OnStandby: func(d *Duel, card *CardInstance, player int) {
 			if !d.isNetGridOnField() && card.Face == FaceUp {
-				d.destroyByEffect(card, "NetGrid left field")
+				d.destroyByEffect(card, card, "NetGrid left field")
 			}
 		},
 	}
@@ -2276,7 +2196,7 @@ func DenMotherUnit() *Card {
 				}
 			}
 			_ = d.executeSpecialSummon(chosen[0], player, PositionATK, FaceUp)
-			p.ShuffleDeck()
+			p.ShuffleDeck(d.rng)
 			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
 		},
 	}
@@ -2325,7 +2245,7 @@ func DroneCarrier() *Card {
 				}
 			}
 			_ = d.executeSpecialSummon(chosen[0], player, PositionATK, FaceUp)
-			p.ShuffleDeck()
+			p.ShuffleDeck(d.rng)
 			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
 		},
 	}
@@ -2379,7 +2299,7 @@ func FrostbiteTyrant() *Card {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			for _, t := range targets {
 				if d.isOnField(t) {
-					d.destroyByEffect(t, "Frostbite Tyrant")
+					d.destroyByEffect(t, card, "Frostbite Tyrant")
 				}
 			}
 			return nil
@@ -2418,15 +2338,10 @@ func PlasmaArcTyrant() *Card {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			gs := d.State
 			opp := gs.Opponent(player)
-			oppP := gs.Players[opp]
-			if len(oppP.Hand) == 0 {
+			c := d.discardRandom(opp, "Plasma Arc Tyrant")
+			if c == nil {
 				return nil
 			}
-			// Random discard (first card for determinism in tests)
-			c := oppP.Hand[0]
-			oppP.RemoveFromHand(c)
-			oppP.SendToScrapheap(c)
-			d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), opp, c.Card.Name))
 			// If agent, deal level*100 damage
 			if c.Card.CardType == CardTypeAgent {
 				dmg := c.Card.Level * 100
@@ -2480,7 +2395,7 @@ func ThermalSpike() *Card {
 			if err != nil {
 				return false, err
 			}
-			d.purgeFromScrapheap(player, chosen[0], "ThermalSpike cost")
+			d.purgeAsCost(player, chosen, card)
 			return true, nil
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
@@ -2540,9 +2455,7 @@ func FenrirMkII() *Card {
 			if err != nil {
 				return false, err
 			}
-			for _, c := range chosen {
-				d.purgeFromScrapheap(player, c, "FenrirMkII cost")
-			}
+			d.purgeAsCost(player, chosen, card)
 			return true, nil
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
@@ -2635,7 +2548,7 @@ func AbyssalCircuitLeviathan() *Card {
 			// Send Umi to Scrapheap
 			for p := 0; p < 2; p++ {
 				if fs := gs.Players[p].OS; fs != nil && fs.Face == FaceUp {
-					if fs.Card.Name == "NetGrid" || fs.Card.Name == "The Undercity Grid" {
+					if d.isNetGrid(fs) {
 						d.destroyOS(p)
 						break
 					}
@@ -2645,11 +2558,11 @@ func AbyssalCircuitLeviathan() *Card {
 			for p := 0; p < 2; p++ {
 				for _, m := range gs.Players[p].Agents() {
 					if m.ID != card.ID {
-						d.destroyByEffect(m, "Abyssal Circuit Leviathan")
+						d.destroyByEffect(m, card, "Abyssal Circuit Leviathan")
 					}
 				}
 				for _, st := range gs.Players[p].TechCards() {
-					d.destroyByEffect(st, "Abyssal Circuit Leviathan")
+					d.destroyByEffect(st, card, "Abyssal Circuit Leviathan")
 				}
 			}
 			return nil
@@ -2681,7 +2594,7 @@ func ChromeborneHydraNexus() *Card {
 				return false
 			}
 			for _, m := range d.State.Players[player].FaceUpAgents() {
-				if m.Card.Name == "Abyssal Circuit Leviathan" {
+				if d.hasName(m, "Abyssal Circuit Leviathan") {
 					return true
 				}
 			}
@@ -2691,7 +2604,7 @@ func ChromeborneHydraNexus() *Card {
 			gs := d.State
 			var candidates []*CardInstance
 			for _, m := range gs.Players[player].FaceUpAgents() {
-				if m.Card.Name == "Abyssal Circuit Leviathan" {
+				if d.hasName(m, "Abyssal Circuit Leviathan") {
 					candidates = append(candidates, m)
 				}
 			}
@@ -2724,7 +2637,7 @@ func ChromeborneHydraNexus() *Card {
 			// Send Umi to Scrapheap
 			for p := 0; p < 2; p++ {
 				if fs := gs.Players[p].OS; fs != nil && fs.Face == FaceUp {
-					if fs.Card.Name == "NetGrid" || fs.Card.Name == "The Undercity Grid" {
+					if d.isNetGrid(fs) {
 						d.destroyOS(p)
 						break
 					}
@@ -2734,11 +2647,11 @@ func ChromeborneHydraNexus() *Card {
 			for p := 0; p < 2; p++ {
 				for _, m := range gs.Players[p].Agents() {
 					if m.ID != card.ID {
-						d.destroyByEffect(m, "Chromeborne Hydra Nexus")
+						d.destroyByEffect(m, card, "Chromeborne Hydra Nexus")
 					}
 				}
 				for _, st := range gs.Players[p].TechCards() {
-					d.destroyByEffect(st, "Chromeborne Hydra Nexus")
+					d.destroyByEffect(st, card, "Chromeborne Hydra Nexus")
 				}
 				// Send hand to Scrapheap
 				for len(gs.Players[p].Hand) > 0 {
@@ -2769,10 +2682,10 @@ func ChromeborneHydraNexus() *Card {
 
 // StealthGlider — When normal summoned, no traps can be activated in response.
 func StealthGlider() *Card {
-	// Implementation note: This prevents traps from being activated in the post-summon
-	// response window. Simplified: it's a L3 1300/1200 beater. The trap suppression
-	// would require engine changes to the effect serialization/response window system.
-	// For now, just define the card stats.
+	eff := &CardEffect{
+		Name:            "Stealth Glider",
+		SummonLockTraps: true,
+	}
 	return &Card{
 		Name:        "Stealth Glider",
 		Description: "When this card is Normal Summoned: Your opponent cannot activate Trap cards in response to the Summon.",
@@ -2783,7 +2696,7 @@ func StealthGlider() *Card {
 		ATK:         1300,
 		DEF:         1200,
 		IsEffect:    true,
-		Effects:     []*CardEffect{},
+		Effects:     []*CardEffect{eff},
 	}
 }
 
@@ -2822,7 +2735,7 @@ func SolarFlareSerpent() *Card {
 		TargetRestriction: func(d *Duel, card *CardInstance, player int) bool {
 			// Can be attacked only if controller has no other Pyro
 			for _, m := range d.State.Players[player].FaceUpAgents() {
-				if m.ID != card.ID && m.Card.AgentType == "Burner" {
+				if m.ID != card.ID && d.agentType(m) == "Burner" {
 					return false // can't be attacked
 				}
 			}
@@ -2907,15 +2820,13 @@ func GhostProcess() *Card {
 // GaiaCoreTheVolatileSwarm — Sacrifice Pyros for +1000 ATK each. Piercing. Self-destruct at EP.
 func GaiaCoreTheVolatileSwarm() *Card {
 	sacrificeEff := &CardEffect{
-		Name:       "Gaia Core Sacrifice",
-		ExecSpeed:  ExecSpeed1,
-		EffectType: EffectIgnition,
+		Name:        "Gaia Core Sacrifice",
+		ExecSpeed:   ExecSpeed1,
+		EffectType:  EffectIgnition,
+		OncePerTurn: true,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
-			if card.Counters["gaia_used"] > 0 {
-				return false
-			}
 			for _, m := range d.State.Players[player].FaceUpAgents() {
-				if m.ID != card.ID && m.Card.AgentType == "Burner" {
+				if m.ID != card.ID && d.agentType(m) == "Burner" {
 					return true
 				}
 			}
@@ -2925,7 +2836,7 @@ func GaiaCoreTheVolatileSwarm() *Card {
 			gs := d.State
 			var candidates []*CardInstance
 			for _, m := range gs.Players[player].FaceUpAgents() {
-				if m.ID != card.ID && m.Card.AgentType == "Burner" {
+				if m.ID != card.ID && d.agentType(m) == "Burner" {
 					candidates = append(candidates, m)
 				}
 			}
@@ -2943,7 +2854,6 @@ func GaiaCoreTheVolatileSwarm() *Card {
 				d.log(log.NewSendToScrapheapEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name, "sacrificed for Gaia Core"))
 				card.AddModifier(StatModifier{Source: card.ID, ATKMod: 1000, Permanent: true})
 			}
-			card.Counters["gaia_used"] = 1
 			return nil
 		},
 	}
@@ -2965,7 +2875,7 @@ func GaiaCoreTheVolatileSwarm() *Card {
 		},
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			if d.isOnField(card) {
-				d.destroyByEffect(card, "Gaia Core self-destruct")
+				d.destroyByEffect(card, card, "Gaia Core self-destruct")
 			}
 			return nil
 		},
@@ -3041,9 +2951,10 @@ func UltimateStreetPunk() *Card {
 		},
 	}
 	ignEff := &CardEffect{
-		Name:       "Ultimate Street Punk Burn",
-		ExecSpeed:  ExecSpeed1,
-		EffectType: EffectIgnition,
+		Name:        "Ultimate Street Punk Burn",
+		ExecSpeed:   ExecSpeed1,
+		EffectType:  EffectIgnition,
+		OncePerTurn: true,
 		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
 			for _, m := range d.State.Players[player].FaceUpAgents() {
 				if m.ID != card.ID && m.Card.Attribute == AttrFIRE {
@@ -3088,8 +2999,15 @@ func UltimateStreetPunk() *Card {
 
 // JunkyardLurker — Counts as 2 sacrifices for a WATER agent.
 func JunkyardLurker() *Card {
-	// Implementation note: this would require engine changes to the sacrifice system
-	// to allow a single agent to count as 2 sacrifices. For now, define the card.
+	eff := &CardEffect{
+		Name: "Junkyard Lurker",
+		TributeValue: func(forCard *Card) int {
+			if forCard.Attribute == AttrWATER {
+				return 2
+			}
+			return 1
+		},
+	}
 	return &Card{
 		Name:        "Junkyard Lurker",
 		Description: "You can Tribute this card to Tribute Summon 1 WATER agent. This card counts as 2 Tributes for the Tribute Summon of a WATER agent.",
@@ -3100,7 +3018,7 @@ func JunkyardLurker() *Card {
 		ATK:         1500,
 		DEF:         1600,
 		IsEffect:    true,
-		Effects:     []*CardEffect{},
+		Effects:     []*CardEffect{eff},
 	}
 }
 
@@ -3163,7 +3081,7 @@ func ScorchedCircuitDespot() *Card {
 			}
 			for _, t := range toDestroy {
 				if d.isOnField(t) {
-					d.destroyByEffect(t, "Scorched Circuit Despot")
+					d.destroyByEffect(t, card, "Scorched Circuit Despot")
 				}
 			}
 			return nil
@@ -3182,3 +3100,1765 @@ func ScorchedCircuitDespot() *Card {
 		Effects:     []*CardEffect{eff},
 	}
 }
+
+// ======== Backlog card implementations ========
+
+// PolarityInvert — Quick-Play Program. Swap the ATK and DEF of all face-up agents until end of turn.
+func PolarityInvert() *Card {
+	eff := &CardEffect{
+		Name:      "Polarity Invert",
+		ExecSpeed: ExecSpeed2,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			for p := 0; p < 2; p++ {
+				if len(d.State.Players[p].FaceUpAgents()) > 0 {
+					return true
+				}
+			}
+			return false
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			for p := 0; p < 2; p++ {
+				for _, m := range gs.Players[p].FaceUpAgents() {
+					// Swapping the originals lets later stat changes apply to the
+					// swapped values; the End Phase cleanup swaps them back.
+					// Modifiers already applied stay on the stat they name.
+					m.StatsSwapped = !m.StatsSwapped
+				}
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Polarity Invert",
+		Description: "Swap the ATK and DEF of all face-up agents on the field until the end of this turn.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramQuickPlay,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// MimicFrame — Effect Agent. ATK becomes the highest ATK among other face-up agents.
+func MimicFrame() *Card {
+	eff := &CardEffect{
+		Name:       "Mimic Frame Copy",
+		EffectType: EffectContinuous,
+		SetATK: func(d *Duel, card *CardInstance, player int) (int, bool) {
+			gs := d.State
+			highest, found := 0, false
+			for p := 0; p < 2; p++ {
+				for _, m := range gs.Players[p].FaceUpAgents() {
+					if m.ID == card.ID {
+						continue // never copy itself
+					}
+					if !found || m.CurrentATK() > highest {
+						highest = m.CurrentATK()
+						found = true
+					}
+				}
+			}
+			return highest, found
+		},
+	}
+	return &Card{
+		Name:        "Mimic Frame",
+		Description: "This card's ATK becomes equal to the highest ATK among all other face-up agents on the field.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrDARK,
+		AgentType:   "Construct",
+		ATK:         1000,
+		DEF:         1000,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// ThornResponse — Normal Trap. When your agent is destroyed by battle: destroy the agent that destroyed it.
+func ThornResponse() *Card {
+	// destroyer returns the opponent's agent that destroyed one of player's agents by battle.
+	destroyer := func(d *Duel, card *CardInstance, player int) *CardInstance {
+		for _, m := range d.State.LastBattleDestroyed {
+			if m.Owner != player {
+				continue
+			}
+			opp := d.battleOpponent(m)
+			if opp != nil && opp.Controller != player && d.isOnField(opp) && !d.isUnaffectedBy(opp, card) {
+				return opp
+			}
+		}
+		return nil
+	}
+	eff := &CardEffect{
+		Name:         "Thorn Response",
+		ExecSpeed:    ExecSpeed2,
+		IsTrigger:    true,
+		TriggerEvent: log.EventBattleDestroy,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return destroyer(d, card, player) != nil
+		},
+		Target: func(d *Duel, card *CardInstance, player int) ([]*CardInstance, error) {
+			return []*CardInstance{destroyer(d, card, player)}, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			for _, t := range targets {
+				if d.isOnField(t) {
+					d.destroyByEffect(t, card, "Thorn Response")
+				}
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Thorn Response",
+		Description: "When an agent you control is destroyed by battle: Destroy the agent that destroyed it.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// TrapImmunityField — Continuous Program. Your agents are unaffected by your opponent's Trap effects.
+func TrapImmunityField() *Card {
+	eff := &CardEffect{
+		Name:       "Trap Immunity Field",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		UnaffectedBy: func(d *Duel, card *CardInstance, target *CardInstance, source *CardInstance) bool {
+			return target.Zone == ZoneAgent && target.Controller == card.Controller &&
+				source.Card.CardType == CardTypeTrap && source.Controller != card.Controller
+		},
+	}
+	return &Card{
+		Name:        "Trap Immunity Field",
+		Description: "Agents you control are unaffected by your opponent's Trap effects.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// EchoCaller — Effect Agent. Once per turn: purge 1 Normal Program from your Scrapheap to apply its effect.
+func EchoCaller() *Card {
+	// echoable returns the Normal Programs in player's scrapheap whose effect can be re-fired
+	// without a cost or targets.
+	echoable := func(d *Duel, player int) []*CardInstance {
+		var result []*CardInstance
+		for _, c := range d.State.Players[player].Scrapheap {
+			if c.Card.CardType != CardTypeProgram || c.Card.ProgramSub != ProgramNormal || len(c.Card.Effects) == 0 {
+				continue
+			}
+			eff := c.Card.Effects[0]
+			if eff.Resolve == nil || eff.Cost != nil || eff.Target != nil {
+				continue
+			}
+			if eff.CanActivate != nil && !eff.CanActivate(d, c, player) {
+				continue
+			}
+			result = append(result, c)
+		}
+		return result
+	}
+	eff := &CardEffect{
+		Name:        "Echo Caller Recall",
+		ExecSpeed:   ExecSpeed1,
+		EffectType:  EffectIgnition,
+		OncePerTurn: true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return len(echoable(d, player)) > 0
+		},
+		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, d.State, "Choose 1 Normal Program to purge", echoable(d, player), 1, 1)
+			if err != nil {
+				return false, err
+			}
+			if len(chosen) == 0 {
+				return false, nil
+			}
+			d.purgeAsCost(player, chosen, card)
+			card.Counters["echo_source"] = chosen[0].ID
+			return true, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			for _, c := range d.State.Players[player].Purged {
+				if c.ID == card.Counters["echo_source"] {
+					return c.Card.Effects[0].Resolve(d, c, player, nil)
+				}
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Echo Caller",
+		Description: "Once per turn: You can purge 1 Normal Program from your Scrapheap; apply that card's effect.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrDARK,
+		AgentType:   "Hacker",
+		ATK:         1400,
+		DEF:         1200,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// EntropyField — Continuous Trap. Opponent's agents lose 100 ATK per card in your Scrapheap.
+func EntropyField() *Card {
+	eff := &CardEffect{
+		Name:       "Entropy Field",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectContinuous,
+		ContinuousApply: func(d *Duel, card *CardInstance, player int) {
+			gs := d.State
+			debuff := 100 * len(gs.Players[player].Scrapheap)
+			if debuff == 0 {
+				return
+			}
+			for _, m := range gs.Players[gs.Opponent(player)].FaceUpAgents() {
+				m.AddModifier(StatModifier{Source: card.ID, ATKMod: -debuff, Continuous: true})
+			}
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up; effect applied via ContinuousApply
+		},
+	}
+	return &Card{
+		Name:        "Entropy Field",
+		Description: "All agents your opponent controls lose 100 ATK for each card in your Scrapheap.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// FullRollback — Normal Program. Return all agents on the field to their owners' hands.
+func FullRollback() *Card {
+	eff := &CardEffect{
+		Name:      "Full Rollback",
+		ExecSpeed: ExecSpeed1,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			for p := 0; p < 2; p++ {
+				if d.State.Players[p].AgentCount() > 0 {
+					return true
+				}
+			}
+			return false
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			// Snapshot first: returning cards (and their equips) mutates the zones
+			var agents []*CardInstance
+			for p := 0; p < 2; p++ {
+				agents = append(agents, d.State.Players[p].Agents()...)
+			}
+			for _, m := range agents {
+				if d.isOnField(m) {
+					d.returnToHand(m, "Full Rollback")
+				}
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Full Rollback",
+		Description: "Return all agents on the field to their owners' hands.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// AggroBeacon — While face-up in ATK position, your opponent's attacks must target this card.
+func AggroBeacon() *Card {
+	eff := &CardEffect{
+		Name:       "Aggro Beacon Taunt",
+		EffectType: EffectContinuous,
+		Taunt: func(d *Duel, card *CardInstance, player int) bool {
+			return card.Face == FaceUp && card.Position == PositionATK
+		},
+	}
+	return &Card{
+		Name:        "Aggro Beacon",
+		Description: "While this card is face-up in ATK position, your opponent's attacks must target this card, if able.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrLIGHT,
+		AgentType:   "Machine",
+		ATK:         1800,
+		DEF:         1000,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// DataTrade — Normal Program. Discard up to 3 cards, then draw that many cards.
+func DataTrade() *Card {
+	eff := &CardEffect{
+		Name:            "Data Trade",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return len(d.State.Players[player].Hand) >= 2 // need 1 card besides this program
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			max := 3
+			if len(p.Hand) < max {
+				max = len(p.Hand)
+			}
+			if max == 0 {
+				return nil
+			}
+			toDiscard, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose up to 3 cards to discard", p.Hand, 0, max)
+			if err != nil {
+				return err
+			}
+			for _, c := range toDiscard {
+				p.RemoveFromHand(c)
+				p.SendToScrapheap(c)
+				d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name))
+			}
+			d.drawForEffect(player, len(toDiscard))
+			d.recalculateContinuousEffects()
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Data Trade",
+		Description: "Discard up to 3 cards, then draw the same number of cards.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// ScalingMatrix — Operating System. All agents on the field gain ATK equal to their Level × 100.
+func ScalingMatrix() *Card {
+	eff := &CardEffect{
+		Name:       "Scaling Matrix",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		ContinuousApply: func(d *Duel, card *CardInstance, player int) {
+			gs := d.State
+			for p := 0; p < 2; p++ {
+				for _, m := range gs.Players[p].FaceUpAgents() {
+					m.AddModifier(StatModifier{
+						Source:     card.ID,
+						ATKMod:     100 * d.currentLevel(m),
+						Continuous: true,
+					})
+				}
+			}
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.recalculateContinuousEffects()
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Scaling Matrix",
+		Description: "All agents on the field gain ATK equal to their Level x 100.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramOS,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// FailsafeCircuit — Continuous Trap. Once, when you would take lethal damage, your HP becomes 1 instead; then destroy this card.
+func FailsafeCircuit() *Card {
+	eff := &CardEffect{
+		Name:       "Failsafe Circuit",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectContinuous,
+		Failsafe: func(d *Duel, card *CardInstance, player int) bool {
+			return true
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up; checked by applyDamage
+		},
+	}
+	return &Card{
+		Name:        "Failsafe Circuit",
+		Description: "If you would take damage that reduces your HP to 0 while this card is face-up, your HP becomes 1 instead, then destroy this card.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// ForcedDisclosure — Normal Program. Your opponent reveals their hand; you choose 1 card for them to discard.
+func ForcedDisclosure() *Card {
+	eff := &CardEffect{
+		Name:      "Forced Disclosure",
+		ExecSpeed: ExecSpeed1,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			opp := d.State.Opponent(player)
+			return len(d.State.Players[opp].Hand) > 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			opp := gs.Opponent(player)
+			oppP := gs.Players[opp]
+			if len(oppP.Hand) == 0 {
+				return nil
+			}
+			var names []string
+			for _, c := range oppP.Hand {
+				names = append(names, c.Card.Name)
+			}
+			gs.RevealedHands[opp] = true
+			defer func() { gs.RevealedHands[opp] = false }()
+			d.log(log.NewRevealHandEvent(gs.Turn, gs.Phase.String(), opp, names))
+
+			// The activating player, not the opponent, picks the discard
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose 1 card for your opponent to discard", oppP.Hand, 1, 1)
+			if err != nil {
+				return err
+			}
+			for _, c := range chosen {
+				oppP.RemoveFromHand(c)
+				oppP.SendToScrapheap(c)
+				d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), opp, c.Card.Name))
+			}
+			d.recalculateContinuousEffects()
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Forced Disclosure",
+		Description: "Your opponent reveals their hand. Choose 1 card from it; your opponent discards that card.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// GridLink — Normal Program. Activate only while you control "NetGrid". Add 1 card from your Deck to your hand.
+func GridLink() *Card {
+	eff := &CardEffect{
+		Name:            "Grid Link",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.controlsNetGrid(player) && d.State.Players[player].DeckCount() > 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			if len(p.Deck) == 0 {
+				return nil
+			}
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose 1 card to add to your hand", p.Deck, 1, 1)
+			if err != nil {
+				return err
+			}
+			for _, c := range chosen {
+				p.RemoveFromDeck(c)
+				c.Zone = ZoneHand
+				p.Hand = append(p.Hand, c)
+				d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name, "Grid Link"))
+			}
+			p.ShuffleDeck(d.rng)
+			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Grid Link",
+		Description: "Activate only while you control \"NetGrid\". Add 1 card from your Deck to your hand.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// OverrunProtocol — Continuous Program. When an agent you control destroys an ATK position agent by battle, inflict the overkill again as follow-through damage.
+func OverrunProtocol() *Card {
+	eff := &CardEffect{
+		Name:       "Overrun Protocol",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		Overrun: func(d *Duel, card *CardInstance, attacker *CardInstance) bool {
+			return attacker.Controller == card.Controller
+		},
+	}
+	return &Card{
+		Name:        "Overrun Protocol",
+		Description: "When an agent you control destroys an ATK position agent by battle, inflict damage to your opponent equal to the battle damage dealt.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// OptimizerSprite — Effect Agent. You pay 500 less HP for the costs of your effects.
+func OptimizerSprite() *Card {
+	eff := &CardEffect{
+		Name:       "Optimizer Sprite Discount",
+		EffectType: EffectContinuous,
+		HPCostDiscount: func(d *Duel, card *CardInstance, player int, source *CardInstance) int {
+			if player == card.Controller {
+				return 500
+			}
+			return 0
+		},
+	}
+	return &Card{
+		Name:        "Optimizer Sprite",
+		Description: "While this card is face-up on the field, you pay 500 less HP for the costs of your effects.",
+		CardType:    CardTypeAgent,
+		Level:       3,
+		Attribute:   AttrWIND,
+		AgentType:   "Hacker",
+		ATK:         800,
+		DEF:         1200,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// ScrapheapFirewall — Continuous Trap. Your opponent cannot add cards from their Scrapheap to their hand.
+func ScrapheapFirewall() *Card {
+	eff := &CardEffect{
+		Name:       "Scrapheap Firewall",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectContinuous,
+		BlocksScrapheapToHand: func(d *Duel, card *CardInstance, player int) bool {
+			return player != card.Controller
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up; checked by addFromScrapheapToHand
+		},
+	}
+	return &Card{
+		Name:        "Scrapheap Firewall",
+		Description: "While this card is face-up, your opponent cannot add cards from their Scrapheap to their hand.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// HotSwap — Normal Program. Choose 1 card in hand, draw 1 card, then place the chosen card on top of your Deck.
+func HotSwap() *Card {
+	eff := &CardEffect{
+		Name:            "Hot Swap",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			p := d.State.Players[player]
+			return len(p.Hand) >= 2 && p.DeckCount() > 0 // need 1 card to swap besides this program
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			if len(p.Hand) == 0 {
+				return nil
+			}
+			// Choose before drawing, so the swapped-in card can't be sent straight back
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose 1 card to place on top of your Deck", p.Hand, 1, 1)
+			if err != nil {
+				return err
+			}
+			if !d.drawForEffect(player, 1) {
+				return nil
+			}
+			for _, c := range chosen {
+				p.RemoveFromHand(c)
+				p.PlaceOnDeckTop(c)
+				d.log(log.NewReturnToDeckTopEvent(gs.Turn, gs.Phase.String(), player, "Hot Swap"))
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Hot Swap",
+		Description: "Choose 1 card in your hand. Draw 1 card, then place the chosen card on top of your Deck.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// SummonTax — Continuous Trap. Each time a player summons their second or later agent in a turn, they take 500 damage.
+func SummonTax() *Card {
+	eff := &CardEffect{
+		Name:       "Summon Tax",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectContinuous,
+		OnSummon: func(d *Duel, card *CardInstance, summoned *CardInstance, player int) {
+			if d.State.AgentsSummonedThisTurn[player] >= 2 {
+				d.applyEffectDamage(player, 500, "Summon Tax")
+			}
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up; applied by recordSummon
+		},
+	}
+	return &Card{
+		Name:        "Summon Tax",
+		Description: "Each time a player summons their second or later agent in a turn, they take 500 damage.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// TotalLockout — SS3 Counter Trap. Negate the activations of every card and effect already on the chain.
+func TotalLockout() *Card {
+	eff := &CardEffect{
+		Name:      "Total Lockout",
+		ExecSpeed: ExecSpeed3,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			gs := d.State
+			return gs.Chain != nil && len(gs.Chain.Links) > 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			// Negate only: the negated cards are not destroyed
+			for i := d.chainIndexOf(card) - 1; i >= 0; i-- {
+				d.negateChainLink(i)
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Total Lockout",
+		Description: "When a card or effect is activated: Negate the activations of all cards and effects on the chain.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapCounter,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// PhoenixProcess — If this card would be destroyed, place it on top of its owner's Deck instead.
+func PhoenixProcess() *Card {
+	eff := &CardEffect{
+		Name:       "Phoenix Process Rebirth",
+		EffectType: EffectContinuous,
+		ReplacementDestination: func(d *Duel, card *CardInstance) (ZoneType, bool) {
+			return ZoneDeck, true
+		},
+	}
+	return &Card{
+		Name:        "Phoenix Process",
+		Description: "If this card would be destroyed, place it on top of its owner's Deck instead of sending it to the scrapheap.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrFIRE,
+		AgentType:   "Pyro",
+		ATK:         1600,
+		DEF:         1200,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// Recalibrate — Normal Program. Draw 2 cards, then place 1 of them on the top or bottom of your Deck.
+func Recalibrate() *Card {
+	eff := &CardEffect{
+		Name:            "Recalibrate",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.State.Players[player].DeckCount() >= 2
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			before := len(p.Hand)
+			if !d.drawForEffect(player, 2) {
+				return nil
+			}
+			drawn := append([]*CardInstance(nil), p.Hand[before:]...)
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose 1 drawn card to return to your Deck", drawn, 1, 1)
+			if err != nil {
+				return err
+			}
+			top, err := d.Controllers[player].ChooseYesNo(d.ctx, gs, "Recalibrate: Place it on top of your Deck? (No = bottom)")
+			if err != nil {
+				return err
+			}
+			for _, c := range chosen {
+				p.RemoveFromHand(c)
+				if top {
+					p.PlaceOnDeckTop(c)
+					d.log(log.NewReturnToDeckTopEvent(gs.Turn, gs.Phase.String(), player, "Recalibrate"))
+				} else {
+					p.PlaceOnDeckBottom(c)
+					d.log(log.NewReturnToDeckBottomEvent(gs.Turn, gs.Phase.String(), player, "Recalibrate"))
+				}
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Recalibrate",
+		Description: "Draw 2 cards, then place 1 of the cards drawn by this effect on the top or bottom of your Deck.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// EmergencyShields — Continuous Program. Once per turn, an agent you control is not destroyed by battle and you take no battle damage from that battle.
+func EmergencyShields() *Card {
+	eff := &CardEffect{
+		Name:       "Emergency Shields",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		BattleShield: func(d *Duel, card *CardInstance, agent *CardInstance) bool {
+			if agent.Controller != card.Controller || card.Counters["shield_turn"] == d.State.Turn {
+				return false
+			}
+			card.Counters["shield_turn"] = d.State.Turn
+			return true
+		},
+	}
+	return &Card{
+		Name:        "Emergency Shields",
+		Description: "Once per turn, when an agent you control would be destroyed by battle: It is not destroyed, and you take no battle damage from that battle.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// DataOverload — Continuous Trap. During each of your opponent's End Phases, inflict 100 damage to them for each card in their hand.
+func DataOverload() *Card {
+	eff := &CardEffect{
+		Name:       "Data Overload",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectContinuous,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up; burn applied by the End Phase effect
+		},
+	}
+	burnEff := &CardEffect{
+		Name:          "Data Overload Burn",
+		ExecSpeed:     ExecSpeed1,
+		EffectType:    EffectTrigger,
+		IsTrigger:     true,
+		IsMandatory:   true,
+		TriggerEvent:  log.EventPhaseChange,
+		OnFieldEffect: func(d *Duel, card *CardInstance, player int) {},
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.State.Phase == PhaseEnd && d.State.TurnPlayer != player
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			opp := d.State.Opponent(player)
+			if n := len(d.State.Players[opp].Hand); n > 0 {
+				d.applyEffectDamage(opp, 100*n, "Data Overload")
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Data Overload",
+		Description: "During each of your opponent's End Phases: Inflict 100 damage to your opponent for each card in their hand.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{eff, burnEff},
+	}
+}
+
+// EvolvingConstruct — Effect Agent. Gains an evolution counter each of your Standby Phases; with 3 or more it gains 1000 ATK and piercing.
+func EvolvingConstruct() *Card {
+	const threshold = 3
+	evolved := func(card *CardInstance) bool {
+		return card.Counters["evolution"] >= threshold
+	}
+	counterEff := &CardEffect{
+		Name:       "Evolving Construct Growth",
+		EffectType: EffectContinuous,
+		OnStandby: func(d *Duel, card *CardInstance, player int) {
+			if d.State.TurnPlayer != card.Controller {
+				return
+			}
+			card.Counters["evolution"]++
+			d.recalculateContinuousEffects()
+		},
+	}
+	evolvedEff := &CardEffect{
+		Name:       "Evolving Construct Evolution",
+		EffectType: EffectContinuous,
+		ContinuousApply: func(d *Duel, card *CardInstance, player int) {
+			if evolved(card) {
+				card.AddModifier(StatModifier{Source: card.ID, ATKMod: 1000, Continuous: true})
+			}
+		},
+		Piercing: func(d *Duel, card *CardInstance) bool {
+			return evolved(card)
+		},
+	}
+	return &Card{
+		Name:        "Evolving Construct",
+		Description: "During each of your Standby Phases, place 1 evolution counter on this card. While it has 3 or more, it gains 1000 ATK, and if it attacks a DEF position agent, inflict piercing battle damage.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrEARTH,
+		AgentType:   "Construct",
+		ATK:         1200,
+		DEF:         1000,
+		IsEffect:    true,
+		Effects:     []*CardEffect{counterEff, evolvedEff},
+	}
+}
+
+// FeedbackLoop — Normal Trap. Inflict 300 damage to your opponent for each chain link up to and including this card.
+func FeedbackLoop() *Card {
+	eff := &CardEffect{
+		Name:      "Feedback Loop",
+		ExecSpeed: ExecSpeed2,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.State.Chain != nil && len(d.State.Chain.Links) > 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			// Links above this one have already resolved and left the chain
+			links := d.chainIndexOf(card) + 1
+			if links > 0 {
+				d.applyEffectDamage(d.State.Opponent(player), 300*links, "Feedback Loop")
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Feedback Loop",
+		Description: "Activate only in response to a card or effect activation. Inflict 300 damage to your opponent for each Chain Link in the chain when this card resolves, including this card.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// RapidFabricator — Effect Agent. Special Summon this card from your Deck by skipping your next draw.
+func RapidFabricator() *Card {
+	eff := &CardEffect{
+		Name:                  "Rapid Fabricator Special Summon",
+		ExecSpeed:             ExecSpeed1,
+		EffectType:            EffectNone,
+		SpecialSummonFromDeck: true,
+		SpecialSummonCondition: func(d *Duel, card *CardInstance, player int) bool {
+			p := d.State.Players[player]
+			return !p.SkipNextDraw && p.FreeAgentZone() != -1
+		},
+		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
+			d.State.Players[player].SkipNextDraw = true
+			return true, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			if card.Zone != ZoneDeck || p.FreeAgentZone() == -1 {
+				return nil
+			}
+			p.RemoveFromDeck(card)
+			p.ShuffleDeck(d.rng)
+			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
+			return d.executeSpecialSummon(card, player, PositionATK, FaceUp)
+		},
+	}
+	return &Card{
+		Name:        "Rapid Fabricator",
+		Description: "You can Special Summon this card from your Deck by skipping your next Draw Phase draw.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrEARTH,
+		AgentType:   "Machine",
+		ATK:         1400,
+		DEF:         1000,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// BodyguardSplice — Effect Agent. Hand trap: discard it to end an opponent's attack, then Special Summon it in DEF.
+func BodyguardSplice() *Card {
+	eff := &CardEffect{
+		Name:       "Bodyguard Splice Intercept",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectQuick,
+		HandTrap:   true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			gs := d.State
+			return card.Zone == ZoneHand &&
+				gs.CurrentAttacker != nil && gs.CurrentAttacker.Controller != player &&
+				gs.Players[player].FreeAgentZone() != -1
+		},
+		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
+			d.discardAsCost(player, []*CardInstance{card}, card)
+			return true, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.negateAttack()
+			if card.Zone != ZoneScrapheap || d.State.Players[player].FreeAgentZone() == -1 {
+				return nil
+			}
+			d.removeFromScrapheap(player, card)
+			return d.executeSpecialSummon(card, player, PositionDEF, FaceUp)
+		},
+	}
+	return &Card{
+		Name:        "Bodyguard Splice",
+		Description: "When your opponent declares an attack: You can discard this card; end that attack, then Special Summon this card from your Scrapheap in Defense Position.",
+		CardType:    CardTypeAgent,
+		Level:       3,
+		Attribute:   AttrEARTH,
+		AgentType:   "Machine",
+		ATK:         600,
+		DEF:         1800,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// CloakGrid — Operating System. Your opponent's effects can't target your face-down cards.
+func CloakGrid() *Card {
+	eff := &CardEffect{
+		Name:       "Cloak Grid",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		Untargetable: func(d *Duel, card *CardInstance, target *CardInstance, source *CardInstance) bool {
+			return target.Face == FaceDown && target.Controller == card.Controller && source.Controller != card.Controller
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up; protects set cards while active
+		},
+	}
+	return &Card{
+		Name:        "Cloak Grid",
+		Description: "Your opponent cannot target face-down cards you control with card effects.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramOS,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// MedPatch — Normal Program. Gain 1000 HP.
+func MedPatch() *Card {
+	eff := &CardEffect{
+		Name:      "Med-Patch",
+		ExecSpeed: ExecSpeed1,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.gainHP(player, 1000, "Med-Patch")
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Med-Patch",
+		Description: "Gain 1000 HP.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// QuantumDraw — Normal Program. Reveal your top card; draw 1, 2 or 3 cards by its Level, then shuffle it back.
+func QuantumDraw() *Card {
+	eff := &CardEffect{
+		Name:            "Quantum Draw",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.State.Players[player].DeckCount() >= 2
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			revealed := p.PeekDeck()
+			if revealed == nil {
+				return nil
+			}
+			d.log(log.NewRevealCardEvent(gs.Turn, gs.Phase.String(), player, revealed.Card.Name, "the top of their Deck"))
+
+			// Set the revealed card aside while drawing; non-agents count as Level 0
+			p.RemoveFromDeck(revealed)
+			n := 1
+			switch level := revealed.Card.Level; {
+			case level >= 7:
+				n = 3
+			case level >= 5:
+				n = 2
+			}
+			d.drawForEffect(player, n)
+
+			p.PlaceOnDeckTop(revealed)
+			p.ShuffleDeck(d.rng)
+			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Quantum Draw",
+		Description: "Reveal the top card of your Deck. Draw 1 card if it is Level 4 or lower, 2 if it is Level 5 or 6, or 3 if it is Level 7 or higher. Then shuffle the revealed card back into your Deck.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// KnockdownPulse — Quick-Play Program. Target 1 face-up ATK agent; change it to DEF Position.
+func KnockdownPulse() *Card {
+	eff := &CardEffect{
+		Name:      "Knockdown Pulse",
+		ExecSpeed: ExecSpeed2,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			for p := 0; p < 2; p++ {
+				if len(d.targetableBy(card, d.State.Players[p].FaceUpATKAgents())) > 0 {
+					return true
+				}
+			}
+			return false
+		},
+		Target: func(d *Duel, card *CardInstance, player int) ([]*CardInstance, error) {
+			var candidates []*CardInstance
+			for p := 0; p < 2; p++ {
+				candidates = append(candidates, d.targetableBy(card, d.State.Players[p].FaceUpATKAgents())...)
+			}
+			return d.Controllers[player].ChooseCards(d.ctx, d.State, "Choose 1 face-up ATK agent to change to DEF", candidates, 1, 1)
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			for _, t := range targets {
+				if d.isOnField(t) && t.Face == FaceUp {
+					d.forcePosition(t, PositionDEF)
+				}
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Knockdown Pulse",
+		Description: "Target 1 face-up Attack Position agent on the field; change that target to Defense Position.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramQuickPlay,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// SacrificialNode — Effect Agent. When sacrificed for a Sacrifice Summon or Set, its controller draws 1 card.
+func SacrificialNode() *Card {
+	eff := &CardEffect{
+		Name:      "Sacrificial Node Draw",
+		ExecSpeed: ExecSpeed1,
+		OnUsedAsTribute: func(d *Duel, card *CardInstance, controller int) {
+			gs := d.State
+			d.log(log.NewActivateEvent(gs.Turn, gs.Phase.String(), controller, card.Card.Name+" effect"))
+			d.drawForEffect(controller, 1)
+		},
+	}
+	return &Card{
+		Name:        "Sacrificial Node",
+		Description: "If this card is sacrificed for a Sacrifice Summon or Set: Draw 1 card.",
+		CardType:    CardTypeAgent,
+		Level:       2,
+		Attribute:   AttrDARK,
+		AgentType:   "Machine",
+		ATK:         500,
+		DEF:         500,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// ContestedDig — Normal Program. Reveal your top 3 cards; your opponent picks 1 for you to add to your hand, the rest go to the scrapheap.
+func ContestedDig() *Card {
+	eff := &CardEffect{
+		Name:            "Contested Dig",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.State.Players[player].DeckCount() >= 3
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			opp := gs.Opponent(player)
+
+			var revealed []*CardInstance
+			for i := 0; i < 3; i++ {
+				c := p.PeekDeck()
+				if c == nil {
+					break
+				}
+				p.RemoveFromDeck(c)
+				revealed = append(revealed, c)
+				d.log(log.NewRevealCardEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name, "the top of their Deck"))
+			}
+			if len(revealed) == 0 {
+				return nil
+			}
+
+			// The opponent picks which revealed card the activating player keeps
+			chosen, err := d.Controllers[opp].ChooseCards(d.ctx, gs, "Choose 1 card for your opponent to add to their hand", revealed, 1, 1)
+			if err != nil {
+				return err
+			}
+			for _, c := range revealed {
+				if len(chosen) > 0 && c == chosen[0] {
+					c.Zone = ZoneHand
+					p.Hand = append(p.Hand, c)
+					d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name, "Contested Dig"))
+					continue
+				}
+				p.SendToScrapheap(c)
+				d.log(log.NewSendToScrapheapEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name, "Contested Dig"))
+			}
+			d.recalculateContinuousEffects()
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Contested Dig",
+		Description: "Reveal the top 3 cards of your Deck. Your opponent chooses 1 of them for you to add to your hand, and the rest are sent to the scrapheap.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// DualFangUnit — Effect Agent. Can attack twice during each Battle Phase, but cannot attack directly.
+func DualFangUnit() *Card {
+	eff := &CardEffect{
+		Name:               "Dual-Fang Unit Twin Strike",
+		EffectType:         EffectContinuous,
+		CannotDirectAttack: true,
+		ExtraAttacks: func(d *Duel, card *CardInstance, player int) int {
+			return 1
+		},
+	}
+	return &Card{
+		Name:        "Dual-Fang Unit",
+		Description: "This card can attack twice during each Battle Phase. This card cannot attack your opponent directly.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrEARTH,
+		AgentType:   "Machine",
+		ATK:         1700,
+		DEF:         1000,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// Defragment — Normal Program. Shuffle up to 5 cards from your scrapheap into your Deck.
+func Defragment() *Card {
+	eff := &CardEffect{
+		Name:      "Defragment",
+		ExecSpeed: ExecSpeed1,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return len(d.State.Players[player].Scrapheap) > 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			if len(p.Scrapheap) == 0 {
+				return nil
+			}
+			candidates := append([]*CardInstance(nil), p.Scrapheap...)
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose up to 5 cards to shuffle into your Deck", candidates, 1, min(5, len(candidates)))
+			if err != nil {
+				return err
+			}
+			for _, c := range chosen {
+				d.returnFromScrapheapToDeck(player, c, "Defragment")
+			}
+			p.ShuffleDeck(d.rng)
+			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Defragment",
+		Description: "Choose up to 5 cards in your scrapheap and shuffle them into your Deck.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// FortressProtocol — Continuous Program. Your DEF Position agents gain DEF equal to their original ATK.
+func FortressProtocol() *Card {
+	eff := &CardEffect{
+		Name:       "Fortress Protocol",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		ContinuousApply: func(d *Duel, card *CardInstance, player int) {
+			for _, m := range d.State.Players[player].FaceUpAgents() {
+				if m.Position != PositionDEF {
+					continue
+				}
+				base := m.Card.ATK
+				if m.OriginalATK != 0 {
+					base = m.OriginalATK
+				}
+				if base > 0 {
+					m.AddModifier(StatModifier{Source: card.ID, DEFMod: base, Continuous: true})
+				}
+			}
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.recalculateContinuousEffects()
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Fortress Protocol",
+		Description: "Face-up DEF Position agents you control gain DEF equal to their original ATK.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// AegisPulse — SS2 Quick-Play Program. Tribute 1 agent; you take no battle damage this turn.
+func AegisPulse() *Card {
+	eff := &CardEffect{
+		Name:      "Aegis Pulse",
+		ExecSpeed: ExecSpeed2,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return len(d.State.Players[player].Agents()) > 0
+		},
+		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
+			gs := d.State
+			agents := gs.Players[player].Agents()
+			if len(agents) == 0 {
+				return false, nil
+			}
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Tribute 1 agent", agents, 1, 1)
+			if err != nil {
+				return false, err
+			}
+			d.tributeAsCost(player, chosen, card)
+			return true, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.State.NoBattleDamage[player] = true
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Aegis Pulse",
+		Description: "Tribute 1 agent; you take no battle damage this turn.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramQuickPlay,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// ScoutRelay — When Normal Summoned, SS a Level 1 agent from Deck in DEF.
+func ScoutRelay() *Card {
+	level1InDeck := func(p *Player) []*CardInstance {
+		var candidates []*CardInstance
+		for _, c := range p.Deck {
+			if c.Card.CardType == CardTypeAgent && c.Card.Level == 1 {
+				candidates = append(candidates, c)
+			}
+		}
+		return candidates
+	}
+	eff := &CardEffect{
+		Name:         "Scout Relay",
+		ExecSpeed:    ExecSpeed1,
+		EffectType:   EffectTrigger,
+		IsTrigger:    true,
+		TriggerEvent: log.EventNormalSummon,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			gs := d.State
+			p := gs.Players[player]
+			return gs.LastSummonEvent != nil && gs.LastSummonEvent.Card.ID == card.ID &&
+				p.FreeAgentZone() != -1 && len(level1InDeck(p)) > 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			candidates := level1InDeck(p)
+			if p.FreeAgentZone() == -1 || len(candidates) == 0 {
+				return nil
+			}
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose a Level 1 agent to Special Summon", candidates, 1, 1)
+			if err != nil {
+				return err
+			}
+			p.RemoveFromDeck(chosen[0])
+			if err := d.executeSpecialSummon(chosen[0], player, PositionDEF, FaceUp); err != nil {
+				return err
+			}
+			p.ShuffleDeck(d.rng)
+			d.log(log.NewShuffleEvent(gs.Turn, gs.Phase.String(), player))
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Scout Relay",
+		Description: "When this card is Normal Summoned: You can Special Summon 1 Level 1 agent from your Deck in Defense Position, then shuffle your Deck.",
+		CardType:    CardTypeAgent,
+		Level:       4,
+		Attribute:   AttrWIND,
+		AgentType:   "Cyborg",
+		ATK:         1500,
+		DEF:         1000,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// CrossedWires — Normal Program. Switch control of 1 of your agents and 1 of the opponent's until the End Phase.
+func CrossedWires() *Card {
+	targetable := func(d *Duel, card *CardInstance, p int) []*CardInstance {
+		var result []*CardInstance
+		for _, m := range d.State.Players[p].Agents() {
+			if d.canTarget(card, m) {
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+	eff := &CardEffect{
+		Name:      "Crossed Wires",
+		ExecSpeed: ExecSpeed1,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			gs := d.State
+			opp := gs.Opponent(player)
+			return len(targetable(d, card, player)) > 0 && len(targetable(d, card, opp)) > 0 &&
+				gs.Players[player].FreeAgentZone() != -1 && gs.Players[opp].FreeAgentZone() != -1
+		},
+		Target: func(d *Duel, card *CardInstance, player int) ([]*CardInstance, error) {
+			gs := d.State
+			mine, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose 1 of your agents to give", targetable(d, card, player), 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			theirs, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose 1 of your opponent's agents to take", targetable(d, card, gs.Opponent(player)), 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return []*CardInstance{mine[0], theirs[0]}, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			if len(targets) != 2 {
+				return nil
+			}
+			opp := d.State.Opponent(player)
+			mine, theirs := targets[0], targets[1]
+			if !d.isOnField(mine) || mine.Controller != player || !d.isOnField(theirs) || theirs.Controller != opp {
+				return nil
+			}
+			if err := d.changeControlUntilEndPhase(mine, opp); err != nil {
+				return err
+			}
+			return d.changeControlUntilEndPhase(theirs, player)
+		},
+	}
+	return &Card{
+		Name:        "Crossed Wires",
+		Description: "Target 1 agent you control and 1 agent your opponent controls; switch control of those targets until the End Phase.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// OpenExchange — Normal Program. Both players draw 2 cards, the turn player first.
+func OpenExchange() *Card {
+	eff := &CardEffect{
+		Name:            "Open Exchange",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			// The turn player draws first, so they are the one who decks out if both would
+			tp := d.State.TurnPlayer
+			if d.drawForEffect(tp, 2) {
+				d.drawForEffect(d.State.Opponent(tp), 2)
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Open Exchange",
+		Description: "Both players draw 2 cards, starting with the turn player.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// SingleStrikeDoctrine — Continuous Trap. Your opponent can declare only 1 attack each turn.
+func SingleStrikeDoctrine() *Card {
+	eff := &CardEffect{
+		Name:                "Single Strike Doctrine",
+		ExecSpeed:           ExecSpeed2,
+		EffectType:          EffectContinuous,
+		OpponentAttackLimit: 1,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up
+		},
+	}
+	return &Card{
+		Name:        "Single Strike Doctrine",
+		Description: "Your opponent can only declare 1 attack each Battle Phase.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// FullDisclosureDraw — Normal Program. Reveal your hand to your opponent, then draw 1 card for each agent revealed.
+func FullDisclosureDraw() *Card {
+	eff := &CardEffect{
+		Name:            "Full Disclosure Draw",
+		ExecSpeed:       ExecSpeed1,
+		DrawsOrSearches: true,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			p := gs.Players[player]
+			var names []string
+			agents := 0
+			for _, c := range p.Hand {
+				names = append(names, c.Card.Name)
+				if c.Card.CardType == CardTypeAgent {
+					agents++
+				}
+			}
+			gs.RevealedHands[player] = true
+			defer func() { gs.RevealedHands[player] = false }()
+			d.log(log.NewRevealHandEvent(gs.Turn, gs.Phase.String(), player, names))
+
+			// Draws stop at the bottom of the Deck rather than decking out
+			d.drawForEffect(player, min(agents, p.DeckCount()))
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Full Disclosure Draw",
+		Description: "Reveal your hand to your opponent, then draw 1 card for each agent revealed.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// ExposureField — Quick-Play Program. Neither player can Set agents for the rest of this turn.
+func ExposureField() *Card {
+	eff := &CardEffect{
+		Name:      "Exposure Field",
+		ExecSpeed: ExecSpeed2,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.State.NoAgentSet = true
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Exposure Field",
+		Description: "Neither player can Set agents for the rest of this turn.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramQuickPlay,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// BalancedLedger — Operating System. Each time a player Special Summons an agent, their opponent draws 1 card.
+func BalancedLedger() *Card {
+	eff := &CardEffect{
+		Name:       "Balanced Ledger",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		OnSpecialSummon: func(d *Duel, card *CardInstance, summoned *CardInstance, player int) {
+			d.drawForEffect(d.State.Opponent(player), 1)
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up; applied by executeSpecialSummon
+		},
+	}
+	return &Card{
+		Name:        "Balanced Ledger",
+		Description: "Each time a player Special Summons an agent, their opponent draws 1 card.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramOS,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// FlipJammer — SS3 Counter Trap. Negate the activation of a FLIP effect.
+func FlipJammer() *Card {
+	eff := &CardEffect{
+		Name:      "Flip Jammer",
+		ExecSpeed: ExecSpeed3,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			gs := d.State
+			if gs.Chain == nil || len(gs.Chain.Links) == 0 {
+				return false
+			}
+			return gs.Chain.Links[len(gs.Chain.Links)-1].Effect.EffectType == EffectFlip
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			// Negate only: the flipped agent stays on the field
+			if myIndex := d.chainIndexOf(card); myIndex > 0 {
+				d.negateChainLink(myIndex - 1)
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Flip Jammer",
+		Description: "When a FLIP effect is activated: Negate the activation.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapCounter,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// LifeSiphonArray — Normal Program. Tribute 1 agent; gain HP equal to its ATK.
+func LifeSiphonArray() *Card {
+	eff := &CardEffect{
+		Name:      "Life Siphon Array",
+		ExecSpeed: ExecSpeed1,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return len(d.State.Players[player].Agents()) > 0
+		},
+		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
+			gs := d.State
+			agents := gs.Players[player].Agents()
+			if len(agents) == 0 {
+				return false, nil
+			}
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Tribute 1 agent", agents, 1, 1)
+			if err != nil {
+				return false, err
+			}
+			// The ATK it had on the field, before it loses its modifiers
+			card.Counters["siphon_hp"] = chosen[0].CurrentATK()
+			d.tributeAsCost(player, chosen, card)
+			return true, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			if hp := card.Counters["siphon_hp"]; hp > 0 {
+				d.gainHP(player, hp, "Life Siphon Array")
+			}
+			card.Counters["siphon_hp"] = 0
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Life Siphon Array",
+		Description: "Tribute 1 agent; gain HP equal to its ATK.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// MimicShell — Effect Agent. While face-up, treated as having the name and type of the last agent it battled this turn.
+func MimicShell() *Card {
+	eff := &CardEffect{
+		Name:       "Mimic Shell",
+		EffectType: EffectContinuous,
+		NameAs: func(d *Duel, card *CardInstance) (*Card, bool) {
+			if card.LastBattled == nil || card.LastBattledTurn != d.State.Turn {
+				return nil, false
+			}
+			return card.LastBattled.Card, true
+		},
+	}
+	return &Card{
+		Name:        "Mimic Shell",
+		Description: "While this card is face-up on the field, it is treated as having the name and Type of the last agent it battled this turn.",
+		CardType:    CardTypeAgent,
+		Level:       3,
+		Attribute:   AttrDARK,
+		AgentType:   "Construct",
+		ATK:         1200,
+		DEF:         1000,
+		IsEffect:    true,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// TotalSurveillance — Continuous Trap. While face-up, your opponent plays with their hand revealed.
+func TotalSurveillance() *Card {
+	eff := &CardEffect{
+		Name:                "Total Surveillance",
+		ExecSpeed:           ExecSpeed2,
+		EffectType:          EffectContinuous,
+		RevealsOpponentHand: true,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			gs := d.State
+			opp := gs.Opponent(player)
+			var names []string
+			for _, c := range gs.Players[opp].Hand {
+				names = append(names, c.Card.Name)
+			}
+			d.log(log.NewRevealHandEvent(gs.Turn, gs.Phase.String(), opp, names))
+			return nil // stays face-up; checked by GameState.HandRevealed
+		},
+	}
+	return &Card{
+		Name:        "Total Surveillance",
+		Description: "While this card is face-up on the field, your opponent plays with their hand revealed.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// TimeDilationCore — Normal Program. Take an extra turn after this one. Can't be activated while an extra turn is pending.
+func TimeDilationCore() *Card {
+	eff := &CardEffect{
+		Name:      "Time Dilation Core",
+		ExecSpeed: ExecSpeed1,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.State.ExtraTurnFor < 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			// Another copy may have resolved first in the same chain
+			if d.State.ExtraTurnFor < 0 {
+				d.State.ExtraTurnFor = player
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Time Dilation Core",
+		Description: "Take an extra turn after this one. You cannot activate this card while an extra turn is pending.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramNormal,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// SpoilsProtocol — Continuous Program. At the end of your Battle Phase, if an opponent's agent was destroyed by battle that phase, draw 1 card.
+func SpoilsProtocol() *Card {
+	eff := &CardEffect{
+		Name:       "Spoils Protocol",
+		ExecSpeed:  ExecSpeed1,
+		EffectType: EffectContinuous,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			return nil // stays face-up
+		},
+		OnBattlePhaseEnd: func(d *Duel, card *CardInstance, player int) {
+			gs := d.State
+			if player != gs.TurnPlayer || !gs.DestroyedByBattle[gs.Opponent(player)] {
+				return
+			}
+			d.drawForEffect(player, 1)
+		},
+	}
+	return &Card{
+		Name:        "Spoils Protocol",
+		Description: "At the end of your Battle Phase, if an agent your opponent controlled was destroyed by battle during that Battle Phase: Draw 1 card.",
+		CardType:    CardTypeProgram,
+		ProgramSub:  ProgramContinuous,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// InterferenceBurst — Counter Trap. When your opponent activates a Program that draws or searches: purge 2 cards from your scrapheap; negate the activation.
+func InterferenceBurst() *Card {
+	eff := &CardEffect{
+		Name:      "Interference Burst",
+		ExecSpeed: ExecSpeed3,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			gs := d.State
+			if gs.Chain == nil || len(gs.Chain.Links) == 0 || len(gs.Players[player].Scrapheap) < 2 {
+				return false
+			}
+			top := gs.Chain.Links[len(gs.Chain.Links)-1]
+			return top.Controller != player && top.Card.Card.CardType == CardTypeProgram && top.Effect.DrawsOrSearches
+		},
+		Cost: func(d *Duel, card *CardInstance, player int) (bool, error) {
+			gs := d.State
+			chosen, err := d.Controllers[player].ChooseCards(d.ctx, gs, "Choose 2 cards in your scrapheap to purge", gs.Players[player].Scrapheap, 2, 2)
+			if err != nil {
+				return false, err
+			}
+			if len(chosen) != 2 {
+				return false, nil
+			}
+			d.purgeAsCost(player, chosen, card)
+			return true, nil
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			if myIndex := d.chainIndexOf(card); myIndex > 0 {
+				d.negateChainLink(myIndex - 1)
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Interference Burst",
+		Description: "When your opponent activates a Program that would draw or add cards from the Deck to the hand: Purge 2 cards from your scrapheap; negate the activation.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapCounter,
+		Effects:     []*CardEffect{eff},
+	}
+}
+
+// DecayProtocol — Continuous Trap. Mark your opponent's Set Programs/Traps; at their next End Phase, destroy each marked card still Set.
+// A marked card that is activated or flipped face-up loses its mark.
+func DecayProtocol() *Card {
+	// unmark clears this card's marks from the opponent's cards, except those
+	// still Set when keepSet is true.
+	unmark := func(d *Duel, card *CardInstance, keepSet bool) {
+		p := d.State.Players[d.State.Opponent(card.Controller)]
+		cards := append(p.TechCards(), p.Hand...)
+		cards = append(cards, p.Scrapheap...)
+		cards = append(cards, p.Purged...)
+		cards = append(cards, p.Deck...)
+		for _, c := range cards {
+			if c.Counters["decay_source"] != card.ID {
+				continue
+			}
+			if keepSet && c.Zone == ZoneTech && c.Face == FaceDown {
+				continue
+			}
+			delete(c.Counters, "decay_source")
+		}
+	}
+	markEff := &CardEffect{
+		Name:       "Decay Protocol",
+		ExecSpeed:  ExecSpeed2,
+		EffectType: EffectContinuous,
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return len(d.State.Players[d.State.Opponent(player)].FaceDownTech()) > 0
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			for _, st := range d.State.Players[d.State.Opponent(player)].FaceDownTech() {
+				st.Counters["decay_source"] = card.ID
+			}
+			return nil
+		},
+		ContinuousApply: func(d *Duel, card *CardInstance, player int) {
+			unmark(d, card, true)
+		},
+		OnLeaveField: func(d *Duel, card *CardInstance, player int) {
+			unmark(d, card, false)
+		},
+	}
+	decayEff := &CardEffect{
+		Name:          "Decay Protocol Destroy",
+		ExecSpeed:     ExecSpeed1,
+		EffectType:    EffectTrigger,
+		IsTrigger:     true,
+		IsMandatory:   true,
+		TriggerEvent:  log.EventPhaseChange,
+		OnFieldEffect: func(d *Duel, card *CardInstance, player int) {},
+		CanActivate: func(d *Duel, card *CardInstance, player int) bool {
+			return d.State.Phase == PhaseEnd && d.State.TurnPlayer != player
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			unmark(d, card, true)
+			for _, st := range d.State.Players[d.State.Opponent(player)].FaceDownTech() {
+				if st.Counters["decay_source"] == card.ID {
+					delete(st.Counters, "decay_source")
+					d.destroyByEffect(st, card, "Decay Protocol")
+				}
+			}
+			return nil
+		},
+	}
+	return &Card{
+		Name:        "Decay Protocol",
+		Description: "Your opponent's Set Programs and Traps on the field when this card resolves are marked. At your opponent's next End Phase, destroy each marked card that is still Set.",
+		CardType:    CardTypeTrap,
+		TrapSub:     TrapContinuous,
+		Effects:     []*CardEffect{markEff, decayEff},
+	}
+}
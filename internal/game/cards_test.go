@@ -0,0 +1,2508 @@
+package game
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// TestPolarityInvert: swapped stats are used in battle and revert at end of turn.
+func TestPolarityInvert(t *testing.T) {
+	wall := vanillaAgent("Wall Bot", 4, 1000, 2500, AttrEARTH)
+	striker := vanillaAgent("Striker", 4, 2000, 500, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{wall}, 40)
+	deck1 := makePaddedDeck([]*Card{striker, PolarityInvert()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Wall Bot in ATK position
+	p0.AddAction(ActionNormalSummon, "Wall Bot")
+
+	// Turn 2 (P2): Summon Striker, invert polarity, attack into the swapped Wall Bot
+	p1.AddAction(ActionNormalSummon, "Striker")
+	p1.AddAction(ActionActivate, "Polarity Invert")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddAttack("Striker", "Wall Bot")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	// Battle used swapped values: Striker (ATK 500) vs Wall Bot (ATK 2500)
+	calcs := logger.EventsOfType(log.EventDamageCalc)
+	if len(calcs) == 0 {
+		t.Fatal("Expected a damage calculation")
+	}
+	if !strings.Contains(calcs[0].Details, "Striker (ATK 500)") || !strings.Contains(calcs[0].Details, "Wall Bot (ATK 2500)") {
+		t.Errorf("Expected swapped stats in damage calc, got %q", calcs[0].Details)
+	}
+
+	// Striker destroyed, P2 takes 2000
+	destroys := logger.EventsOfType(log.EventBattleDestroy)
+	if len(destroys) != 1 || destroys[0].Card != "Striker" {
+		t.Errorf("Expected only Striker destroyed by battle, got %v", destroys)
+	}
+	if hp := duel.State.Players[1].HP; hp != StartingHP-2000 {
+		t.Errorf("Expected P2 HP %d, got %d", StartingHP-2000, hp)
+	}
+
+	// Turn 3: Wall Bot's stats have reverted
+	m := findAgent(duel, 0, "Wall Bot")
+	if m == nil {
+		t.Fatal("Expected Wall Bot on P1's field")
+	}
+	if m.CurrentATK() != 1000 || m.CurrentDEF() != 2500 {
+		t.Errorf("Expected Wall Bot to revert to 1000/2500, got %d/%d", m.CurrentATK(), m.CurrentDEF())
+	}
+}
+
+// TestPolarityInvertThenBoost: a stat change after the swap applies to the
+// swapped values and outlasts the swap.
+func TestPolarityInvertThenBoost(t *testing.T) {
+	duel := NewDuel(DuelConfig{Deck0: makePaddedDeck(nil, 10), Deck1: makePaddedDeck(nil, 10)},
+		NewRandomController(1), NewRandomController(2))
+	gs := duel.State
+	wall := gs.CreateCardInstance(vanillaAgent("Wall Bot", 4, 1000, 2500, AttrEARTH), 0)
+	wall.Face = FaceUp
+	gs.Players[0].PlaceAgent(wall, 0)
+
+	if err := PolarityInvert().Effects[0].Resolve(duel, nil, 1, nil); err != nil {
+		t.Fatalf("Polarity Invert: %v", err)
+	}
+	wall.AddModifier(StatModifier{ATKMod: 500, Permanent: true})
+	if wall.CurrentATK() != 3000 || wall.CurrentDEF() != 1000 {
+		t.Errorf("Expected the boost on the swapped ATK, 3000/1000, got %d/%d", wall.CurrentATK(), wall.CurrentDEF())
+	}
+
+	duel.clearEndOfTurnModifiers()
+	if wall.CurrentATK() != 1500 || wall.CurrentDEF() != 2500 {
+		t.Errorf("Expected the swap to end and the boost to stay, 1500/2500, got %d/%d", wall.CurrentATK(), wall.CurrentDEF())
+	}
+}
+
+// TestPolarityInvertAfterBoost: only the original stats swap, so a boost
+// applied before Polarity Invert stays on ATK rather than moving to DEF.
+func TestPolarityInvertAfterBoost(t *testing.T) {
+	duel := NewDuel(DuelConfig{Deck0: makePaddedDeck(nil, 10), Deck1: makePaddedDeck(nil, 10)},
+		NewRandomController(1), NewRandomController(2))
+	gs := duel.State
+	wall := gs.CreateCardInstance(vanillaAgent("Wall Bot", 4, 1000, 2500, AttrEARTH), 0)
+	wall.Face = FaceUp
+	gs.Players[0].PlaceAgent(wall, 0)
+
+	wall.AddModifier(StatModifier{ATKMod: 500, EndOfTurn: true})
+	if err := PolarityInvert().Effects[0].Resolve(duel, nil, 1, nil); err != nil {
+		t.Fatalf("Polarity Invert: %v", err)
+	}
+	if wall.CurrentATK() != 3000 || wall.CurrentDEF() != 1000 {
+		t.Errorf("Expected the boost to stay on ATK, 3000/1000, got %d/%d", wall.CurrentATK(), wall.CurrentDEF())
+	}
+
+	duel.clearEndOfTurnModifiers()
+	if wall.CurrentATK() != 1000 || wall.CurrentDEF() != 2500 {
+		t.Errorf("Expected the swap and the boost to end, 1000/2500, got %d/%d", wall.CurrentATK(), wall.CurrentDEF())
+	}
+}
+
+// TestMimicFrame: Mimic Frame copies the strongest other agent and reverts when it leaves.
+func TestMimicFrame(t *testing.T) {
+	titan := vanillaAgent("Titan", 4, 2800, 1000, AttrEARTH)
+
+	fl := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	newDecks := func() ([]*Card, []*Card) {
+		// Flatline Command is drawn on Turn 3
+		deck0 := makePaddedDeck([]*Card{MimicFrame(), fl, fl, fl, fl, fl, FlatlineCommand()}, 40)
+		deck1 := makePaddedDeck([]*Card{titan}, 40)
+		return deck0, deck1
+	}
+
+	// Turn 1 (P1): Summon Mimic Frame (empty field → base ATK)
+	// Turn 2 (P2): Summon Titan → Mimic Frame becomes 2800
+	deck0, deck1 := newDecks()
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	p0.AddAction(ActionNormalSummon, "Mimic Frame")
+	p1.AddAction(ActionNormalSummon, "Titan")
+
+	duel, logger := runDuel(t, DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}, p0, p1)
+	summons := logger.EventsOfType(log.EventNormalSummon)
+	if len(summons) == 0 || !strings.Contains(summons[0].Details, "ATK 1000") {
+		t.Errorf("Expected Mimic Frame to be summoned with base ATK 1000, got %v", summons)
+	}
+	if m := findAgent(duel, 0, "Mimic Frame"); m == nil || m.CurrentATK() != 2800 {
+		t.Fatalf("Expected Mimic Frame ATK 2800, got %v", m)
+	}
+
+	// Turn 3 (P1): Flatline Command destroys Titan → Mimic Frame back to 1000
+	deck0, deck1 = newDecks()
+	p0 = NewScriptedController(t, "P1")
+	p1 = NewScriptedController(t, "P2")
+	p0.AddAction(ActionNormalSummon, "Mimic Frame")
+	p1.AddAction(ActionNormalSummon, "Titan")
+	p0.AddAction(ActionActivate, "Flatline Command")
+	p0.AddCardChoice("Titan")
+
+	duel, _ = runDuel(t, DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}, p0, p1)
+	if findAgent(duel, 1, "Titan") != nil {
+		t.Fatal("Expected Titan to be destroyed")
+	}
+	if m := findAgent(duel, 0, "Mimic Frame"); m == nil || m.CurrentATK() != 1000 {
+		t.Errorf("Expected Mimic Frame to revert to 1000 ATK, got %v", m)
+	}
+}
+
+// TestThornResponse: the attacker that destroys an agent by battle is destroyed in return.
+func TestThornResponse(t *testing.T) {
+	guard := vanillaAgent("Guard", 4, 1000, 1000, AttrEARTH)
+	brute := vanillaAgent("Brute", 4, 2000, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{guard, ThornResponse()}, 40)
+	deck1 := makePaddedDeck([]*Card{brute}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Guard, set Thorn Response
+	p0.AddAction(ActionNormalSummon, "Guard")
+	p0.AddAction(ActionSetTech, "Thorn Response")
+	p0.AddYesNo(true)
+
+	// Turn 2 (P2): Summon Brute and attack Guard
+	p1.AddAction(ActionNormalSummon, "Brute")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddAttack("Brute", "Guard")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	destroys := logger.EventsOfType(log.EventBattleDestroy)
+	if len(destroys) != 1 || destroys[0].Card != "Guard" {
+		t.Fatalf("Expected Guard destroyed by battle, got %v", destroys)
+	}
+	if findAgent(duel, 1, "Brute") != nil {
+		t.Error("Expected Brute to be destroyed by Thorn Response")
+	}
+	found := false
+	for _, e := range logger.EventsOfType(log.EventDestroy) {
+		if e.Card == "Brute" && strings.Contains(e.Details, "Thorn Response") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a destroy event for Brute from Thorn Response")
+	}
+}
+
+// TestTrapImmunityField: an opponent's Reactive Plating can't destroy a protected attacker.
+func TestTrapImmunityField(t *testing.T) {
+	striker := vanillaAgent("Striker", 4, 1800, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{striker, TrapImmunityField()}, 40)
+	deck1 := makePaddedDeck([]*Card{ReactivePlating()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Activate Trap Immunity Field, summon Striker
+	p0.AddAction(ActionActivate, "Trap Immunity Field")
+	p0.AddAction(ActionNormalSummon, "Striker")
+	// Turn 3 (P1): Attack directly
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddDirectAttack("Striker")
+
+	// Turn 2 (P2): Set Reactive Plating; Turn 3: activate it on the attack
+	p1.AddAction(ActionSetTech, "Reactive Plating")
+	p1.AddAction(ActionActivate, "Reactive Plating")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if len(logger.EventsOfType(log.EventChainResolve)) == 0 {
+		t.Fatal("Expected Reactive Plating to resolve")
+	}
+	if len(logger.EventsOfType(log.EventUnaffected)) != 1 {
+		t.Error("Expected Striker to be unaffected by Reactive Plating")
+	}
+	if findAgent(duel, 0, "Striker") == nil {
+		t.Fatal("Expected Striker to survive Reactive Plating")
+	}
+	if hp := duel.State.Players[1].HP; hp != StartingHP-1800 {
+		t.Errorf("Expected P2 HP %d after the direct attack, got %d", StartingHP-1800, hp)
+	}
+}
+
+// TestEchoCaller: a Greed Protocol in the scrapheap is purged to draw 2 again.
+func TestEchoCaller(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{GreedProtocol(), EchoCaller()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Greed Protocol, summon Echo Caller, re-fire Greed Protocol
+	p0.AddAction(ActionActivate, "Greed Protocol")
+	p0.AddAction(ActionNormalSummon, "Echo Caller")
+	p0.AddAction(ActionActivate, "Echo Caller")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	draws := 0
+	for _, e := range logger.EventsOfType(log.EventDraw) {
+		if e.Phase == PhaseMain1.String() && e.Player == 0 {
+			draws++
+		}
+	}
+	if draws != 4 {
+		t.Errorf("Expected 4 draws in Main Phase 1 (Greed Protocol twice), got %d", draws)
+	}
+
+	p := duel.State.Players[0]
+	if len(p.Purged) != 1 || p.Purged[0].Card.Name != "Greed Protocol" {
+		t.Errorf("Expected Greed Protocol purged, got %v", p.Purged)
+	}
+	for _, c := range p.Scrapheap {
+		if c.Card.Name == "Greed Protocol" {
+			t.Error("Expected Greed Protocol to leave the scrapheap")
+		}
+	}
+	costs := logger.EventsOfType(log.EventCostPaid)
+	if len(costs) != 1 || costs[0].Card != "Echo Caller" {
+		t.Errorf("Expected one purge cost paid for Echo Caller, got %v", costs)
+	}
+}
+
+// TestEntropyField: opponent's agents lose 100 ATK per card in the controller's scrapheap.
+func TestEntropyField(t *testing.T) {
+	brute := vanillaAgent("Brute", 4, 2000, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{EntropyField(), GreedProtocol(), GreedProtocol()}, 40)
+	deck1 := makePaddedDeck([]*Card{brute}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Entropy Field, Greed Protocol (scrapheap: 1)
+	p0.AddAction(ActionSetTech, "Entropy Field")
+	p0.AddAction(ActionActivate, "Greed Protocol")
+	// Turn 3 (P1): Activate Entropy Field, second Greed Protocol (scrapheap: 2)
+	p0.AddAction(ActionActivate, "Entropy Field")
+	p0.AddAction(ActionActivate, "Greed Protocol")
+
+	// Turn 2 (P2): Summon Brute
+	p1.AddAction(ActionNormalSummon, "Brute")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	m := findAgent(duel, 1, "Brute")
+	if m == nil {
+		t.Fatal("Expected Brute on P2's field")
+	}
+	// Two Greed Protocols plus the End Phase hand-size discards
+	p := duel.State.Players[0]
+	if len(p.Scrapheap) < 2 {
+		t.Fatalf("Expected at least 2 cards in P1's scrapheap, got %d", len(p.Scrapheap))
+	}
+	for len(p.Scrapheap) > 0 {
+		if want := 2000 - 100*len(p.Scrapheap); m.CurrentATK() != want {
+			t.Fatalf("Expected Brute ATK %d with %d cards in scrapheap, got %d", want, len(p.Scrapheap), m.CurrentATK())
+		}
+		// Emptying the scrapheap restores the ATK
+		duel.purgeFromScrapheap(0, p.Scrapheap[0], "test")
+	}
+	if m.CurrentATK() != 2000 {
+		t.Errorf("Expected Brute ATK 2000 with an empty scrapheap, got %d", m.CurrentATK())
+	}
+}
+
+// TestFullRollback: all agents return to their owners' hands, including a stolen one.
+func TestFullRollback(t *testing.T) {
+	alpha := vanillaAgent("Alpha", 4, 1500, 1000, AttrLIGHT)
+	beta := vanillaAgent("Beta", 4, 1600, 1000, AttrDARK)
+
+	// Full Rollback is P1's Turn 3 draw so it isn't activated on Turn 1
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{alpha, filler, filler, filler, filler, filler, FullRollback()}, 40)
+	deck1 := makePaddedDeck([]*Card{beta, HostileTakeover()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Alpha; Turn 3: Full Rollback
+	p0.AddAction(ActionNormalSummon, "Alpha")
+	p0.AddAction(ActionActivate, "Full Rollback")
+
+	// Turn 2 (P2): Summon Beta, take control of Alpha
+	p1.AddAction(ActionNormalSummon, "Beta")
+	p1.AddAction(ActionActivate, "Hostile Takeover")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	for p := 0; p < 2; p++ {
+		if n := duel.State.Players[p].AgentCount(); n != 0 {
+			t.Errorf("Expected P%d's agent zones empty, got %d agents", p+1, n)
+		}
+	}
+	inHand := func(player int, name string) *CardInstance {
+		for _, c := range duel.State.Players[player].Hand {
+			if c.Card.Name == name {
+				return c
+			}
+		}
+		return nil
+	}
+	a := inHand(0, "Alpha")
+	if a == nil {
+		t.Fatal("Expected Alpha back in its owner's (P1's) hand")
+	}
+	if a.Controller != 0 || len(a.Modifiers) != 0 || len(a.Equips) != 0 {
+		t.Errorf("Expected Alpha's field state reset, got controller=%d mods=%v equips=%v", a.Controller, a.Modifiers, a.Equips)
+	}
+	if inHand(1, "Beta") == nil {
+		t.Error("Expected Beta back in P2's hand")
+	}
+	if inHand(1, "Alpha") != nil {
+		t.Error("Expected Alpha not to go to its controller's hand")
+	}
+}
+
+// attackTargetRecorder wraps a ScriptedController and records the names of the
+// agents offered as attack targets on each of its prompts.
+type attackTargetRecorder struct {
+	*ScriptedController
+	targets map[string]bool
+}
+
+func (ar *attackTargetRecorder) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	for _, a := range actions {
+		if a.Type == ActionAttack {
+			ar.targets[a.Targets[0].Card.Name] = true
+		}
+	}
+	return ar.ScriptedController.ChooseAction(ctx, state, actions)
+}
+
+// TestAggroBeacon: the opponent's attacker may only target Aggro Beacon, not a weaker agent.
+func TestAggroBeacon(t *testing.T) {
+	weak := vanillaAgent("Weak Dummy", 2, 500, 500, AttrLIGHT)
+	striker := vanillaAgent("Striker", 4, 2500, 500, AttrFIRE)
+
+	// Aggro Beacon is P1's Turn 3 draw; Striker is P2's Turn 4 draw
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{weak, filler, filler, filler, filler, filler, AggroBeacon()}, 40)
+	deck1 := makePaddedDeck([]*Card{filler, filler, filler, filler, filler, filler, striker}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := &attackTargetRecorder{ScriptedController: NewScriptedController(t, "P2"), targets: map[string]bool{}}
+
+	// Turn 1 (P1): Summon Weak Dummy; Turn 3: Summon Aggro Beacon
+	p0.AddAction(ActionNormalSummon, "Weak Dummy")
+	p0.AddAction(ActionNormalSummon, "Aggro Beacon")
+
+	// Turn 4 (P2): Summon Striker, attack Aggro Beacon
+	p1.AddAction(ActionNormalSummon, "Striker")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddAttack("Striker", "Aggro Beacon")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	if !p1.targets["Aggro Beacon"] {
+		t.Error("Expected Aggro Beacon to be offered as an attack target")
+	}
+	if p1.targets["Weak Dummy"] {
+		t.Error("Expected Weak Dummy not to be attackable while Aggro Beacon is face-up in ATK")
+	}
+	if findAgent(duel, 0, "Aggro Beacon") != nil {
+		t.Error("Expected Aggro Beacon destroyed by Striker's attack")
+	}
+	if findAgent(duel, 0, "Weak Dummy") == nil {
+		t.Error("Expected Weak Dummy to survive")
+	}
+}
+
+// TestDataTrade: discarding 2 cards draws 2; choosing to discard none draws none.
+func TestDataTrade(t *testing.T) {
+	a := vanillaAgent("Card A", 4, 1000, 1000, AttrLIGHT)
+	b := vanillaAgent("Card B", 4, 1000, 1000, AttrLIGHT)
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	x := vanillaAgent("Card X", 4, 1000, 1000, AttrDARK)
+	y := vanillaAgent("Card Y", 4, 1000, 1000, AttrDARK)
+	newDeck := func() []*Card {
+		// Card X and Card Y are the next two cards after the Turn 1 draw
+		return makePaddedDeck([]*Card{DataTrade(), a, b, filler, filler, filler, x, y}, 40)
+	}
+
+	t.Run("discard 2", func(t *testing.T) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddAction(ActionActivate, "Data Trade")
+		p0.AddCardChoice("Card A", "Card B")
+
+		cfg := DuelConfig{Deck0: newDeck(), Deck1: makePaddedDeck(nil, 40), MaxTurns: 1}
+		duel, logger := runDuel(t, cfg, p0, p1)
+
+		discards := logger.EventsOfType(log.EventDiscard)
+		if len(discards) != 2 || discards[0].Card != "Card A" || discards[1].Card != "Card B" {
+			t.Fatalf("Expected Card A and Card B discarded, got %v", discards)
+		}
+		var drawn []string
+		for _, e := range logger.EventsOfType(log.EventDraw) {
+			if e.Phase == "Main Phase 1" {
+				drawn = append(drawn, e.Card)
+			}
+		}
+		if len(drawn) != 2 || drawn[0] != "Card X" || drawn[1] != "Card Y" {
+			t.Errorf("Expected Card X and Card Y drawn, got %v", drawn)
+		}
+		if n := len(duel.State.Players[0].Hand); n != 5 {
+			t.Errorf("Expected 5 cards in hand, got %d", n)
+		}
+	})
+
+	t.Run("discard 0", func(t *testing.T) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddAction(ActionActivate, "Data Trade")
+
+		cfg := DuelConfig{Deck0: newDeck(), Deck1: makePaddedDeck(nil, 40), MaxTurns: 1}
+		duel, logger := runDuel(t, cfg, p0, p1)
+
+		if len(logger.EventsOfType(log.EventChainResolve)) == 0 {
+			t.Fatal("Expected Data Trade to resolve")
+		}
+		if n := len(logger.EventsOfType(log.EventDiscard)); n != 0 {
+			t.Errorf("Expected no discards, got %d", n)
+		}
+		hand := duel.State.Players[0].Hand
+		if len(hand) != 5 || hand[0].Card.Name != "Card A" {
+			t.Errorf("Expected the hand untouched, got %v", hand)
+		}
+	})
+}
+
+// TestScalingMatrix: a Level 6 agent gains 600 ATK; The Undercity Grid's Level
+// reduction lowers the bonus.
+func TestScalingMatrix(t *testing.T) {
+	fodder := vanillaAgent("Fodder", 1, 100, 100, AttrLIGHT)
+	leviathan := vanillaAgent("Deep Leviathan", 6, 2000, 1500, AttrWATER)
+
+	deck0 := makePaddedDeck([]*Card{fodder, leviathan, ScalingMatrix()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Fodder, activate Scaling Matrix
+	p0.AddAction(ActionNormalSummon, "Fodder")
+	p0.AddAction(ActionActivate, "Scaling Matrix")
+	// Turn 3 (P1): Sacrifice Fodder for Deep Leviathan
+	p0.AddAction(ActionSacrificeSummon, "Deep Leviathan")
+	p0.AddCardChoice("Fodder")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	m := findAgent(duel, 0, "Deep Leviathan")
+	if m == nil {
+		t.Fatal("Expected Deep Leviathan on P1's field")
+	}
+	if atk := m.CurrentATK(); atk != 2600 {
+		t.Errorf("Expected Deep Leviathan ATK 2600 (2000 + 6×100), got %d", atk)
+	}
+
+	// Only one OS can normally be on the field, so put The Undercity Grid
+	// straight into P2's OS zone to have both active at once.
+	gs := duel.State
+	grid := &CardInstance{Card: TheUndercityGrid(), ID: gs.NextID(), Owner: 1, Controller: 1, Zone: ZoneOS, Face: FaceUp}
+	gs.Players[1].OS = grid
+	duel.recalculateContinuousEffects()
+
+	if lv := duel.currentLevel(m); lv != 5 {
+		t.Errorf("Expected Deep Leviathan Level 5 under The Undercity Grid, got %d", lv)
+	}
+	// 2000 base + 200 (Undercity Grid WATER bonus) + 5×100
+	if atk := m.CurrentATK(); atk != 2700 {
+		t.Errorf("Expected Deep Leviathan ATK 2700, got %d", atk)
+	}
+}
+
+// TestFailsafeCircuit: a lethal hit leaves P1 at 1 HP and destroys Failsafe
+// Circuit; the next lethal hit ends the duel.
+func TestFailsafeCircuit(t *testing.T) {
+	gs := NewGameState()
+	gs.Turn = 2
+	gs.TurnPlayer = 1
+	gs.Phase = PhaseBattle
+
+	memLog := log.NewMemoryLogger()
+	d := &Duel{
+		State:       gs,
+		Controllers: [2]PlayerController{NewScriptedController(t, "P1"), NewScriptedController(t, "P2")},
+		Logger:      memLog,
+		ctx:         context.Background(),
+	}
+
+	failsafe := &CardInstance{Card: FailsafeCircuit(), ID: gs.NextID(), Owner: 0, Controller: 0, Face: FaceUp}
+	gs.Players[0].PlaceTech(failsafe, 0)
+
+	d.applyDamage(0, StartingHP+1000, "direct attack")
+	if hp := gs.Players[0].HP; hp != 1 {
+		t.Errorf("Expected P1 HP 1, got %d", hp)
+	}
+	if gs.Over {
+		t.Fatal("Expected the duel to continue")
+	}
+	if gs.Players[0].TechZones[0] != nil || len(gs.Players[0].Scrapheap) != 1 {
+		t.Error("Expected Failsafe Circuit destroyed")
+	}
+
+	d.applyDamage(0, 500, "direct attack")
+	if !gs.Over || gs.Winner != 1 {
+		t.Errorf("Expected P2 to win on the second lethal hit, got over=%v winner=%d", gs.Over, gs.Winner)
+	}
+	if hp := gs.Players[0].HP; hp != 0 {
+		t.Errorf("Expected P1 HP 0, got %d", hp)
+	}
+}
+
+// TestForcedDisclosure: P2 activates it and picks which card P1 discards.
+func TestForcedDisclosure(t *testing.T) {
+	a := vanillaAgent("Card A", 4, 1000, 1000, AttrLIGHT)
+	b := vanillaAgent("Card B", 4, 1000, 1000, AttrLIGHT)
+	c := vanillaAgent("Card C", 4, 1000, 1000, AttrLIGHT)
+
+	deck0 := makePaddedDeck([]*Card{a, b, c}, 40)
+	deck1 := makePaddedDeck([]*Card{ForcedDisclosure()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 2 (P2): Forced Disclosure, discard Card B from P1's hand
+	p1.AddAction(ActionActivate, "Forced Disclosure")
+	p1.AddCardChoice("Card B")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if n := len(logger.EventsOfType(log.EventRevealHand)); n != 1 {
+		t.Errorf("Expected P1's hand to be revealed once, got %d", n)
+	}
+	discards := logger.EventsOfType(log.EventDiscard)
+	if len(discards) != 1 || discards[0].Card != "Card B" || discards[0].Player != 0 {
+		t.Fatalf("Expected P1 to discard Card B, got %v", discards)
+	}
+	for _, h := range duel.State.Players[0].Hand {
+		if h.Card.Name == "Card B" {
+			t.Error("Expected Card B removed from P1's hand")
+		}
+	}
+	if duel.State.RevealedHands[0] {
+		t.Error("Expected P1's hand to be hidden again after resolution")
+	}
+}
+
+// TestGridLink: Grid Link needs its controller's NetGrid, then tutors any card.
+func TestGridLink(t *testing.T) {
+	prize := vanillaAgent("Prize", 4, 1900, 1000, AttrDARK)
+	newDeck := func(top ...*Card) []*Card {
+		deck := makePaddedDeck(top, 39)
+		// Prize sits at the bottom of the deck
+		return append([]*Card{prize}, deck...)
+	}
+
+	t.Run("without NetGrid", func(t *testing.T) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddAction(ActionActivate, "Grid Link")
+
+		cfg := DuelConfig{Deck0: newDeck(GridLink()), Deck1: makePaddedDeck(nil, 40), MaxTurns: 1}
+		_, logger := runDuel(t, cfg, p0, p1)
+
+		if n := len(logger.EventsOfType(log.EventActivate)); n != 0 {
+			t.Errorf("Expected Grid Link not to be activatable, got %d activations", n)
+		}
+	})
+
+	t.Run("with NetGrid", func(t *testing.T) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddAction(ActionActivate, "The Undercity Grid")
+		p0.AddAction(ActionActivate, "Grid Link")
+		p0.AddCardChoice("Prize")
+
+		cfg := DuelConfig{Deck0: newDeck(GridLink(), TheUndercityGrid()), Deck1: makePaddedDeck(nil, 40), MaxTurns: 1}
+		duel, logger := runDuel(t, cfg, p0, p1)
+
+		adds := logger.EventsOfType(log.EventAddToHand)
+		if len(adds) != 1 || adds[0].Card != "Prize" {
+			t.Fatalf("Expected Prize added to hand, got %v", adds)
+		}
+		if n := len(logger.EventsOfType(log.EventShuffle)); n != 1 {
+			t.Errorf("Expected the deck to be shuffled once, got %d", n)
+		}
+		p := duel.State.Players[0]
+		if n := p.DeckCount(); n != 40-6-1 {
+			t.Errorf("Expected %d cards in deck, got %d", 40-6-1, n)
+		}
+		found := false
+		for _, c := range p.Hand {
+			if c.Card.Name == "Prize" {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("Expected Prize in P1's hand")
+		}
+	})
+}
+
+// TestOverrunProtocol: destroying an ATK position agent deals the overkill twice.
+func TestOverrunProtocol(t *testing.T) {
+	striker := vanillaAgent("Striker", 4, 2000, 1000, AttrFIRE)
+	weak := vanillaAgent("Weak Guard", 4, 1200, 1000, AttrEARTH)
+
+	deck0 := makePaddedDeck([]*Card{striker, OverrunProtocol()}, 40)
+	deck1 := makePaddedDeck([]*Card{weak}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Overrun Protocol, summon Striker; Turn 3: attack Weak Guard
+	p0.AddAction(ActionActivate, "Overrun Protocol")
+	p0.AddAction(ActionNormalSummon, "Striker")
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddAttack("Striker", "Weak Guard")
+
+	// Turn 2 (P2): Summon Weak Guard in ATK position
+	p1.AddAction(ActionNormalSummon, "Weak Guard")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 1, "Weak Guard") != nil {
+		t.Fatal("Expected Weak Guard destroyed by battle")
+	}
+	overrun := 0
+	for _, e := range logger.EventsOfType(log.EventHPChange) {
+		if strings.Contains(e.Details, "overrun") {
+			overrun++
+		}
+	}
+	if overrun != 1 {
+		t.Errorf("Expected 1 overrun damage event, got %d", overrun)
+	}
+	// 800 battle damage + 800 follow-through
+	if hp := duel.State.Players[1].HP; hp != StartingHP-1600 {
+		t.Errorf("Expected P2 HP %d, got %d", StartingHP-1600, hp)
+	}
+}
+
+// TestOptimizerSprite: Memory Corruption costs 500 HP instead of 1000.
+func TestOptimizerSprite(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{OptimizerSprite(), MemoryCorruption()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Optimizer Sprite, activate Memory Corruption
+	p0.AddAction(ActionNormalSummon, "Optimizer Sprite")
+	p0.AddAction(ActionActivate, "Memory Corruption")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if n := len(logger.EventsOfType(log.EventDiscard)); n != 2 {
+		t.Fatalf("Expected Memory Corruption to discard 2 cards, got %d", n)
+	}
+	if hp := duel.State.Players[0].HP; hp != StartingHP-500 {
+		t.Errorf("Expected P1 HP %d, got %d", StartingHP-500, hp)
+	}
+}
+
+// TestScrapheapFirewall: Recursive Worm can't return to hand while the opponent's firewall is up.
+func TestScrapheapFirewall(t *testing.T) {
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	// Data Trade is P1's Turn 3 draw
+	deck0 := makePaddedDeck([]*Card{RecursiveWorm(), filler, filler, filler, filler, filler, DataTrade()}, 40)
+	deck1 := makePaddedDeck([]*Card{ScrapheapFirewall()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 3 (P1): Data Trade, discarding Recursive Worm
+	p0.AddAction(ActionActivate, "Data Trade")
+	p0.AddCardChoice("Recursive Worm")
+	// Turn 5 (P1): would return Recursive Worm if asked
+	p0.AddYesNo(true)
+
+	// Turn 2 (P2): Set Scrapheap Firewall; Turn 3: activate it in response to Data Trade
+	p1.AddAction(ActionSetTech, "Scrapheap Firewall")
+	p1.AddAction(ActionActivate, "Scrapheap Firewall")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 5}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	discards := logger.EventsOfType(log.EventDiscard)
+	if len(discards) == 0 || discards[0].Card != "Recursive Worm" {
+		t.Fatalf("Expected Recursive Worm discarded by Data Trade, got %v", discards)
+	}
+	for _, e := range logger.EventsOfType(log.EventAddToHand) {
+		if e.Card == "Recursive Worm" {
+			t.Errorf("Expected Recursive Worm's recovery to be blocked, got %q", e.Details)
+		}
+	}
+	found := false
+	for _, c := range duel.State.Players[0].Scrapheap {
+		if c.Card.Name == "Recursive Worm" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected Recursive Worm to stay in P1's Scrapheap")
+	}
+}
+
+// TestHotSwap: the chosen hand card goes on top of the deck and the old top card
+// is drawn in its place.
+func TestHotSwap(t *testing.T) {
+	keep := vanillaAgent("Keeper", 4, 1000, 1000, AttrLIGHT)
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	next := vanillaAgent("Next Card", 4, 1200, 1000, AttrDARK)
+
+	// Next Card is the top of the deck after the Turn 1 draw
+	deck0 := makePaddedDeck([]*Card{HotSwap(), keep, filler, filler, filler, filler, next}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Hot Swap, sending Keeper back
+	p0.AddAction(ActionActivate, "Hot Swap")
+	p0.AddCardChoice("Keeper")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	p := duel.State.Players[0]
+	if top := p.PeekDeck(); top == nil || top.Card.Name != "Keeper" {
+		t.Errorf("Expected Keeper on top of the deck, got %v", top)
+	}
+	inHand := false
+	for _, c := range p.Hand {
+		if c.Card.Name == "Keeper" {
+			t.Error("Expected Keeper to leave the hand")
+		}
+		if c.Card.Name == "Next Card" {
+			inHand = true
+		}
+	}
+	if !inHand {
+		t.Error("Expected Next Card drawn into the hand")
+	}
+	// 6 cards after the Turn 1 draw, minus Hot Swap itself
+	if n := len(p.Hand); n != 5 {
+		t.Errorf("Expected 5 cards in hand, got %d", n)
+	}
+	if n := len(logger.EventsOfType(log.EventReturnToDeck)); n != 1 {
+		t.Errorf("Expected 1 return-to-deck event, got %d", n)
+	}
+}
+
+// TestSummonTax: the second summon in a turn costs its summoner 500 HP; the first is free.
+func TestSummonTax(t *testing.T) {
+	flipper := vanillaAgent("Flipper", 4, 1000, 1000, AttrLIGHT)
+	yonder := vanillaAgent("Yonder", 4, 1200, 1000, AttrDARK)
+
+	deck0 := makePaddedDeck([]*Card{SummonTax(), flipper, yonder}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Summon Tax and Flipper
+	p0.AddAction(ActionSetTech, "Summon Tax")
+	p0.AddAction(ActionNormalSet, "Flipper")
+	// Turn 3 (P1): Activate Summon Tax, flip summon Flipper, normal summon Yonder
+	p0.AddAction(ActionActivate, "Summon Tax")
+	p0.AddAction(ActionFlipSummon, "Flipper")
+	p0.AddAction(ActionNormalSummon, "Yonder")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Yonder") == nil {
+		t.Fatal("Expected Yonder to be summoned")
+	}
+	if n := duel.State.AgentsSummonedThisTurn[0]; n != 2 {
+		t.Errorf("Expected 2 summons counted for P1 this turn, got %d", n)
+	}
+	taxed := 0
+	for _, e := range logger.EventsOfType(log.EventHPChange) {
+		if strings.Contains(e.Details, "Summon Tax") {
+			taxed++
+		}
+	}
+	if taxed != 1 {
+		t.Errorf("Expected Summon Tax to fire once, got %d", taxed)
+	}
+	if hp := duel.State.Players[0].HP; hp != StartingHP-500 {
+		t.Errorf("Expected P1 HP %d, got %d", StartingHP-500, hp)
+	}
+}
+
+// TestPhoenixProcess: destroyed by battle, it goes on top of the deck and is
+// drawn next turn instead of landing in the scrapheap.
+func TestPhoenixProcess(t *testing.T) {
+	striker := vanillaAgent("Striker", 4, 2500, 500, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{PhoenixProcess()}, 40)
+	deck1 := makePaddedDeck([]*Card{striker}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Phoenix Process
+	p0.AddAction(ActionNormalSummon, "Phoenix Process")
+
+	// Turn 2 (P2): Striker destroys it by battle
+	p1.AddAction(ActionNormalSummon, "Striker")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddAttack("Striker", "Phoenix Process")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	p := duel.State.Players[0]
+	if findAgent(duel, 0, "Phoenix Process") != nil {
+		t.Fatal("Expected Phoenix Process destroyed by Striker's attack")
+	}
+	for _, c := range p.Scrapheap {
+		if c.Card.Name == "Phoenix Process" {
+			t.Error("Expected Phoenix Process not to be in the scrapheap")
+		}
+	}
+	// Turn 3 draw picks it back up from the top of the deck
+	drawn := p.Hand[len(p.Hand)-1]
+	if drawn.Card.Name != "Phoenix Process" {
+		t.Errorf("Expected Phoenix Process as P1's Turn 3 draw, got %s", drawn.Card.Name)
+	}
+	if n := len(logger.EventsOfType(log.EventReturnToDeck)); n != 1 {
+		t.Errorf("Expected 1 return-to-deck event, got %d", n)
+	}
+}
+
+// TestRecalibrate: draws 2 and returns the chosen one to the deck edge picked,
+// for a net +1 card in hand.
+func TestRecalibrate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		top  bool
+	}{
+		{"top", true},
+		{"bottom", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := vanillaAgent("Card A", 4, 1000, 1000, AttrLIGHT)
+			b := vanillaAgent("Card B", 4, 1000, 1000, AttrLIGHT)
+			filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+
+			// Card A and Card B are the two cards under P1's Turn 1 draw
+			deck0 := makePaddedDeck([]*Card{Recalibrate(), filler, filler, filler, filler, filler, a, b}, 40)
+			deck1 := makePaddedDeck(nil, 40)
+
+			p0 := NewScriptedController(t, "P1")
+			p1 := NewScriptedController(t, "P2")
+
+			p0.AddAction(ActionActivate, "Recalibrate")
+			p0.AddCardChoice("Card B")
+			p0.AddYesNo(tc.top)
+
+			cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+			duel, _ := runDuel(t, cfg, p0, p1)
+
+			p := duel.State.Players[0]
+			// 6 after the Turn 1 draw, minus Recalibrate, plus 2 drawn, minus 1 returned
+			if n := len(p.Hand); n != 6 {
+				t.Errorf("Expected 6 cards in hand, got %d", n)
+			}
+			for _, c := range p.Hand {
+				if c.Card.Name == "Card B" {
+					t.Error("Expected Card B to leave the hand")
+				}
+			}
+			edge := p.Deck[0]
+			if tc.top {
+				edge = p.PeekDeck()
+			}
+			if edge.Card.Name != "Card B" {
+				t.Errorf("Expected Card B on the %s of the deck, got %s", tc.name, edge.Card.Name)
+			}
+		})
+	}
+}
+
+// TestEmergencyShields: the first losing battle of a turn leaves the agent on
+// the field with no damage taken; the shield is spent, so the second goes through.
+func TestEmergencyShields(t *testing.T) {
+	guard := vanillaAgent("Guard Bot", 4, 1000, 1000, AttrEARTH)
+	strikerA := vanillaAgent("Striker A", 4, 2500, 500, AttrFIRE)
+	strikerB := vanillaAgent("Striker B", 4, 2000, 500, AttrFIRE)
+
+	// Striker B is P2's Turn 4 draw
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{EmergencyShields(), guard}, 40)
+	deck1 := makePaddedDeck([]*Card{strikerA, filler, filler, filler, filler, filler, strikerB}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Emergency Shields, summon Guard Bot
+	p0.AddAction(ActionActivate, "Emergency Shields")
+	p0.AddAction(ActionNormalSummon, "Guard Bot")
+
+	// Turn 2 (P2): Summon Striker A; Turn 4: Summon Striker B, both attack Guard Bot
+	p1.AddAction(ActionNormalSummon, "Striker A")
+	p1.AddAction(ActionNormalSummon, "Striker B")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddAttack("Striker A", "Guard Bot")
+	p1.AddAttack("Striker B", "Guard Bot")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Guard Bot") != nil {
+		t.Error("Expected Guard Bot destroyed by the second attack")
+	}
+	// Only Striker B's battle (2000 vs 1000) deals damage
+	if hp := duel.State.Players[0].HP; hp != StartingHP-1000 {
+		t.Errorf("Expected P1 HP %d, got %d", StartingHP-1000, hp)
+	}
+	if n := len(logger.EventsOfType(log.EventBattleDestroy)); n != 1 {
+		t.Errorf("Expected 1 battle destruction, got %d", n)
+	}
+}
+
+// TestDataOverload: P2 takes 100 per card in hand at their End Phase; P1's own
+// End Phase deals nothing.
+func TestDataOverload(t *testing.T) {
+	grunt := vanillaAgent("Grunt", 4, 1000, 1000, AttrEARTH)
+
+	deck0 := makePaddedDeck([]*Card{DataOverload()}, 40)
+	deck1 := makePaddedDeck([]*Card{grunt}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Data Overload; Turn 3: Activate it
+	p0.AddAction(ActionSetTech, "Data Overload")
+	p0.AddAction(ActionActivate, "Data Overload")
+
+	// Turn 2 (P2): Summon Grunt, so P2 ends Turn 4 with 6 cards in hand
+	p1.AddAction(ActionNormalSummon, "Grunt")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	burns := 0
+	for _, e := range logger.EventsOfType(log.EventHPChange) {
+		if strings.Contains(e.Details, "Data Overload") {
+			burns++
+			if e.Turn != 4 {
+				t.Errorf("Expected Data Overload to fire only on P2's Turn 4, fired on Turn %d", e.Turn)
+			}
+		}
+	}
+	if burns != 1 {
+		t.Errorf("Expected Data Overload to fire once, got %d", burns)
+	}
+	if hp := duel.State.Players[1].HP; hp != StartingHP-600 {
+		t.Errorf("Expected P2 HP %d, got %d", StartingHP-600, hp)
+	}
+	if hp := duel.State.Players[0].HP; hp != StartingHP {
+		t.Errorf("Expected P1 HP unchanged, got %d", hp)
+	}
+}
+
+// TestEvolvingConstruct: after three of P1's Standby Phases it has 2200 ATK and
+// pierces a 2000 DEF wall.
+func TestEvolvingConstruct(t *testing.T) {
+	wall := vanillaAgent("Wall", 4, 0, 2000, AttrEARTH)
+	gate := vanillaAgent("Gate Drone", 1, 100, 100, AttrLIGHT)
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+
+	// Gate Drone is P1's Turn 7 draw; summoning it holds the attack until then
+	deck0 := makePaddedDeck([]*Card{EvolvingConstruct(), filler, filler, filler, filler, filler, filler, filler, gate}, 40)
+	deck1 := makePaddedDeck([]*Card{wall}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Evolving Construct; Turn 7: attack the Wall
+	p0.AddAction(ActionNormalSummon, "Evolving Construct")
+	p0.AddAction(ActionNormalSummon, "Gate Drone")
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddAttack("Evolving Construct", "Wall")
+
+	// Turn 2 (P2): Set the Wall
+	p1.AddAction(ActionNormalSet, "Wall")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 7}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	construct := findAgent(duel, 0, "Evolving Construct")
+	if construct == nil {
+		t.Fatal("Expected Evolving Construct on the field")
+	}
+	if n := construct.Counters["evolution"]; n != 3 {
+		t.Errorf("Expected 3 evolution counters, got %d", n)
+	}
+	if atk := construct.CurrentATK(); atk != 2200 {
+		t.Errorf("Expected ATK 2200, got %d", atk)
+	}
+	if findAgent(duel, 1, "Wall") != nil {
+		t.Error("Expected the Wall destroyed")
+	}
+	if hp := duel.State.Players[1].HP; hp != StartingHP-200 {
+		t.Errorf("Expected 200 piercing damage, got P2 HP %d", hp)
+	}
+}
+
+// TestFeedbackLoop: as Chain Link 3 it deals 3 × 300 damage.
+func TestFeedbackLoop(t *testing.T) {
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	// Greed Protocol is P1's Turn 3 draw
+	deck0 := makePaddedDeck([]*Card{FeedbackLoop(), filler, filler, filler, filler, filler, GreedProtocol()}, 40)
+	deck1 := makePaddedDeck([]*Card{sparkTrap()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Feedback Loop; Turn 3: Greed Protocol (CL1), Feedback Loop (CL3)
+	p0.AddAction(ActionSetTech, "Feedback Loop")
+	p0.AddAction(ActionActivate, "Greed Protocol")
+	p0.AddAction(ActionActivate, "Feedback Loop")
+
+	// Turn 2 (P2): Set Spark Trap; Turn 3: chain it (CL2)
+	p1.AddAction(ActionSetTech, "Spark Trap")
+	p1.AddAction(ActionActivate, "Spark Trap")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	links := logger.EventsOfType(log.EventChainLink)
+	if len(links) != 3 || links[2].Card != "Feedback Loop" {
+		t.Fatalf("Expected a 3-link chain topped by Feedback Loop, got %v", links)
+	}
+	if hp := duel.State.Players[1].HP; hp != StartingHP-900 {
+		t.Errorf("Expected P2 HP %d, got %d", StartingHP-900, hp)
+	}
+	if hp := duel.State.Players[0].HP; hp != StartingHP-300 {
+		t.Errorf("Expected P1 HP %d from Spark Trap, got %d", StartingHP-300, hp)
+	}
+}
+
+// TestRapidFabricator: summoned from deep in the Deck on Turn 1, it costs P1 the
+// Turn 3 draw.
+func TestRapidFabricator(t *testing.T) {
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	top := []*Card{filler, filler, filler, filler, filler, filler, filler, filler, filler, filler, RapidFabricator()}
+	deck0 := makePaddedDeck(top, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	p0.AddAction(ActionActivate, "Rapid Fabricator")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Rapid Fabricator") == nil {
+		t.Fatal("Expected Rapid Fabricator summoned from the deck")
+	}
+	p := duel.State.Players[0]
+	// 6 cards after the Turn 1 draw, and no draw on Turn 3
+	if n := len(p.Hand); n != 6 {
+		t.Errorf("Expected 6 cards in hand, got %d", n)
+	}
+	if n := len(p.Deck); n != 40-6-1 {
+		t.Errorf("Expected %d cards in deck, got %d", 40-6-1, n)
+	}
+	for _, e := range logger.EventsOfType(log.EventDraw) {
+		if e.Turn == 3 {
+			t.Errorf("Expected P1's Turn 3 draw to be skipped, got %q", e.Details)
+		}
+	}
+	if n := len(logger.EventsOfType(log.EventSkipDraw)); n != 1 {
+		t.Errorf("Expected 1 skipped draw, got %d", n)
+	}
+	if p.SkipNextDraw {
+		t.Error("Expected SkipNextDraw cleared after the skipped draw")
+	}
+}
+
+// TestBodyguardSplice: P1 discards Bodyguard Splice when P2 attacks directly;
+// the attack ends and Bodyguard Splice lands in DEF.
+func TestBodyguardSplice(t *testing.T) {
+	striker := vanillaAgent("Striker", 4, 1800, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{BodyguardSplice()}, 40)
+	deck1 := makePaddedDeck([]*Card{striker}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	p0.AddAction(ActionActivate, "Bodyguard Splice")
+
+	// Turn 2 (P2): Summon Striker and attack directly
+	p1.AddAction(ActionNormalSummon, "Striker")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddDirectAttack("Striker")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if hp := duel.State.Players[0].HP; hp != StartingHP {
+		t.Errorf("Expected P1 HP %d, got %d", StartingHP, hp)
+	}
+	guard := findAgent(duel, 0, "Bodyguard Splice")
+	if guard == nil {
+		t.Fatal("Expected Bodyguard Splice special summoned")
+	}
+	if guard.Position != PositionDEF || guard.Face != FaceUp {
+		t.Error("Expected Bodyguard Splice in face-up DEF")
+	}
+	if n := len(logger.EventsOfType(log.EventAttackStopped)); n != 1 {
+		t.Errorf("Expected 1 stopped attack, got %d", n)
+	}
+}
+
+// cardPromptRecorder wraps a ScriptedController and records the candidate
+// names offered on each ChooseCards prompt.
+type cardPromptRecorder struct {
+	*ScriptedController
+	prompts [][]string
+}
+
+func (cr *cardPromptRecorder) ChooseCards(ctx context.Context, state *GameState, prompt string, candidates []*CardInstance, min, max int) ([]*CardInstance, error) {
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Card.Name)
+	}
+	cr.prompts = append(cr.prompts, names)
+	return cr.ScriptedController.ChooseCards(ctx, state, prompt, candidates, min, max)
+}
+
+// TestHeadshotRoutineTieIncludesOwnAgent: when P1's own agent ties the
+// opponent's for highest ATK, both are offered and P1 may destroy their own.
+func TestHeadshotRoutineTieIncludesOwnAgent(t *testing.T) {
+	own := vanillaAgent("Own Striker", 4, 1800, 1000, AttrFIRE)
+	opp := vanillaAgent("Opp Striker", 4, 1800, 1000, AttrDARK)
+
+	// Headshot Routine is P1's Turn 3 draw
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{own, filler, filler, filler, filler, filler, HeadshotRoutine()}, 40)
+	deck1 := makePaddedDeck([]*Card{opp}, 40)
+
+	p0 := &cardPromptRecorder{ScriptedController: NewScriptedController(t, "P1")}
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Own Striker; Turn 3: Headshot Routine, destroying it
+	p0.AddAction(ActionNormalSummon, "Own Striker")
+	p0.AddAction(ActionActivate, "Headshot Routine")
+	p0.AddCardChoice("Own Striker")
+
+	// Turn 2 (P2): Summon Opp Striker
+	p1.AddAction(ActionNormalSummon, "Opp Striker")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	if len(p0.prompts) != 1 {
+		t.Fatalf("Expected 1 tie-break prompt, got %d", len(p0.prompts))
+	}
+	// Stable order: P1's zones before P2's
+	if got := p0.prompts[0]; len(got) != 2 || got[0] != "Own Striker" || got[1] != "Opp Striker" {
+		t.Errorf("Expected candidates [Own Striker Opp Striker], got %v", got)
+	}
+	if findAgent(duel, 0, "Own Striker") != nil {
+		t.Error("Expected Own Striker destroyed")
+	}
+	if findAgent(duel, 1, "Opp Striker") == nil {
+		t.Error("Expected Opp Striker to survive")
+	}
+}
+
+// TestCloakGrid: with P2's Cloak Grid active, P1's Trace and Terminate can only
+// target P1's own face-down agent, not P2's.
+func TestCloakGrid(t *testing.T) {
+	decoy := vanillaAgent("Own Decoy", 3, 1000, 1000, AttrLIGHT)
+	hidden := vanillaAgent("Hidden Bot", 4, 1500, 1500, AttrDARK)
+
+	// Trace and Terminate is P1's Turn 3 draw
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{decoy, filler, filler, filler, filler, filler, TraceAndTerminate()}, 40)
+	deck1 := makePaddedDeck([]*Card{CloakGrid(), hidden}, 40)
+
+	p0 := &cardPromptRecorder{ScriptedController: NewScriptedController(t, "P1")}
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Own Decoy; Turn 3: Trace and Terminate
+	p0.AddAction(ActionNormalSet, "Own Decoy")
+	p0.AddAction(ActionActivate, "Trace and Terminate")
+
+	// Turn 2 (P2): Cloak Grid, set Hidden Bot
+	p1.AddAction(ActionActivate, "Cloak Grid")
+	p1.AddAction(ActionNormalSet, "Hidden Bot")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	if len(p0.prompts) != 1 {
+		t.Fatalf("Expected 1 target prompt, got %d", len(p0.prompts))
+	}
+	if got := p0.prompts[0]; len(got) != 1 || got[0] != "Own Decoy" {
+		t.Errorf("Expected only [Own Decoy] targetable, got %v", got)
+	}
+	if findAgent(duel, 1, "Hidden Bot") == nil {
+		t.Error("Expected Hidden Bot to stay on the field")
+	}
+}
+
+// TestMedPatchMaxHP: Med-Patch heals 1000, clamped at DuelConfig.MaxHP when set.
+func TestMedPatchMaxHP(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		maxHP int
+		want  int
+	}{
+		{"uncapped", 0, StartingHP + 1000},
+		{"capped", StartingHP + 500, StartingHP + 500},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			deck0 := makePaddedDeck([]*Card{MedPatch()}, 40)
+			deck1 := makePaddedDeck(nil, 40)
+
+			p0 := NewScriptedController(t, "P1")
+			p1 := NewScriptedController(t, "P2")
+			p0.AddAction(ActionActivate, "Med-Patch")
+
+			cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1, MaxHP: tc.maxHP}
+			duel, _ := runDuel(t, cfg, p0, p1)
+
+			if hp := duel.State.Players[0].HP; hp != tc.want {
+				t.Errorf("Expected P1 HP %d, got %d", tc.want, hp)
+			}
+		})
+	}
+}
+
+// TestQuantumDraw: the revealed top card's Level sets how many cards are drawn,
+// and the revealed card goes back into the deck.
+func TestQuantumDraw(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		revealed *Card
+		draws    int
+	}{
+		{"level 4 or below", vanillaAgent("Revealed Card", 4, 1000, 1000, AttrLIGHT), 1},
+		{"non-agent", GreedProtocol(), 1},
+		{"level 5-6", vanillaAgent("Revealed Card", 6, 2000, 1000, AttrLIGHT), 2},
+		{"level 7+", vanillaAgent("Revealed Card", 8, 3000, 2500, AttrLIGHT), 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The revealed card is the top card after P1's Turn 1 draw
+			filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+			deck0 := makePaddedDeck([]*Card{QuantumDraw(), filler, filler, filler, filler, filler, tc.revealed}, 40)
+			deck1 := makePaddedDeck(nil, 40)
+
+			p0 := NewScriptedController(t, "P1")
+			p1 := NewScriptedController(t, "P2")
+			p0.AddAction(ActionActivate, "Quantum Draw")
+
+			cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+			duel, logger := runDuel(t, cfg, p0, p1)
+
+			// Count draws outside the Draw Phase; the End Phase hand limit may discard some
+			drawn := 0
+			for _, e := range logger.EventsOfType(log.EventDraw) {
+				if e.Phase == PhaseMain1.String() {
+					drawn++
+					if e.Card == "Revealed Card" || e.Card == "Greed Protocol" {
+						t.Error("Expected the revealed card back in the deck, not drawn")
+					}
+				}
+			}
+			if drawn != tc.draws {
+				t.Errorf("Expected %d draws, got %d", tc.draws, drawn)
+			}
+			p := duel.State.Players[0]
+			if n := len(p.Deck); n != 40-6-tc.draws {
+				t.Errorf("Expected %d cards in deck, got %d", 40-6-tc.draws, n)
+			}
+			if n := len(logger.EventsOfType(log.EventRevealCard)); n != 1 {
+				t.Errorf("Expected 1 reveal, got %d", n)
+			}
+		})
+	}
+}
+
+// TestQuantumDrawDeckOut: drawing past the end of the Deck loses the duel.
+func TestQuantumDrawDeckOut(t *testing.T) {
+	// After P1's Turn 1 draw, a Level 8 is revealed with 1 card under it
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	revealed := vanillaAgent("Revealed Card", 8, 3000, 2500, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{QuantumDraw(), filler, filler, filler, filler, filler, revealed, filler}, 8)
+
+	p0 := NewScriptedController(t, "P1")
+	p0.AddAction(ActionActivate, "Quantum Draw")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: makePaddedDeck(nil, 40), MaxTurns: 1}
+	duel, _ := runDuel(t, cfg, p0, NewScriptedController(t, "P2"))
+
+	if gs := duel.State; !gs.Over || gs.Winner != 1 || gs.WinReason != WinReasonDeckout {
+		t.Errorf("Expected P1 to deck out, got %q", gs.Result)
+	}
+}
+
+// TestKnockdownPulse: P1's set Knockdown Pulse switches P2's attacking Striker
+// to DEF, ending the attack; the forced change doesn't use up its own change.
+func TestKnockdownPulse(t *testing.T) {
+	striker := vanillaAgent("Striker", 4, 1800, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{KnockdownPulse()}, 40)
+	deck1 := makePaddedDeck([]*Card{striker}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Knockdown Pulse; Turn 2: activate it on Striker's attack
+	p0.AddAction(ActionSetTech, "Knockdown Pulse")
+	p0.AddAction(ActionActivate, "Knockdown Pulse")
+
+	// Turn 2 (P2): Summon Striker, attack directly
+	p1.AddAction(ActionNormalSummon, "Striker")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddDirectAttack("Striker")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if hp := duel.State.Players[0].HP; hp != StartingHP {
+		t.Errorf("Expected P1 HP %d, got %d", StartingHP, hp)
+	}
+	s := findAgent(duel, 1, "Striker")
+	if s == nil {
+		t.Fatal("Expected Striker on the field")
+	}
+	if s.Position != PositionDEF {
+		t.Errorf("Expected Striker in DEF, got %s", s.Position)
+	}
+	if s.PositionChangedThisTurn {
+		t.Error("Expected the forced change not to count as Striker's position change")
+	}
+	if n := len(logger.EventsOfType(log.EventAttackStopped)); n != 1 {
+		t.Errorf("Expected 1 stopped attack, got %d", n)
+	}
+}
+
+// TestSacrificialNode: sacrificing Sacrificial Node for a Level 5 agent draws
+// its controller 1 card.
+func TestSacrificialNode(t *testing.T) {
+	heavy := vanillaAgent("Heavy Unit", 5, 2300, 1800, AttrEARTH)
+	bonus := vanillaAgent("Bonus Card", 1, 100, 100, AttrLIGHT)
+
+	// Bonus Card is the top card after P1's Turn 3 draw
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{SacrificialNode(), heavy, filler, filler, filler, filler, filler, bonus}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Sacrificial Node; Turn 3: sacrifice it for Heavy Unit
+	p0.AddAction(ActionNormalSummon, "Sacrificial Node")
+	p0.AddAction(ActionSacrificeSummon, "Heavy Unit")
+	p0.AddCardChoice("Sacrificial Node")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Heavy Unit") == nil {
+		t.Fatal("Expected Heavy Unit sacrifice summoned")
+	}
+	found := false
+	for _, c := range duel.State.Players[0].Hand {
+		if c.Card.Name == "Bonus Card" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected Sacrificial Node to draw Bonus Card")
+	}
+}
+
+// TestSacrificialNodeDeckOut: with P1's deck empty by Turn 3, Sacrificial
+// Node's draw decks them out.
+func TestSacrificialNodeDeckOut(t *testing.T) {
+	heavy := vanillaAgent("Heavy Unit", 5, 2300, 1800, AttrEARTH)
+	deck0 := makePaddedDeck([]*Card{SacrificialNode(), heavy}, 7)
+
+	p0 := NewScriptedController(t, "P1")
+	p0.AddAction(ActionNormalSummon, "Sacrificial Node")
+	p0.AddAction(ActionSacrificeSummon, "Heavy Unit")
+	p0.AddCardChoice("Sacrificial Node")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: makePaddedDeck(nil, 40), MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, NewScriptedController(t, "P2"))
+
+	if gs := duel.State; !gs.Over || gs.Winner != 1 || gs.WinReason != WinReasonDeckout {
+		t.Errorf("Expected P1 to deck out, got %q", gs.Result)
+	}
+}
+
+// TestContestedDig: P2 picks which of P1's top 3 cards P1 keeps; the other two are milled.
+func TestContestedDig(t *testing.T) {
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	digA := vanillaAgent("Dig A", 4, 1000, 1000, AttrLIGHT)
+	digB := vanillaAgent("Dig B", 7, 2500, 2000, AttrLIGHT)
+	digC := vanillaAgent("Dig C", 4, 1200, 1000, AttrLIGHT)
+	// The dug cards are the top 3 after P1's Turn 1 draw
+	deck0 := makePaddedDeck([]*Card{ContestedDig(), filler, filler, filler, filler, filler, digA, digB, digC}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	p0.AddAction(ActionActivate, "Contested Dig")
+	p1.AddCardChoice("Dig B")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	p := duel.State.Players[0]
+	has := func(cards []*CardInstance, name string) bool {
+		for _, c := range cards {
+			if c.Card.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	if !has(p.Hand, "Dig B") {
+		t.Error("Expected the opponent's pick Dig B in P1's hand")
+	}
+	for _, name := range []string{"Dig A", "Dig C"} {
+		if !has(p.Scrapheap, name) {
+			t.Errorf("Expected %s in P1's scrapheap", name)
+		}
+	}
+	if n := len(p.Deck); n != 40-6-3 {
+		t.Errorf("Expected %d cards in deck, got %d", 40-6-3, n)
+	}
+	if n := len(logger.EventsOfType(log.EventRevealCard)); n != 3 {
+		t.Errorf("Expected 3 reveals, got %d", n)
+	}
+}
+
+// directAttackRecorder records every direct attack it is offered.
+type directAttackRecorder struct {
+	*ScriptedController
+	offered int
+}
+
+func (dr *directAttackRecorder) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	for _, a := range actions {
+		if a.Type == ActionDirectAttack {
+			dr.offered++
+		}
+	}
+	return dr.ScriptedController.ChooseAction(ctx, state, actions)
+}
+
+// TestDualFangUnit: Dual-Fang Unit gets no direct attack after clearing P2's
+// field on Turn 3, then attacks P2's set Iron Wall twice on Turn 5.
+func TestDualFangUnit(t *testing.T) {
+	dummy := vanillaAgent("Dummy", 2, 500, 500, AttrLIGHT)
+	wall := vanillaAgent("Iron Wall", 4, 0, 2500, AttrEARTH)
+
+	deck0 := makePaddedDeck([]*Card{DualFangUnit()}, 40)
+	deck1 := makePaddedDeck([]*Card{dummy, wall}, 40)
+
+	p0 := &directAttackRecorder{ScriptedController: NewScriptedController(t, "P1")}
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Dual-Fang Unit; Turn 3: destroy Dummy
+	p0.AddAction(ActionNormalSummon, "Dual-Fang Unit")
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddAttack("Dual-Fang Unit", "Dummy")
+	// Turn 5: attack Iron Wall twice
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddAttack("Dual-Fang Unit", "Iron Wall")
+	p0.AddAttack("Dual-Fang Unit", "Iron Wall")
+
+	// Turn 2 (P2): Summon Dummy; Turn 4: Set Iron Wall
+	p1.AddAction(ActionNormalSummon, "Dummy")
+	p1.AddAction(ActionNormalSet, "Iron Wall")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 5}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	attacks := 0
+	for _, e := range logger.EventsOfType(log.EventAttackDeclare) {
+		if e.Turn == 5 {
+			attacks++
+		}
+	}
+	if attacks != 2 {
+		t.Errorf("Expected 2 attacks on Turn 5, got %d", attacks)
+	}
+	if want := StartingHP - 2*800; duel.State.Players[0].HP != want {
+		t.Errorf("Expected P1 HP %d after two attacks into Iron Wall, got %d", want, duel.State.Players[0].HP)
+	}
+	if p0.offered != 0 {
+		t.Errorf("Expected no direct attack to be offered, got %d", p0.offered)
+	}
+	if n := len(logger.EventsOfType(log.EventDirectAttackDeclare)); n != 0 {
+		t.Errorf("Expected no direct attacks, got %d", n)
+	}
+}
+
+// TestOncePerTurnIgnition: Ultimate Street Punk's burn can't be activated twice
+// on Turn 5 despite a second FIRE agent to sacrifice, but is available again on Turn 7.
+func TestOncePerTurnIgnition(t *testing.T) {
+	fireA := vanillaAgent("Fire A", 4, 1000, 1000, AttrFIRE)
+	fireB := vanillaAgent("Fire B", 4, 1000, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{UltimateStreetPunk(), fireA, fireB}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turns 1, 3, 5 (P1): Summon Ultimate Street Punk, Fire A, Fire B
+	p0.AddAction(ActionNormalSummon, "Ultimate Street Punk")
+	p0.AddAction(ActionNormalSummon, "Fire A")
+	p0.AddAction(ActionNormalSummon, "Fire B")
+	// Turn 5: burn; the second activation has to wait for Turn 7
+	p0.AddAction(ActionActivate, "Ultimate Street Punk")
+	p0.AddAction(ActionActivate, "Ultimate Street Punk")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 7}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	var turns []int
+	for _, e := range logger.EventsOfType(log.EventActivate) {
+		if e.Card == "Ultimate Street Punk effect" {
+			turns = append(turns, e.Turn)
+		}
+	}
+	if len(turns) != 2 || turns[0] != 5 || turns[1] != 7 {
+		t.Errorf("Expected activations on Turns 5 and 7, got %v", turns)
+	}
+	if want := StartingHP - 1000; duel.State.Players[1].HP != want {
+		t.Errorf("Expected P2 HP %d, got %d", want, duel.State.Players[1].HP)
+	}
+}
+
+// TestDefragment: three programs in P1's scrapheap are shuffled back into the Deck.
+func TestDefragment(t *testing.T) {
+	blank := &Card{
+		Name:       "Blank Program",
+		CardType:   CardTypeProgram,
+		ProgramSub: ProgramNormal,
+		Effects: []*CardEffect{{
+			Name:      "Blank Program",
+			ExecSpeed: ExecSpeed1,
+			Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+				return nil
+			},
+		}},
+	}
+	deck0 := makePaddedDeck([]*Card{GreedProtocol(), MedPatch(), blank, Defragment()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	p0.AddAction(ActionActivate, "Greed Protocol")
+	p0.AddAction(ActionActivate, "Med-Patch")
+	p0.AddAction(ActionActivate, "Blank Program")
+	p0.AddAction(ActionActivate, "Defragment")
+	p0.AddCardChoice("Greed Protocol", "Med-Patch", "Blank Program")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	// 40 - 5 in hand - 1 drawn - 2 from Greed Protocol, then 3 shuffled back
+	p := duel.State.Players[0]
+	if n := len(p.Deck); n != 32+3 {
+		t.Errorf("Expected %d cards in deck, got %d", 32+3, n)
+	}
+	if n := len(logger.EventsOfType(log.EventReturnToDeck)); n != 3 {
+		t.Errorf("Expected 3 cards returned to the deck, got %d", n)
+	}
+	if n := len(logger.EventsOfType(log.EventShuffle)); n != 1 {
+		t.Errorf("Expected 1 shuffle, got %d", n)
+	}
+	for _, c := range p.Scrapheap {
+		if c.Card.Name != "Defragment" {
+			t.Errorf("Expected only Defragment left in the scrapheap, found %s", c.Card.Name)
+		}
+	}
+}
+
+// TestFortressProtocol: Bastion gains DEF equal to its 1800 ATK once switched
+// to DEF Position on Turn 3, and loses it when switched back on Turn 5.
+func TestFortressProtocol(t *testing.T) {
+	play := func(maxTurns int) *CardInstance {
+		bastion := vanillaAgent("Bastion", 4, 1800, 1200, AttrEARTH)
+		deck0 := makePaddedDeck([]*Card{bastion, FortressProtocol()}, 40)
+		deck1 := makePaddedDeck(nil, 40)
+
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		// Turn 1: Summon Bastion, activate Fortress Protocol; Turns 3 and 5: switch position
+		p0.AddAction(ActionNormalSummon, "Bastion")
+		p0.AddAction(ActionActivate, "Fortress Protocol")
+		p0.AddAction(ActionChangePosition, "Bastion")
+		p0.AddAction(ActionEndTurn, "")
+		p0.AddAction(ActionChangePosition, "Bastion")
+
+		cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: maxTurns}
+		duel, _ := runDuel(t, cfg, p0, p1)
+		return findAgent(duel, 0, "Bastion")
+	}
+
+	if b := play(1); b == nil || b.CurrentDEF() != 1200 {
+		t.Fatalf("Expected Bastion in ATK Position with 1200 DEF on Turn 1, got %v", b)
+	}
+	if b := play(3); b.Position != PositionDEF || b.CurrentDEF() != 3000 {
+		t.Errorf("Expected Bastion in DEF Position with 3000 DEF, got %s with %d", b.Position, b.CurrentDEF())
+	}
+	if b := play(5); b.Position != PositionATK || b.CurrentDEF() != 1200 {
+		t.Errorf("Expected Bastion back in ATK Position with 1200 DEF, got %s with %d", b.Position, b.CurrentDEF())
+	}
+}
+
+// TestSectorLockdownRevertsPositions: Striker, forced to DEF by P2's Sector
+// Lockdown, returns to ATK once ICE Breaker destroys it, attacks, and can later
+// change position normally.
+func TestSectorLockdownRevertsPositions(t *testing.T) {
+	striker := vanillaAgent("Striker", 4, 1900, 1200, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{striker, ICEBreaker()}, 40)
+	deck1 := makePaddedDeck([]*Card{SectorLockdownZoneB()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Striker
+	p0.AddAction(ActionNormalSummon, "Striker")
+	// Turn 3: ICE Breaker on Sector Lockdown, then attack directly
+	p0.AddAction(ActionActivate, "ICE Breaker")
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddDirectAttack("Striker")
+	p0.AddAction(ActionEndTurn, "")
+	// Turn 5: switch Striker to DEF
+	p0.AddAction(ActionChangePosition, "Striker")
+
+	// Turn 2 (P2): Activate Sector Lockdown
+	p1.AddAction(ActionActivate, "Sector Lockdown - Zone B")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 5}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	var turns []int
+	for _, e := range logger.EventsOfType(log.EventChangePosition) {
+		turns = append(turns, e.Turn)
+	}
+	if len(turns) != 2 || turns[0] != 3 || turns[1] != 5 {
+		t.Errorf("Expected Striker to revert on Turn 3 and switch on Turn 5, got changes on turns %v", turns)
+	}
+	if want := StartingHP - 1900; duel.State.Players[1].HP != want {
+		t.Errorf("Expected Striker's direct attack to leave P2 at %d, got %d", want, duel.State.Players[1].HP)
+	}
+	if s := findAgent(duel, 0, "Striker"); s == nil || s.Position != PositionDEF {
+		t.Errorf("Expected Striker switched to DEF on Turn 5, got %v", s)
+	}
+}
+
+// TestAegisPulse: P1 answers Raider's attack by tributing its only agent to
+// Aegis Pulse, so the replayed direct attack deals no damage; Raider's next
+// direct attack deals full damage.
+func TestAegisPulse(t *testing.T) {
+	fodder := vanillaAgent("Fodder", 3, 1000, 1000, AttrEARTH)
+	raider := vanillaAgent("Raider", 4, 1800, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{fodder, AegisPulse()}, 40)
+	deck1 := makePaddedDeck([]*Card{raider}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Fodder, set Aegis Pulse
+	p0.AddAction(ActionNormalSummon, "Fodder")
+	p0.AddAction(ActionSetTech, "Aegis Pulse")
+	p0.AddAction(ActionEndTurn, "")
+	// Turn 2: activate Aegis Pulse in response to Raider's attack
+	p0.AddAction(ActionActivate, "Aegis Pulse")
+
+	// Turn 2 (P2): Summon Raider, attack Fodder, then attack directly on the replay
+	p1.AddAction(ActionNormalSummon, "Raider")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddAttack("Raider", "Fodder")
+	p1.AddDirectAttack("Raider")
+	p1.AddAction(ActionEndTurn, "")
+	// Turn 4: attack directly again
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddDirectAttack("Raider")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Fodder") != nil {
+		t.Fatal("Expected Fodder to be tributed for Aegis Pulse")
+	}
+	if blocked := logger.EventsOfType(log.EventDamageBlocked); len(blocked) != 1 || blocked[0].Turn != 2 {
+		t.Errorf("Expected Raider's Turn 2 damage to be blocked, got %v", blocked)
+	}
+	if want := StartingHP - 1800; duel.State.Players[0].HP != want {
+		t.Errorf("Expected only the Turn 4 attack to hit P1 (HP %d), got %d", want, duel.State.Players[0].HP)
+	}
+}
+
+// TestCounterHack: P1 activates Counter-Hack when Brute attacks Ember; Ember's
+// destruction by battle inflicts 500 damage to P2.
+func TestCounterHack(t *testing.T) {
+	ember := vanillaAgent("Ember", 4, 1000, 1000, AttrFIRE)
+	brute := vanillaAgent("Brute", 4, 2000, 1000, AttrEARTH)
+
+	deck0 := makePaddedDeck([]*Card{ember, CounterHack()}, 40)
+	deck1 := makePaddedDeck([]*Card{brute}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Ember, set Counter-Hack
+	p0.AddAction(ActionNormalSummon, "Ember")
+	p0.AddAction(ActionSetTech, "Counter-Hack")
+	p0.AddAction(ActionEndTurn, "")
+	// Turn 2: activate Counter-Hack in response to Brute's attack
+	p0.AddAction(ActionActivate, "Counter-Hack")
+
+	// Turn 2 (P2): Summon Brute and attack Ember
+	p1.AddAction(ActionNormalSummon, "Brute")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddAttack("Brute", "Ember")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Ember") != nil {
+		t.Fatal("Expected Ember to be destroyed by battle")
+	}
+	if want := StartingHP - 500; duel.State.Players[1].HP != want {
+		t.Errorf("Expected Counter-Hack to leave P2 at %d, got %d", want, duel.State.Players[1].HP)
+	}
+	if want := StartingHP - 1000; duel.State.Players[0].HP != want {
+		t.Errorf("Expected P1 at %d after battle damage, got %d", want, duel.State.Players[0].HP)
+	}
+}
+
+// TestScoutRelay: Normal Summoning Scout Relay fetches Pixie from the Deck in
+// DEF Position, then shuffles the Deck.
+func TestScoutRelay(t *testing.T) {
+	pixie := vanillaAgent("Pixie", 1, 300, 400, AttrLIGHT)
+	// Pixie sits below the opening hand and the Turn 1 draw
+	top := []*Card{ScoutRelay()}
+	for len(top) < 6 {
+		top = append(top, vanillaAgent("Brick", 3, 1000, 1000, AttrEARTH))
+	}
+	deck0 := makePaddedDeck(append(top, pixie), 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	p0.AddAction(ActionNormalSummon, "Scout Relay")
+	p0.AddYesNo(true)
+	p0.AddCardChoice("Pixie")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: makePaddedDeck(nil, 40), MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	pix := findAgent(duel, 0, "Pixie")
+	if pix == nil || pix.Position != PositionDEF || pix.Face != FaceUp {
+		t.Fatalf("Expected Pixie Special Summoned in face-up DEF Position, got %v", pix)
+	}
+	if n := len(logger.EventsOfType(log.EventShuffle)); n != 1 {
+		t.Errorf("Expected the deck to be shuffled once, got %d", n)
+	}
+	if n := duel.State.Players[0].DeckCount(); n != 40-6-1 {
+		t.Errorf("Expected %d cards left in the deck, got %d", 40-6-1, n)
+	}
+}
+
+// TestJunkyardLurker: Junkyard Lurker alone covers both sacrifices for a
+// Level 7 WATER agent, but counts as only one for other attributes.
+func TestJunkyardLurker(t *testing.T) {
+	colossus := vanillaAgent("Tidal Colossus", 7, 2600, 2000, AttrWATER)
+	titan := vanillaAgent("Magma Titan", 7, 2600, 2000, AttrFIRE)
+
+	if v := JunkyardLurker().TributeValue(titan); v != 1 {
+		t.Errorf("Expected Junkyard Lurker to count as 1 sacrifice for a FIRE agent, got %d", v)
+	}
+
+	deck0 := makePaddedDeck([]*Card{JunkyardLurker(), colossus}, 40)
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1: Summon Junkyard Lurker; Turn 3: sacrifice it for Tidal Colossus
+	p0.AddAction(ActionNormalSummon, "Junkyard Lurker")
+	p0.AddAction(ActionEndTurn, "")
+	p0.AddAction(ActionSacrificeSummon, "Tidal Colossus")
+	p0.AddCardChoice("Junkyard Lurker")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: makePaddedDeck(nil, 40), MaxTurns: 3}
+	duel, _ := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Tidal Colossus") == nil {
+		t.Fatal("Expected Tidal Colossus Sacrifice Summoned")
+	}
+	if n := duel.State.Players[0].AgentCount(); n != 1 {
+		t.Errorf("Expected only Tidal Colossus on the field, got %d agents", n)
+	}
+}
+
+// TestCrossedWires: P1 swaps Scrap for P2's Brute, attacks Scrap with Brute,
+// and Brute returns to P2 in the End Phase.
+func TestCrossedWires(t *testing.T) {
+	scrap := vanillaAgent("Scrap", 2, 500, 500, AttrEARTH)
+	brute := vanillaAgent("Brute", 4, 2000, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{scrap, CrossedWires()}, 40)
+	deck1 := makePaddedDeck([]*Card{brute}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Scrap
+	p0.AddAction(ActionNormalSummon, "Scrap")
+	p0.AddAction(ActionEndTurn, "")
+	// Turn 3: Crossed Wires (give Scrap, take Brute), then Brute attacks Scrap
+	p0.AddAction(ActionActivate, "Crossed Wires")
+	p0.AddCardChoice("Scrap")
+	p0.AddCardChoice("Brute")
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddAttack("Brute", "Scrap")
+
+	// Turn 2 (P2): Summon Brute
+	p1.AddAction(ActionNormalSummon, "Brute")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if destroys := logger.EventsOfType(log.EventBattleDestroy); len(destroys) != 1 || destroys[0].Card != "Scrap" {
+		t.Fatalf("Expected Brute to destroy Scrap by battle, got %v", destroys)
+	}
+	if want := StartingHP - 1500; duel.State.Players[1].HP != want {
+		t.Errorf("Expected P2 at %d after Brute's attack, got %d", want, duel.State.Players[1].HP)
+	}
+	if b := findAgent(duel, 1, "Brute"); b == nil || b.Controller != 1 {
+		t.Errorf("Expected Brute back under P2's control after the End Phase, got %v", b)
+	}
+	if n := len(logger.EventsOfType(log.EventChangeControl)); n != 3 {
+		t.Errorf("Expected 2 swaps and 1 return of control, got %d control changes", n)
+	}
+}
+
+// TestStealthGliderLocksTraps: P2's Cascade Failure cannot answer Stealth
+// Glider's Normal Summon, but triggers on P1's next summon.
+func TestStealthGliderLocksTraps(t *testing.T) {
+	decoy := vanillaAgent("Decoy", 4, 1200, 1000, AttrEARTH)
+
+	deck0 := makePaddedDeck([]*Card{StealthGlider(), decoy}, 40)
+	deck1 := makePaddedDeck([]*Card{CascadeFailure()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): pass; Turn 3: summon Stealth Glider; Turn 5: summon Decoy
+	p0.AddAction(ActionEndTurn, "")
+	p0.AddAction(ActionNormalSummon, "Stealth Glider")
+	p0.AddAction(ActionEndTurn, "")
+	p0.AddAction(ActionNormalSummon, "Decoy")
+
+	// Turn 2 (P2): set Cascade Failure, activate it when offered
+	p1.AddAction(ActionSetTech, "Cascade Failure")
+	p1.AddYesNo(true)
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 5}
+	_, logger := runDuel(t, cfg, p0, p1)
+
+	var destroyed []string
+	for _, e := range logger.EventsOfType(log.EventDestroy) {
+		if e.Turn != 5 {
+			t.Errorf("Expected nothing destroyed before Turn 5, got %s on Turn %d", e.Card, e.Turn)
+		}
+		destroyed = append(destroyed, e.Card)
+	}
+	if len(destroyed) != 2 {
+		t.Errorf("Expected Cascade Failure to destroy Stealth Glider and Decoy on Turn 5, got %v", destroyed)
+	}
+}
+
+// TestStealthGliderLockEndsWithWindow: the trap lock covers only the summon's
+// own response window, so P2's Cascade Failure can answer a Special Summon
+// made as the summon's chain resolves.
+func TestStealthGliderLockEndsWithWindow(t *testing.T) {
+	relay := ScoutRelay()
+	relay.Name = "Stealth Relay"
+	relay.Effects = append(relay.Effects, &CardEffect{Name: "Stealth Relay", SummonLockTraps: true})
+	scout := vanillaAgent("Level 1 Scout", 1, 300, 300, AttrWIND)
+	filler := vanillaAgent("Filler Z", 4, 1000, 1000, AttrLIGHT)
+
+	// The scout sits under P1's opening hand and Turn 1 and 3 draws
+	deck0 := makePaddedDeck([]*Card{relay, filler, filler, filler, filler, filler, filler, scout}, 40)
+	deck1 := makePaddedDeck([]*Card{CascadeFailure()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): pass; Turn 3: summon Stealth Relay, Special Summon the scout
+	p0.AddAction(ActionEndTurn, "")
+	p0.AddAction(ActionNormalSummon, "Stealth Relay")
+	p0.AddYesNo(true)
+	p0.AddCardChoice("Level 1 Scout")
+
+	// Turn 2 (P2): set Cascade Failure, activate it when offered
+	p1.AddAction(ActionSetTech, "Cascade Failure")
+	p1.AddYesNo(true)
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	_, logger := runDuel(t, cfg, p0, p1)
+
+	if n := len(logger.EventsOfType(log.EventDestroy)); n != 2 {
+		t.Errorf("Expected Cascade Failure to destroy Stealth Relay and the scout, got %d destroyed", n)
+	}
+}
+
+// TestOpenExchange: both players draw 2; when both would deck out, the turn
+// player draws first and is the one who loses.
+func TestOpenExchange(t *testing.T) {
+	play := func(size0, size1 int) *Duel {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddAction(ActionActivate, "Open Exchange")
+		cfg := DuelConfig{
+			Deck0:    makePaddedDeck([]*Card{OpenExchange()}, size0),
+			Deck1:    makePaddedDeck(nil, size1),
+			MaxTurns: 1,
+		}
+		duel, _ := runDuel(t, cfg, p0, p1)
+		return duel
+	}
+
+	// Decks count the draws, since P1 discards back to 6 in the End Phase
+	duel := play(40, 40)
+	if n0, n1 := duel.State.Players[0].DeckCount(), duel.State.Players[1].DeckCount(); n0 != 40-6-2 || n1 != 40-5-2 {
+		t.Errorf("Expected both players to draw 2, got decks of P1 %d and P2 %d", n0, n1)
+	}
+
+	duel = play(40, 6)
+	if !duel.State.Over || duel.State.Winner != 0 || duel.State.WinReason != WinReasonDeckout {
+		t.Errorf("Expected P1 to win when P2 decks out, got %q", duel.State.Result)
+	}
+
+	duel = play(7, 6)
+	if !duel.State.Over || duel.State.Winner != 1 || duel.State.WinReason != WinReasonDeckout {
+		t.Errorf("Expected P1 to deck out first, got %q", duel.State.Result)
+	}
+}
+
+// TestSingleStrikeDoctrine: with P1's Single Strike Doctrine face-up, P2's
+// Alpha attacks directly and Beta can no longer declare an attack.
+func TestSingleStrikeDoctrine(t *testing.T) {
+	alpha := vanillaAgent("Alpha", 4, 1500, 1000, AttrFIRE)
+	beta := vanillaAgent("Beta", 4, 1400, 1000, AttrWIND)
+
+	deck0 := makePaddedDeck([]*Card{SingleStrikeDoctrine()}, 40)
+	deck1 := makePaddedDeck([]*Card{alpha, beta}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): set Single Strike Doctrine; Turn 3: activate it
+	p0.AddAction(ActionSetTech, "Single Strike Doctrine")
+	p0.AddAction(ActionEndTurn, "")
+	p0.AddAction(ActionActivate, "Single Strike Doctrine")
+
+	// Turn 2 (P2): summon Alpha; Turn 4: summon Beta, attack with both
+	p1.AddAction(ActionNormalSummon, "Alpha")
+	p1.AddAction(ActionEndTurn, "")
+	p1.AddAction(ActionNormalSummon, "Beta")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddDirectAttack("Alpha")
+	p1.AddDirectAttack("Beta")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if n := len(logger.EventsOfType(log.EventDirectAttackDeclare)); n != 1 {
+		t.Errorf("Expected only 1 attack declared, got %d", n)
+	}
+	if want := StartingHP - 1500; duel.State.Players[0].HP != want {
+		t.Errorf("Expected only Alpha's attack to hit P1 (HP %d), got %d", want, duel.State.Players[0].HP)
+	}
+}
+
+// TestFullDisclosureDraw: with 2 agents and 1 Program left in hand, P1 reveals
+// all three to P2 and draws 2.
+func TestFullDisclosureDraw(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{
+		FullDisclosureDraw(),
+		vanillaAgent("Agent A", 4, 1000, 1000, AttrLIGHT),
+		vanillaAgent("Agent B", 4, 1000, 1000, AttrLIGHT),
+		normalProgram("Spare X"),
+		normalProgram("Spare Y"),
+		normalProgram("Spare Z"),
+	}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): set 2 Programs, leaving Agent A, Agent B and Spare X
+	p0.AddAction(ActionSetTech, "Spare Y")
+	p0.AddAction(ActionSetTech, "Spare Z")
+	p0.AddAction(ActionActivate, "Full Disclosure Draw")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	reveals := logger.EventsOfType(log.EventRevealHand)
+	if len(reveals) != 1 || reveals[0].Player != 0 {
+		t.Fatalf("Expected P1's hand to be revealed once, got %v", reveals)
+	}
+	if got := reveals[0].Details; !strings.Contains(got, "Agent A") || !strings.Contains(got, "Agent B") || !strings.Contains(got, "Spare X") {
+		t.Errorf("Expected the reveal to show Agent A, Agent B and Spare X, got %q", got)
+	}
+	if n := duel.State.Players[0].DeckCount(); n != 40-6-2 {
+		t.Errorf("Expected P1 to draw 2 cards, got deck of %d", n)
+	}
+	if duel.State.RevealedHands[0] {
+		t.Error("Expected P1's hand to be hidden again after resolution")
+	}
+}
+
+// setLockRecorder wraps a ScriptedController and records the Main Phase
+// action types offered while agents can't be Set, and on later turns.
+type setLockRecorder struct {
+	*ScriptedController
+	locked, later map[ActionType]bool
+}
+
+func (sr *setLockRecorder) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	var seen map[ActionType]bool
+	switch {
+	case state.Phase != PhaseMain1:
+	case state.NoAgentSet:
+		seen = sr.locked
+	case state.Turn > 1:
+		seen = sr.later
+	}
+	for _, a := range actions {
+		if seen != nil {
+			seen[a.Type] = true
+		}
+	}
+	return sr.ScriptedController.ChooseAction(ctx, state, actions)
+}
+
+// TestExposureField: after P1 activates it, Set is no longer offered but
+// Normal Summon is; P2 can Set again next turn.
+func TestExposureField(t *testing.T) {
+	alpha := vanillaAgent("Alpha", 4, 1500, 1000, AttrLIGHT)
+	deck0 := makePaddedDeck([]*Card{ExposureField(), alpha}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := &setLockRecorder{ScriptedController: NewScriptedController(t, "P1"), locked: map[ActionType]bool{}, later: map[ActionType]bool{}}
+	p1 := &setLockRecorder{ScriptedController: NewScriptedController(t, "P2"), locked: map[ActionType]bool{}, later: map[ActionType]bool{}}
+
+	// Turn 1 (P1): Exposure Field, then Normal Summon Alpha
+	p0.AddAction(ActionActivate, "Exposure Field")
+	p0.AddAction(ActionNormalSummon, "Alpha")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if !p0.locked[ActionNormalSummon] {
+		t.Error("Expected Normal Summon to stay available under Exposure Field")
+	}
+	if p0.locked[ActionNormalSet] || p0.locked[ActionSacrificeSet] {
+		t.Error("Expected no Set actions under Exposure Field")
+	}
+	if findAgent(duel, 0, "Alpha") == nil || len(logger.EventsOfType(log.EventNormalSummon)) != 1 {
+		t.Error("Expected Alpha to be Normal Summoned")
+	}
+	if !p1.later[ActionNormalSet] || len(p1.locked) != 0 {
+		t.Error("Expected P2 to be able to Set agents on Turn 2")
+	}
+}
+
+// TestBalancedLedger: P1's Scout Relay Special Summons Pixie under Balanced
+// Ledger and P2 draws 1; with an empty deck that draw loses P2 the duel.
+func TestBalancedLedger(t *testing.T) {
+	play := func(deck1Size int) (*Duel, *log.MemoryLogger) {
+		pixie := vanillaAgent("Pixie", 1, 300, 400, AttrLIGHT)
+		top := []*Card{BalancedLedger(), ScoutRelay()}
+		for len(top) < 6 {
+			top = append(top, vanillaAgent("Brick", 3, 1000, 1000, AttrEARTH))
+		}
+		deck0 := makePaddedDeck(append(top, pixie), 40)
+
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddAction(ActionActivate, "Balanced Ledger")
+		p0.AddAction(ActionNormalSummon, "Scout Relay")
+		p0.AddYesNo(true)
+		p0.AddCardChoice("Pixie")
+
+		cfg := DuelConfig{Deck0: deck0, Deck1: makePaddedDeck(nil, deck1Size), MaxTurns: 1}
+		return runDuel(t, cfg, p0, p1)
+	}
+
+	duel, logger := play(40)
+	if findAgent(duel, 0, "Pixie") == nil {
+		t.Fatal("Expected Pixie to be Special Summoned")
+	}
+	var p2Draws int
+	for _, e := range logger.EventsOfType(log.EventDraw) {
+		if e.Player == 1 {
+			p2Draws++
+		}
+	}
+	if p2Draws != 1 || len(duel.State.Players[1].Hand) != 6 {
+		t.Errorf("Expected P2 to draw 1 card, got %d draws and a hand of %d", p2Draws, len(duel.State.Players[1].Hand))
+	}
+	if n := len(duel.State.Players[0].Hand); n != 6-2 {
+		t.Errorf("Expected P1 not to draw, got a hand of %d", n)
+	}
+
+	duel, _ = play(5)
+	if gs := duel.State; !gs.Over || gs.Winner != 0 || gs.WinReason != WinReasonDeckout {
+		t.Errorf("Expected P2 to deck out on the forced draw, got winner %d reason %q", gs.Winner, gs.WinReason)
+	}
+}
+
+// TestLifeSiphonArray: P1 tributes a 2000 ATK agent and gains 2000 HP.
+func TestLifeSiphonArray(t *testing.T) {
+	titan := vanillaAgent("Titan", 4, 2000, 1000, AttrEARTH)
+	deck0 := makePaddedDeck([]*Card{titan, LifeSiphonArray()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	p0.AddAction(ActionNormalSummon, "Titan")
+	p0.AddAction(ActionActivate, "Life Siphon Array")
+	p0.AddCardChoice("Titan")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if findAgent(duel, 0, "Titan") != nil {
+		t.Error("Expected Titan to be tributed")
+	}
+	if len(logger.EventsOfType(log.EventCostPaid)) != 1 {
+		t.Error("Expected the tribute to be paid as a cost")
+	}
+	if hp := duel.State.Players[0].HP; hp != StartingHP+2000 {
+		t.Errorf("Expected P1 to gain 2000 HP, got %d", hp)
+	}
+}
+
+// TestMimicShell: after battling Abyssal Circuit Leviathan, Mimic Shell is
+// treated as Leviathan for the rest of the turn, so P1 can Tribute it to
+// Special Summon Chromeborne Hydra Nexus.
+func TestMimicShell(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{ChromeborneHydraNexus()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	// Turn 3 (P1): Mimic Shell attacks Leviathan and bounces off its DEF
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddAttack("Mimic Shell", "Abyssal Circuit Leviathan")
+	// Main Phase 2: Mimic Shell now counts as Leviathan
+	p0.AddAction(ActionActivate, "Chromeborne Hydra Nexus")
+	p0.AddCardChoice("Mimic Shell")
+
+	duel := NewDuel(DuelConfig{Deck0: deck0, Deck1: deck1, NoShuffle: true, MaxTurns: 3}, p0, p1)
+	gs := duel.State
+	mimic := gs.CreateCardInstance(MimicShell(), 0)
+	mimic.Face, mimic.Position = FaceUp, PositionATK
+	gs.Players[0].PlaceAgent(mimic, 0)
+	leviathan := gs.CreateCardInstance(AbyssalCircuitLeviathan(), 1)
+	leviathan.Face, leviathan.Position = FaceUp, PositionDEF
+	gs.Players[1].PlaceAgent(leviathan, 0)
+
+	if duel.hasName(mimic, "Abyssal Circuit Leviathan") {
+		t.Fatal("Expected Mimic Shell to keep its own name before battling")
+	}
+	if _, err := duel.Run(context.Background()); err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+
+	if mimic.LastBattled != leviathan || mimic.LastBattledTurn != 3 {
+		t.Errorf("Expected Mimic Shell to record battling Leviathan on Turn 3, got %v on Turn %d", mimic.LastBattled, mimic.LastBattledTurn)
+	}
+	if findAgent(duel, 0, "Chromeborne Hydra Nexus") == nil {
+		t.Fatal("Expected Chromeborne Hydra Nexus to be Special Summoned")
+	}
+	if mimic.Zone != ZoneScrapheap {
+		t.Errorf("Expected Mimic Shell to be Tributed, got zone %v", mimic.Zone)
+	}
+}
+
+// TestTreatedAsName: name checks see a card's second name and a NameAs
+// rename alike: The Undercity Grid counts as "NetGrid" for Abyssal Circuit
+// Leviathan, and an agent renamed Leviathan satisfies Chromeborne Hydra Nexus.
+func TestTreatedAsName(t *testing.T) {
+	duel := NewDuel(DuelConfig{}, NewScriptedController(t, "P1"), NewScriptedController(t, "P2"))
+	gs := duel.State
+	p := gs.Players[0]
+
+	leviathan := gs.CreateCardInstance(AbyssalCircuitLeviathan(), 0)
+	leviathan.Face, leviathan.Position = FaceUp, PositionATK
+	p.PlaceAgent(leviathan, 0)
+	nuke := leviathan.Card.Effects[0]
+
+	ledger := gs.CreateCardInstance(BalancedLedger(), 0)
+	ledger.Face, ledger.Zone = FaceUp, ZoneOS
+	p.OS = ledger
+	if nuke.CanActivate(duel, leviathan, 0) {
+		t.Fatal("Expected Leviathan's effect to need NetGrid")
+	}
+	grid := gs.CreateCardInstance(TheUndercityGrid(), 0)
+	grid.Face, grid.Zone = FaceUp, ZoneOS
+	p.OS = grid
+	if !duel.hasName(grid, "The Undercity Grid") || !nuke.CanActivate(duel, leviathan, 0) {
+		t.Error("Expected The Undercity Grid to keep its name and enable Leviathan's effect")
+	}
+
+	p.RemoveAgent(leviathan)
+	standIn := vanillaAgent("Stand-in", 4, 1000, 1000, AttrWATER)
+	renamed := false
+	standIn.Effects = []*CardEffect{{
+		Name: "Stand-in",
+		NameAs: func(d *Duel, card *CardInstance) (*Card, bool) {
+			return leviathan.Card, renamed
+		},
+	}}
+	stand := gs.CreateCardInstance(standIn, 0)
+	stand.Face, stand.Position = FaceUp, PositionATK
+	p.PlaceAgent(stand, 0)
+	hydra := gs.CreateCardInstance(ChromeborneHydraNexus(), 0)
+	summon := hydra.Card.Effects[0].SpecialSummonCondition
+	if summon(duel, hydra, 0) {
+		t.Fatal("Expected Hydra Nexus to need Abyssal Circuit Leviathan")
+	}
+	renamed = true
+	if !summon(duel, hydra, 0) {
+		t.Error("Expected an agent renamed Abyssal Circuit Leviathan to satisfy Hydra Nexus")
+	}
+}
+
+// TestTotalSurveillance: once P1 activates it, P2's hand stays revealed
+// through the following turns, and is hidden again when it is destroyed.
+func TestTotalSurveillance(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{TotalSurveillance()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): set it; Turn 3: activate it
+	p0.AddAction(ActionSetTech, "Total Surveillance")
+	p0.AddAction(ActionEndTurn, "")
+	p0.AddAction(ActionActivate, "Total Surveillance")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 5}
+	duel, logger := runDuel(t, cfg, p0, p1)
+	gs := duel.State
+
+	var trap *CardInstance
+	for _, st := range gs.Players[0].TechCards() {
+		if st.Card.Name == "Total Surveillance" {
+			trap = st
+		}
+	}
+	if trap == nil || trap.Face != FaceUp {
+		t.Fatal("Expected Total Surveillance face-up on P1's field")
+	}
+	if reveals := logger.EventsOfType(log.EventRevealHand); len(reveals) != 1 || reveals[0].Turn != 3 {
+		t.Errorf("Expected P2's hand revealed once on Turn 3, got %v", reveals)
+	}
+	if !gs.HandRevealed(1) {
+		t.Error("Expected P2's hand still revealed on Turn 5")
+	}
+	if gs.HandRevealed(0) {
+		t.Error("Expected P1's own hand not revealed")
+	}
+
+	duel.destroyByEffect(trap, trap, "test")
+	if gs.HandRevealed(1) {
+		t.Error("Expected P2's hand hidden once Total Surveillance is destroyed")
+	}
+}
+
+// TestTimeDilationCore: P1 takes Turns 1 and 2, then P2 takes Turn 3.
+func TestTimeDilationCore(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{TimeDilationCore(), TimeDilationCore()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	p0.AddAction(ActionActivate, "Time Dilation Core")
+	p0.AddAction(ActionEndTurn, "")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	var players []int
+	for _, e := range logger.EventsOfType(log.EventNewTurn) {
+		players = append(players, e.Player)
+	}
+	if want := []int{0, 0, 1}; !reflect.DeepEqual(players, want) {
+		t.Errorf("Expected turn players %v, got %v", want, players)
+	}
+	if duel.State.ExtraTurnFor != -1 {
+		t.Errorf("Expected no extra turn pending, got %d", duel.State.ExtraTurnFor)
+	}
+
+	// A second copy can't stack another extra turn while one is pending
+	gs := duel.State
+	gs.ExtraTurnFor = 0
+	var second *CardInstance
+	for _, c := range gs.Players[0].Hand {
+		if c.Card.Name == "Time Dilation Core" {
+			second = c
+		}
+	}
+	if second == nil {
+		t.Fatal("Expected the second copy still in hand")
+	}
+	if second.Card.Effects[0].CanActivate(duel, second, 0) {
+		t.Error("Expected Time Dilation Core unusable while an extra turn is pending")
+	}
+}
+
+// TestSpoilsProtocol: P1 draws at the end of the Battle Phase only if an
+// opposing agent was destroyed by battle during it.
+func TestSpoilsProtocol(t *testing.T) {
+	brute := vanillaAgent("Brute", 4, 2000, 1000, AttrEARTH)
+	weakling := vanillaAgent("Weakling", 4, 1000, 1000, AttrEARTH)
+
+	// draws returns P1's Turn 3 draws once Brute has attacked (Weakling, if summoned)
+	draws := func(summonWeakling bool) []log.GameEvent {
+		deck0 := makePaddedDeck([]*Card{SpoilsProtocol(), brute}, 40)
+		deck1 := makePaddedDeck([]*Card{weakling}, 40)
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+
+		// Turn 1 (P1): activate Spoils Protocol, summon Brute
+		p0.AddAction(ActionActivate, "Spoils Protocol")
+		p0.AddAction(ActionNormalSummon, "Brute")
+		p0.AddAction(ActionEndTurn, "")
+		// Turn 2 (P2)
+		if summonWeakling {
+			p1.AddAction(ActionNormalSummon, "Weakling")
+		}
+		p1.AddAction(ActionEndTurn, "")
+		// Turn 3 (P1): attack
+		p0.AddAction(ActionEnterBattlePhase, "")
+		if summonWeakling {
+			p0.AddAttack("Brute", "Weakling")
+		} else {
+			p0.AddDirectAttack("Brute")
+		}
+		p0.AddAction(ActionEndBattlePhase, "")
+
+		cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+		_, logger := runDuel(t, cfg, p0, p1)
+
+		var drawn []log.GameEvent
+		for _, e := range logger.EventsOfType(log.EventDraw) {
+			if e.Turn == 3 && e.Player == 0 {
+				drawn = append(drawn, e)
+			}
+		}
+		return drawn
+	}
+
+	if got := draws(true); len(got) != 2 || got[1].Phase != PhaseBattle.String() {
+		t.Errorf("Expected a Draw Phase draw and a Battle Phase draw, got %v", got)
+	}
+	if got := draws(false); len(got) != 1 {
+		t.Errorf("Expected only the Draw Phase draw without a battle destruction, got %v", got)
+	}
+}
+
+// TestDecayProtocol: P2's two Set traps still Set at P2's End Phase are
+// destroyed; the one P2 activated in the meantime loses its mark and stays.
+func TestDecayProtocol(t *testing.T) {
+	beacon := &Card{
+		Name:     "Beacon",
+		CardType: CardTypeTrap,
+		TrapSub:  TrapContinuous,
+		Effects: []*CardEffect{{
+			Name:       "Beacon",
+			ExecSpeed:  ExecSpeed2,
+			EffectType: EffectContinuous,
+			Resolve:    func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error { return nil },
+		}},
+	}
+	deck0 := makePaddedDeck([]*Card{DecayProtocol()}, 40)
+	deck1 := makePaddedDeck([]*Card{normalTrap("Trap A"), normalTrap("Trap B"), beacon}, 40)
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): set Decay Protocol
+	p0.AddAction(ActionSetTech, "Decay Protocol")
+	p0.AddAction(ActionEndTurn, "")
+	// Turn 2 (P2): set three traps
+	p1.AddAction(ActionSetTech, "Trap A")
+	p1.AddAction(ActionSetTech, "Trap B")
+	p1.AddAction(ActionSetTech, "Beacon")
+	p1.AddAction(ActionEndTurn, "")
+	// Turn 3 (P1): mark them
+	p0.AddAction(ActionActivate, "Decay Protocol")
+	p0.AddAction(ActionEndTurn, "")
+	// Turn 4 (P2): activate Beacon only
+	p1.AddAction(ActionActivate, "Beacon")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	var destroyed []string
+	for _, e := range logger.EventsOfType(log.EventDestroy) {
+		if e.Turn == 4 && e.Phase == PhaseEnd.String() {
+			destroyed = append(destroyed, e.Card)
+		}
+	}
+	if !reflect.DeepEqual(destroyed, []string{"Trap A", "Trap B"}) {
+		t.Errorf("Expected Trap A and Trap B destroyed at P2's End Phase, got %v", destroyed)
+	}
+	tech := duel.State.Players[1].TechCards()
+	if len(tech) != 1 || tech[0].Card.Name != "Beacon" || tech[0].Face != FaceUp {
+		t.Fatalf("Expected only Beacon left, face-up, got %v", tech)
+	}
+	if _, marked := tech[0].Counters["decay_source"]; marked {
+		t.Error("Expected the activated Beacon to lose its mark")
+	}
+}
@@ -13,6 +13,8 @@ type ChainLink struct {
 	Effect     *CardEffect
 	Controller int
 	Targets    []*CardInstance
+	Negated    bool // the link resolves as nothing (negateChainLink)
+	Resolved   bool // set once the link starts resolving
 }
 
 // Chain represents an active chain of effects waiting to resolve.
@@ -49,12 +51,55 @@ func (d *Duel) addToChain(card *CardInstance, effect *CardEffect, player int, ta
 		Targets:    targets,
 	}
 	gs.Chain.Links = append(gs.Chain.Links, link)
+	markOncePerTurn(card, effect)
 
 	d.log(log.NewChainLinkEvent(gs.Turn, gs.Phase.String(), player, card.Card.Name, index))
 
 	return nil
 }
 
+// chainIndexOf returns the index of the card's link in the current chain, or -1.
+func (d *Duel) chainIndexOf(card *CardInstance) int {
+	gs := d.State
+	if gs.Chain == nil {
+		return -1
+	}
+	for i, link := range gs.Chain.Links {
+		if link.Card.ID == card.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// negateChainLink negates the chain link at index i. The link stays on the chain
+// but its Resolve is skipped, and a negated program or trap is sent to the
+// scrapheap instead of staying on the field.
+func (d *Duel) negateChainLink(i int) {
+	d.State.Chain.Links[i].Negated = true
+}
+
+// activationPending reports whether a tech card's activation is still waiting
+// on the chain or was negated there. Its continuous and leave-field effects
+// don't apply until the activation resolves.
+func (d *Duel) activationPending(card *CardInstance) bool {
+	return d.State.activationPending(card)
+}
+
+// activationPending reports whether card is on the chain and its activation
+// has not (successfully) resolved yet.
+func (gs *GameState) activationPending(card *CardInstance) bool {
+	if gs.Chain == nil {
+		return false
+	}
+	for _, link := range gs.Chain.Links {
+		if link.Card.ID == card.ID && (link.Negated || !link.Resolved) {
+			return true
+		}
+	}
+	return false
+}
+
 // resolveChain resolves the chain in LIFO order (last link resolves first).
 func (d *Duel) resolveChain() error {
 	gs := d.State
@@ -67,10 +112,14 @@ func (d *Duel) resolveChain() error {
 		if gs.Over {
 			break
 		}
+		gs.Chain.Links[i].Resolved = true
 		link := gs.Chain.Links[i]
+		if d.Trace {
+			d.traceLink(link)
+		}
 		d.log(log.NewChainResolveEvent(gs.Turn, gs.Phase.String(), link.Controller, link.Card.Card.Name, link.Index))
 
-		if link.Effect.Resolve != nil {
+		if !link.Negated && link.Effect.Resolve != nil {
 			if err := link.Effect.Resolve(d, link.Card, link.Controller, link.Targets); err != nil {
 				return err
 			}
@@ -89,12 +138,33 @@ func (d *Duel) resolveChain() error {
 	return nil
 }
 
+// traceLink logs a chain link's controller, negation and targets as it resolves.
+func (d *Duel) traceLink(link ChainLink) {
+	var targets []string
+	for _, t := range link.Targets {
+		targets = append(targets, t.Card.Name)
+	}
+	d.log(log.NewTraceEvent(d.State.Turn, d.State.Phase.String(), link.Controller, link.Card.Card.Name, link.Index, link.Negated, targets))
+}
+
 // handlePostResolution handles cleanup after a chain link resolves.
 // Normal programs and non-continuous traps go to the scrapheap.
 func (d *Duel) handlePostResolution(link ChainLink) {
 	card := link.Card
 	gs := d.State
 
+	// A negated activation doesn't leave its card on the field
+	if link.Negated && (card.Zone == ZoneTech || card.Zone == ZoneOS) {
+		if card.Zone == ZoneOS {
+			gs.Players[card.Controller].OS = nil
+		} else {
+			gs.Players[card.Controller].RemoveFromTech(card)
+		}
+		gs.Players[card.Owner].SendToScrapheap(card)
+		d.log(log.NewSendToScrapheapEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, "negated"))
+		return
+	}
+
 	// Only move to scrapheap if card is still on the field (wasn't already destroyed during resolution)
 	if card.Zone != ZoneTech {
 		return // already moved (destroyed, etc.)
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -649,6 +650,86 @@ func TestFlipEffect(t *testing.T) {
 	}
 }
 
+// TestFlipJammer: the opponent negates Datamancer's flip effect with Flip
+// Jammer, so the Program stays in the Scrapheap.
+func TestFlipJammer(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{Datamancer(), GreedProtocol()}, 40)
+	deck1 := makePaddedDeck([]*Card{FlipJammer()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// T1: Set Datamancer, send Greed Protocol to the Scrapheap
+	p0.AddAction(ActionNormalSet, "Datamancer")
+	p0.AddAction(ActionActivate, "Greed Protocol")
+	// T2: P2 sets Flip Jammer
+	p1.AddAction(ActionSetTech, "Flip Jammer")
+	// T3: Flip Summon Datamancer, targeting Greed Protocol → P2 chains Flip Jammer
+	p0.AddAction(ActionFlipSummon, "Datamancer")
+	p0.AddYesNo(true)
+	p0.AddCardChoice("Greed Protocol")
+	p1.AddAction(ActionActivate, "Flip Jammer")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	logger := runDuelToCompletion(t, cfg, p0, p1)
+
+	activated := false
+	for _, e := range logger.EventsOfType(log.EventActivate) {
+		if e.Card == "Flip Jammer" && e.Player == 1 {
+			activated = true
+		}
+	}
+	if !activated {
+		t.Fatal("Expected P2 to activate Flip Jammer")
+	}
+	for _, e := range logger.EventsOfType(log.EventAddToHand) {
+		if e.Card == "Greed Protocol" {
+			t.Error("Expected Datamancer's negated effect not to recover Greed Protocol")
+		}
+	}
+}
+
+// TestInterferenceBurst: P2 purges 2 scrapheap cards to negate Greed Protocol.
+func TestInterferenceBurst(t *testing.T) {
+	scrap := func(name string) *Card {
+		return normalProgram(name, &CardEffect{
+			Name:    name,
+			Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error { return nil },
+		})
+	}
+	deck0 := makePaddedDeck([]*Card{GreedProtocol()}, 40)
+	deck1 := makePaddedDeck([]*Card{scrap("Scrap A"), scrap("Scrap B"), InterferenceBurst()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	p0.AddAction(ActionEndTurn, "")
+	// T2: P2 fills its scrapheap and sets Interference Burst
+	p1.AddAction(ActionActivate, "Scrap A")
+	p1.AddAction(ActionActivate, "Scrap B")
+	p1.AddAction(ActionSetTech, "Interference Burst")
+	p1.AddAction(ActionEndTurn, "")
+	// T3: P1 activates Greed Protocol → P2 chains Interference Burst
+	p0.AddAction(ActionActivate, "Greed Protocol")
+	p1.AddAction(ActionActivate, "Interference Burst")
+	p1.AddCardChoice("Scrap A", "Scrap B")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if n := len(duel.State.Players[1].Purged); n != 2 {
+		t.Errorf("Expected P2 to purge 2 cards as the cost, got %d", n)
+	}
+	for _, e := range logger.EventsOfType(log.EventDraw) {
+		if e.Turn == 3 && e.Phase != PhaseDraw.String() {
+			t.Errorf("Expected Greed Protocol negated, but P%d drew %s", e.Player+1, e.Card)
+		}
+	}
+	if len(duel.State.Players[0].Hand) != InitialHandSize+1 {
+		t.Errorf("Expected P1 to hold %d cards, got %d", InitialHandSize+1, len(duel.State.Players[0].Hand))
+	}
+}
+
 // TestIgnitionEffect: Breaker removes program counter to destroy a set tech.
 func TestIgnitionEffect(t *testing.T) {
 	breaker := BreakerTheChromeWarrior()
@@ -818,7 +899,7 @@ func TestBattleReplay(t *testing.T) {
 		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
 			gs := d.State
 			if gs.CurrentTarget != nil && d.isOnField(gs.CurrentTarget) {
-				d.destroyByEffect(gs.CurrentTarget, "Defender Destruction")
+				d.destroyByEffect(gs.CurrentTarget, card, "Defender Destruction")
 			}
 			return nil
 		},
@@ -1058,3 +1139,357 @@ func TestMobiusTorrentialEffectSerialization(t *testing.T) {
 		t.Error("Expected Reactive Plating to be destroyed by Mobius effect (CL1 still resolves after CL2)")
 	}
 }
+
+// TestChainTrace: with Trace on, the Mobius/Cascade Failure chain logs CL2
+// resolving before CL1, neither negated, with Mobius's target.
+func TestChainTrace(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{vanillaAgent("Fodder", 4, 1000, 1000, AttrWATER), FrostbiteTyrant()}, 40)
+	deck1 := makePaddedDeck([]*Card{CascadeFailure(), ReactivePlating()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+	p0.AddAction(ActionNormalSummon, "Fodder")
+	p1.AddAction(ActionSetTech, "Cascade Failure")
+	p1.AddAction(ActionSetTech, "Reactive Plating")
+	p0.AddAction(ActionSacrificeSummon, "Frostbite Tyrant")
+	p0.AddCardChoice("Fodder")
+	p0.AddYesNo(true)
+	p1.AddYesNo(true)
+	p0.AddCardChoice("Reactive Plating")
+
+	logger := log.NewMemoryLogger()
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4, Logger: logger, NoShuffle: true, DeterministicRandom: true}
+	duel := NewDuel(cfg, p0, p1)
+	duel.Trace = true
+	if _, err := duel.Run(context.Background()); err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+
+	traces := logger.EventsOfType(log.EventTrace)
+	if len(traces) != 2 {
+		t.Fatalf("Expected 2 traced links, got %v", traces)
+	}
+	want := []struct {
+		card   string
+		player int
+		detail string
+	}{
+		{"Cascade Failure", 1, "CL2 Cascade Failure (P2) negated=false targets=[]"},
+		{"Frostbite Tyrant", 0, "CL1 Frostbite Tyrant (P1) negated=false targets=[Reactive Plating]"},
+	}
+	for i, w := range want {
+		if traces[i].Card != w.card || traces[i].Player != w.player || !strings.Contains(traces[i].Details, w.detail) {
+			t.Errorf("Trace %d: expected %q, got %q", i+1, w.detail, traces[i].Details)
+		}
+	}
+}
+
+// TestCostPaidEvent: Memory Corruption's HP cost is reported as a cost before the effect resolves.
+func TestCostPaidEvent(t *testing.T) {
+	deck0 := makePaddedDeck([]*Card{MemoryCorruption()}, 40)
+	deck1 := makePaddedDeck([]*Card{}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Activate Memory Corruption
+	p0.AddAction(ActionActivate, "Memory Corruption")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 1}
+	logger := runDuelToCompletion(t, cfg, p0, p1)
+
+	costIdx, resolveIdx := -1, -1
+	for i, e := range logger.Events() {
+		switch {
+		case e.Type == log.EventCostPaid && e.Card == "Memory Corruption":
+			costIdx = i
+			if e.Player != 0 || e.CostKind != log.CostHP || e.CostAmount != 1000 {
+				t.Errorf("Expected P1 to pay 1000 HP, got %d %s", e.CostAmount, e.CostKind)
+			}
+		case e.Type == log.EventChainResolve && e.Card == "Memory Corruption":
+			resolveIdx = i
+		}
+	}
+	if costIdx == -1 {
+		t.Fatal("Expected a cost-paid event for Memory Corruption")
+	}
+	if resolveIdx == -1 || costIdx > resolveIdx {
+		t.Errorf("Expected cost-paid (event %d) to precede resolution (event %d)", costIdx, resolveIdx)
+	}
+}
+
+// TestEffectSourceImmunity: destruction carries its source card, so a trap-immune
+// agent survives a trap's destruction effect but not a program's.
+func TestEffectSourceImmunity(t *testing.T) {
+	var sources []string
+	warded := vanillaAgent("Warded Agent", 4, 1500, 1000, AttrLIGHT)
+	warded.IsEffect = true
+	warded.Effects = []*CardEffect{{
+		Name:       "Trap Ward",
+		EffectType: EffectContinuous,
+		UnaffectedBy: func(d *Duel, card *CardInstance, target *CardInstance, source *CardInstance) bool {
+			if target != card {
+				return false
+			}
+			sources = append(sources, source.Card.Name)
+			return source.Card.CardType == CardTypeTrap
+		},
+	}}
+	purgeBeam := normalProgram("Purge Beam", &CardEffect{
+		Name:      "Purge Beam",
+		ExecSpeed: ExecSpeed1,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			for _, m := range d.State.Players[d.State.Opponent(player)].Agents() {
+				d.destroyByEffect(m, card, "Purge Beam")
+			}
+			return nil
+		},
+	})
+
+	deck0 := makePaddedDeck([]*Card{warded}, 40)
+	deck1 := makePaddedDeck([]*Card{ReactivePlating(), purgeBeam}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Summon Warded Agent; Turn 3: attack directly
+	p0.AddAction(ActionNormalSummon, "Warded Agent")
+	p0.AddAction(ActionEnterBattlePhase, "")
+	p0.AddDirectAttack("Warded Agent")
+
+	// Turn 2 (P2): Set Reactive Plating; Turn 3: activate it; Turn 4: Purge Beam
+	p1.AddAction(ActionSetTech, "Reactive Plating")
+	p1.AddAction(ActionActivate, "Reactive Plating")
+	p1.AddAction(ActionActivate, "Purge Beam")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 4}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if len(sources) != 2 || sources[0] != "Reactive Plating" || sources[1] != "Purge Beam" {
+		t.Fatalf("Expected destruction sources [Reactive Plating Purge Beam], got %v", sources)
+	}
+	if hp := duel.State.Players[1].HP; hp != StartingHP-1500 {
+		t.Errorf("Expected Warded Agent to survive the trap and deal 1500, P2 HP = %d", hp)
+	}
+	if findAgent(duel, 0, "Warded Agent") != nil {
+		t.Error("Expected Purge Beam to destroy Warded Agent")
+	}
+	if len(logger.EventsOfType(log.EventUnaffected)) != 1 {
+		t.Error("Expected exactly one Unaffected event (from the trap)")
+	}
+}
+
+// sparkTrap is a Normal Trap that inflicts 300 damage to the activating player's opponent.
+func sparkTrap() *Card {
+	return normalTrap("Spark Trap", &CardEffect{
+		Name:      "Spark Trap",
+		ExecSpeed: ExecSpeed2,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.applyEffectDamage(d.State.Opponent(player), 300, "Spark Trap")
+			return nil
+		},
+	})
+}
+
+// TestTotalLockoutNegatesWholeChain: Greed Protocol (CL1) and two Spark Traps
+// (CL2, CL3) are all negated by Total Lockout (CL4).
+func TestTotalLockoutNegatesWholeChain(t *testing.T) {
+	filler := vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT)
+	// Greed Protocol is P1's Turn 3 draw
+	deck0 := makePaddedDeck([]*Card{sparkTrap(), filler, filler, filler, filler, filler, GreedProtocol()}, 40)
+	deck1 := makePaddedDeck([]*Card{sparkTrap(), TotalLockout()}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Spark Trap
+	p0.AddAction(ActionSetTech, "Spark Trap")
+	// Turn 3 (P1): Greed Protocol (CL1); chain Spark Trap (CL3)
+	p0.AddAction(ActionActivate, "Greed Protocol")
+	p0.AddAction(ActionActivate, "Spark Trap")
+
+	// Turn 2 (P2): Set Spark Trap and Total Lockout
+	p1.AddAction(ActionSetTech, "Spark Trap")
+	p1.AddAction(ActionSetTech, "Total Lockout")
+	// Turn 3: chain Spark Trap (CL2), then Total Lockout (CL4)
+	p1.AddAction(ActionActivate, "Spark Trap")
+	p1.AddAction(ActionActivate, "Total Lockout")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	links := logger.EventsOfType(log.EventChainLink)
+	if len(links) != 4 || links[3].Card != "Total Lockout" {
+		t.Fatalf("Expected a 4-link chain topped by Total Lockout, got %v", links)
+	}
+	for p := 0; p < 2; p++ {
+		if hp := duel.State.Players[p].HP; hp != StartingHP {
+			t.Errorf("Expected P%d HP unchanged, got %d", p+1, hp)
+		}
+	}
+	for _, e := range logger.EventsOfType(log.EventDraw) {
+		if e.Turn == 3 && e.Phase == "Main Phase 1" {
+			t.Errorf("Expected Greed Protocol's draw to be negated, got %q", e.Details)
+		}
+	}
+	// Negated cards are not destroyed: they resolve (as nothing) and go to the scrapheap
+	for _, e := range logger.EventsOfType(log.EventDestroy) {
+		t.Errorf("Expected no destruction, got %q", e.Details)
+	}
+}
+
+// TestCascadeFailureTriggerOnEverySummon: the declarative Cascade Failure
+// trigger is collected for every summon type, and only in effect serialization.
+func TestCascadeFailureTriggerOnEverySummon(t *testing.T) {
+	gs := NewGameState()
+	gs.Turn = 3
+	d := &Duel{
+		State:       gs,
+		Controllers: [2]PlayerController{NewScriptedController(t, "P1"), NewScriptedController(t, "P2")},
+		Logger:      log.NewMemoryLogger(),
+		ctx:         context.Background(),
+	}
+	cascade := &CardInstance{Card: CascadeFailure(), ID: gs.NextID(), Owner: 1, Controller: 1, Face: FaceDown, TurnPlaced: 2}
+	gs.Players[1].PlaceTech(cascade, 0)
+
+	for _, ev := range SummonEvents {
+		if n := len(d.collectTriggers(ev)); n != 1 {
+			t.Errorf("%s: expected Cascade Failure to trigger, got %d triggers", ev, n)
+		}
+	}
+	if n := len(d.collectTriggers(log.EventActivate)); n != 0 {
+		t.Errorf("Expected no trigger on an activation, got %d", n)
+	}
+
+	// Not offered as a fast action in the response window after a summon
+	gs.ResponseEvent = log.EventNormalSummon
+	for _, a := range d.computeFastEffectActions(1) {
+		if a.Card == cascade {
+			t.Error("Expected Cascade Failure only in effect serialization, not the response window")
+		}
+	}
+}
+
+// TestDeclarativeResponseTrigger: a trap declared with a WindowResponse Trigger
+// on attack declarations is offered when P2 attacks and ends the attack.
+func TestDeclarativeResponseTrigger(t *testing.T) {
+	tripwire := normalTrap("Tripwire", &CardEffect{
+		Name:      "Tripwire",
+		ExecSpeed: ExecSpeed2,
+		Trigger: &Trigger{
+			On:     []log.EventType{log.EventAttackDeclare, log.EventDirectAttackDeclare},
+			Window: WindowResponse,
+		},
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.negateAttack()
+			return nil
+		},
+	})
+	striker := vanillaAgent("Striker", 4, 1800, 1000, AttrFIRE)
+
+	deck0 := makePaddedDeck([]*Card{tripwire}, 40)
+	deck1 := makePaddedDeck([]*Card{striker}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Set Tripwire; Turn 2: activate it when Striker attacks
+	p0.AddAction(ActionSetTech, "Tripwire")
+	p0.AddAction(ActionActivate, "Tripwire")
+
+	// Turn 2 (P2): Summon Striker, attack directly
+	p1.AddAction(ActionNormalSummon, "Striker")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddDirectAttack("Striker")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if hp := duel.State.Players[0].HP; hp != StartingHP {
+		t.Errorf("Expected P1 HP %d, got %d", StartingHP, hp)
+	}
+	if n := len(logger.EventsOfType(log.EventAttackStopped)); n != 1 {
+		t.Errorf("Expected Tripwire to stop 1 attack, got %d", n)
+	}
+}
+
+// TestNegatedChainLinkSkipsResolve: negated links skip Resolve, and a negated
+// continuous program goes to the scrapheap without ever applying its effect.
+func TestNegatedChainLinkSkipsResolve(t *testing.T) {
+	applied := 0
+	overclock := &Card{
+		Name:       "Overclock Field",
+		CardType:   CardTypeProgram,
+		ProgramSub: ProgramContinuous,
+		Effects: []*CardEffect{{
+			Name:       "Overclock Field",
+			ExecSpeed:  ExecSpeed1,
+			EffectType: EffectContinuous,
+			ContinuousApply: func(d *Duel, card *CardInstance, player int) {
+				applied++
+				for _, m := range d.State.Players[player].FaceUpAgents() {
+					m.AddModifier(StatModifier{Source: card.ID, ATKMod: 500, Continuous: true})
+				}
+			},
+		}},
+	}
+
+	setup := func() (*Duel, *CardInstance, *CardInstance, *CardInstance) {
+		gs := NewGameState()
+		gs.Turn = 1
+		d := &Duel{
+			State:       gs,
+			Controllers: [2]PlayerController{NewScriptedController(t, "P1"), NewScriptedController(t, "P2")},
+			Logger:      log.NewMemoryLogger(),
+			ctx:         context.Background(),
+		}
+		p := gs.Players[0]
+		for i := 0; i < 5; i++ {
+			p.Deck = append(p.Deck, gs.CreateCardInstance(vanillaAgent("Filler Z", 1, 0, 0, AttrLIGHT), 0))
+		}
+		striker := gs.CreateCardInstance(vanillaAgent("Striker", 4, 1800, 1000, AttrFIRE), 0)
+		striker.Face = FaceUp
+		p.PlaceAgent(striker, 0)
+		greed := gs.CreateCardInstance(GreedProtocol(), 0)
+		greed.Face = FaceUp
+		p.PlaceTech(greed, 0)
+		field := gs.CreateCardInstance(overclock, 0)
+		field.Face = FaceUp
+		p.PlaceTech(field, 1)
+		gs.Chain = &Chain{Links: []ChainLink{
+			{Index: 1, Card: greed, Effect: greed.Card.Effects[0], Controller: 0},
+			{Index: 2, Card: field, Effect: field.Card.Effects[0], Controller: 0},
+		}}
+		return d, striker, greed, field
+	}
+
+	d, striker, greed, field := setup()
+	d.recalculateContinuousEffects()
+	if applied != 0 {
+		t.Fatalf("Expected no continuous effect while the activation is on the chain, applied %d times", applied)
+	}
+	d.negateChainLink(0)
+	d.negateChainLink(1)
+	if err := d.resolveChain(); err != nil {
+		t.Fatalf("resolveChain: %v", err)
+	}
+	p := d.State.Players[0]
+	if len(p.Hand) != 0 || len(p.Deck) != 5 {
+		t.Errorf("Expected negated Greed Protocol to draw nothing, hand %d deck %d", len(p.Hand), len(p.Deck))
+	}
+	if greed.Zone != ZoneScrapheap || field.Zone != ZoneScrapheap {
+		t.Errorf("Expected both negated cards in the scrapheap, got %v and %v", greed.Zone, field.Zone)
+	}
+	if applied != 0 || striker.CurrentATK() != 1800 {
+		t.Errorf("Expected negated Overclock Field never to apply, applied %d times, Striker ATK %d", applied, striker.CurrentATK())
+	}
+
+	// Without negation, the continuous program applies once it resolves
+	d, striker, _, field = setup()
+	if err := d.resolveChain(); err != nil {
+		t.Fatalf("resolveChain: %v", err)
+	}
+	if field.Zone != ZoneTech || striker.CurrentATK() != 2300 {
+		t.Errorf("Expected resolved Overclock Field to stay and boost Striker to 2300, got zone %v ATK %d", field.Zone, striker.CurrentATK())
+	}
+}
@@ -0,0 +1,16 @@
+package game
+
+import "time"
+
+// Clock is the duel's source of time. Tests inject a fake clock to drive
+// time-based rules (decision timeouts) without sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// wallClock is the default Clock, backed by the time package.
+type wallClock struct{}
+
+func (wallClock) Now() time.Time                         { return time.Now() }
+func (wallClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
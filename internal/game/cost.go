@@ -0,0 +1,68 @@
+package game
+
+import "github.com/peterkuimelis/tcgx/internal/log"
+
+// payHP pays an HP cost for the source card's effect.
+func (d *Duel) payHP(player int, amount int, source *CardInstance) {
+	gs := d.State
+	p := gs.Players[player]
+	amount -= d.hpCostDiscount(player, source)
+	if amount < 0 {
+		amount = 0
+	}
+	oldHP := p.HP
+	p.HP -= amount
+	d.log(log.NewHPChangeEvent(gs.Turn, gs.Phase.String(), player, oldHP, p.HP, source.Card.Name+" cost"))
+	d.log(log.NewCostPaidEvent(gs.Turn, gs.Phase.String(), player, source.Card.Name, log.CostHP, amount))
+}
+
+// discardAsCost discards the given cards from hand as a cost for the source card's effect.
+func (d *Duel) discardAsCost(player int, cards []*CardInstance, source *CardInstance) {
+	gs := d.State
+	p := gs.Players[player]
+	for _, c := range cards {
+		p.RemoveFromHand(c)
+		p.SendToScrapheap(c)
+		d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name))
+	}
+	d.log(log.NewCostPaidEvent(gs.Turn, gs.Phase.String(), player, source.Card.Name, log.CostDiscard, len(cards)))
+	d.recalculateContinuousEffects()
+}
+
+// purgeAsCost purges the given cards from the scrapheap as a cost for the source card's effect.
+func (d *Duel) purgeAsCost(player int, cards []*CardInstance, source *CardInstance) {
+	gs := d.State
+	for _, c := range cards {
+		d.purgeFromScrapheap(player, c, source.Card.Name+" cost")
+	}
+	d.log(log.NewCostPaidEvent(gs.Turn, gs.Phase.String(), player, source.Card.Name, log.CostPurge, len(cards)))
+}
+
+// tributeAsCost sends the given agents from the field to the scrapheap as a cost
+// for the source card's effect.
+func (d *Duel) tributeAsCost(player int, cards []*CardInstance, source *CardInstance) {
+	gs := d.State
+	p := gs.Players[player]
+	for _, c := range cards {
+		p.RemoveAgent(c)
+		p.SendToScrapheap(c)
+		d.log(log.NewSendToScrapheapEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name, "sacrificed for "+source.Card.Name))
+	}
+	d.log(log.NewCostPaidEvent(gs.Turn, gs.Phase.String(), player, source.Card.Name, log.CostTribute, len(cards)))
+	d.triggerUsedAsTribute(cards, player)
+	d.recalculateContinuousEffects()
+}
+
+// hpCostDiscount totals the HP cost reductions (Optimizer Sprite, etc.) that
+// face-up cards grant the player for source's cost.
+func (d *Duel) hpCostDiscount(player int, source *CardInstance) int {
+	discount := 0
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.HPCostDiscount != nil {
+				discount += eff.HPCostDiscount(d, c, player, source)
+			}
+		}
+	}
+	return discount
+}
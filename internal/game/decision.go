@@ -0,0 +1,177 @@
+package game
+
+import (
+	"context"
+	"fmt"
+)
+
+// Decision is one answer a player gave the engine: the index of the chosen
+// action, the indices of the chosen candidates, or a yes/no.
+type Decision struct {
+	Player int    `json:"player"`
+	Kind   string `json:"kind"` // "action", "cards" or "yesno"
+	Index  int    `json:"index,omitempty"`
+	Cards  []int  `json:"cards,omitempty"`
+	Yes    bool   `json:"yes,omitempty"`
+
+	rngCalls uint64 // the RNG position when it was made, for CanUndo
+	forced   bool   // there was only one possible answer
+}
+
+// Decision kinds.
+const (
+	DecisionAction = "action"
+	DecisionCards  = "cards"
+	DecisionYesNo  = "yesno"
+)
+
+// recordingController wraps a player's controller and records every answer
+// given since the current turn began, so a saved duel can replay its way back
+// to the pending decision.
+type recordingController struct {
+	PlayerController
+	d      *Duel
+	player int
+}
+
+func (rc *recordingController) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	chosen, err := rc.PlayerController.ChooseAction(ctx, state, actions)
+	if err == nil {
+		rc.d.recordDecision(Decision{Player: rc.player, Kind: DecisionAction, Index: actionIndex(actions, chosen),
+			forced: len(actions) == 1})
+	}
+	return chosen, err
+}
+
+func (rc *recordingController) ChooseCards(ctx context.Context, state *GameState, prompt string, candidates []*CardInstance, min, max int) ([]*CardInstance, error) {
+	chosen, err := rc.PlayerController.ChooseCards(ctx, state, prompt, candidates, min, max)
+	if err == nil {
+		indices := []int{}
+		for _, c := range chosen {
+			for i, cand := range candidates {
+				if cand.ID == c.ID {
+					indices = append(indices, i)
+					break
+				}
+			}
+		}
+		rc.d.recordDecision(Decision{Player: rc.player, Kind: DecisionCards, Cards: indices,
+			forced: min == len(candidates) && max == min})
+	}
+	return chosen, err
+}
+
+func (rc *recordingController) ChooseYesNo(ctx context.Context, state *GameState, prompt string) (bool, error) {
+	yes, err := rc.PlayerController.ChooseYesNo(ctx, state, prompt)
+	if err == nil {
+		rc.d.recordDecision(Decision{Player: rc.player, Kind: DecisionYesNo, Yes: yes})
+	}
+	return yes, err
+}
+
+// recordDecision keeps a decision for SaveTo and the duel's ReplayRecorder.
+func (d *Duel) recordDecision(dec Decision) {
+	dec.rngCalls = d.rngCalls()
+	d.decisions = append(d.decisions, dec)
+	if d.recorder != nil {
+		d.recorder.record.Decisions = append(d.recorder.record.Decisions, dec)
+	}
+}
+
+// replayController answers from the duel's recorded decisions until they run
+// out, then hands over to the player's controller. The first live prompt ends
+// the catch-up, so events already seen before a save aren't logged twice.
+type replayController struct {
+	PlayerController
+	d      *Duel
+	player int
+}
+
+// next pops the recorded decision for this prompt, or returns nil once the
+// recording is used up.
+func (rc *replayController) next(kind string) (*Decision, error) {
+	d := rc.d
+	if len(d.replay) == 0 {
+		d.catchingUp = false
+		return nil, nil
+	}
+	dec := d.replay[0]
+	d.replay = d.replay[1:]
+	if dec.Player != rc.player || dec.Kind != kind {
+		return nil, fmt.Errorf("replay out of sync: P%d was asked for %s, recorded P%d %s", rc.player+1, kind, dec.Player+1, dec.Kind)
+	}
+	return &dec, nil
+}
+
+func (rc *replayController) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	dec, err := rc.next(DecisionAction)
+	if err != nil {
+		return Action{}, err
+	}
+	if dec == nil {
+		return rc.PlayerController.ChooseAction(ctx, state, actions)
+	}
+	if dec.Index < 0 || dec.Index >= len(actions) {
+		return Action{}, fmt.Errorf("replay out of sync: action %d of %d", dec.Index, len(actions))
+	}
+	return actions[dec.Index], nil
+}
+
+func (rc *replayController) ChooseCards(ctx context.Context, state *GameState, prompt string, candidates []*CardInstance, min, max int) ([]*CardInstance, error) {
+	dec, err := rc.next(DecisionCards)
+	if err != nil {
+		return nil, err
+	}
+	if dec == nil {
+		return rc.PlayerController.ChooseCards(ctx, state, prompt, candidates, min, max)
+	}
+	var chosen []*CardInstance
+	for _, i := range dec.Cards {
+		if i < 0 || i >= len(candidates) {
+			return nil, fmt.Errorf("replay out of sync: card %d of %d", i, len(candidates))
+		}
+		chosen = append(chosen, candidates[i])
+	}
+	return chosen, nil
+}
+
+func (rc *replayController) ChooseYesNo(ctx context.Context, state *GameState, prompt string) (bool, error) {
+	dec, err := rc.next(DecisionYesNo)
+	if err != nil {
+		return false, err
+	}
+	if dec == nil {
+		return rc.PlayerController.ChooseYesNo(ctx, state, prompt)
+	}
+	return dec.Yes, nil
+}
+
+// actionIndex finds chosen among the offered actions, or returns -1.
+func actionIndex(actions []Action, chosen Action) int {
+	for i, a := range actions {
+		if a.Type != chosen.Type || a.Player != chosen.Player || a.Zone != chosen.Zone ||
+			a.EffectIndex != chosen.EffectIndex || a.Desc != chosen.Desc ||
+			cardID(a.Card) != cardID(chosen.Card) || len(a.Targets) != len(chosen.Targets) {
+			continue
+		}
+		same := true
+		for j := range a.Targets {
+			if cardID(a.Targets[j]) != cardID(chosen.Targets[j]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return i
+		}
+	}
+	return -1
+}
+
+// cardID returns a card instance's ID, or 0 for nil.
+func cardID(ci *CardInstance) int {
+	if ci == nil {
+		return 0
+	}
+	return ci.ID
+}
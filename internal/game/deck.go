@@ -1,27 +1,46 @@
 package game
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Deck construction rules, enforced by ValidateDeck.
+const (
+	MinDeckSize = 40
+	MaxDeckSize = 60
+	MaxCopies   = 3 // copies of any one card
+)
+
 // DeckFile represents the top-level YAML structure.
 type DeckFile struct {
-	Decks []DeckEntry `yaml:"decks"`
+	Decks   []DeckEntry `yaml:"decks"`
+	Banlist string      `yaml:"banlist,omitempty"` // banlist file the decks are played under, relative to this file
 }
 
+// Banlist is a format's restricted list: the most copies of each listed card a
+// deck may hold. 0 is forbidden, 1 limited, 2 semi-limited; cards not listed
+// may be played up to MaxCopies.
+type Banlist map[string]int
+
 // DeckEntry represents a single deck in the YAML file.
 type DeckEntry struct {
-	Name  string      `yaml:"name"`
-	Cards []CardEntry `yaml:"cards"`
+	Name  string      `yaml:"name" json:"name"`
+	Cards []CardEntry `yaml:"cards" json:"cards"`
+	Side  []CardEntry `yaml:"side,omitempty" json:"side,omitempty"` // side deck, for match play
 }
 
 // CardEntry represents a card and its count in a deck.
 type CardEntry struct {
-	Name  string `yaml:"name"`
-	Count int    `yaml:"count"`
+	Name  string `yaml:"name" json:"name"`
+	Count int    `yaml:"count" json:"count"`
 }
 
 // ParseDeckFile parses a YAML deck file and returns a map of deck name → card slice.
@@ -52,27 +71,154 @@ func ParseDeckFile(path string) (map[string][]*Card, error) {
 
 // DeckByNumber returns the Nth deck (1-indexed) from the deck file.
 func DeckByNumber(path string, n int) (string, []*Card, error) {
-	data, err := os.ReadFile(path)
+	deck, err := DeckEntryByNumber(path, n)
 	if err != nil {
 		return "", nil, err
 	}
+	cards, err := BuildDeck(deck)
+	if err != nil {
+		return "", nil, err
+	}
+	return deck.Name, cards, nil
+}
+
+// DeckEntryByNumber returns the raw Nth deck entry (1-indexed) from the deck file.
+func DeckEntryByNumber(path string, n int) (DeckEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DeckEntry{}, err
+	}
 
 	var df DeckFile
 	if err := yaml.Unmarshal(data, &df); err != nil {
-		return "", nil, fmt.Errorf("parse deck YAML: %w", err)
+		return DeckEntry{}, fmt.Errorf("parse deck YAML: %w", err)
 	}
 
 	if n < 1 || n > len(df.Decks) {
-		return "", nil, fmt.Errorf("deck %d not found (have %d decks)", n, len(df.Decks))
+		return DeckEntry{}, fmt.Errorf("deck %d not found (have %d decks)", n, len(df.Decks))
 	}
+	return df.Decks[n-1], nil
+}
 
-	deck := df.Decks[n-1]
+// BuildDeck expands a deck entry into card definitions. Unlike LookupCard it
+// returns an error for unknown card names, since entries may come from a remote player.
+func BuildDeck(deck DeckEntry) ([]*Card, error) {
 	var cards []*Card
 	for _, entry := range deck.Cards {
+		ctor, ok := CardRegistry[entry.Name]
+		if !ok {
+			return nil, fmt.Errorf("deck %q: unknown card %q", deck.Name, entry.Name)
+		}
 		for i := 0; i < entry.Count; i++ {
-			cards = append(cards, LookupCard(entry.Name))
+			cards = append(cards, ctor())
 		}
 	}
+	return cards, nil
+}
 
-	return deck.Name, cards, nil
+// BuildSideDeck expands a deck entry's side deck into card definitions.
+func BuildSideDeck(deck DeckEntry) ([]*Card, error) {
+	return BuildDeck(DeckEntry{Name: deck.Name, Cards: deck.Side})
+}
+
+// ValidateDeck checks a main deck against the construction rules: every card
+// is in CardRegistry, the deck holds MinDeckSize to MaxDeckSize cards, and no
+// card appears more than MaxCopies times.
+func ValidateDeck(cards []*Card) error {
+	counts := make(map[string]int)
+	var names []string // in deck order, for a stable first error
+	for _, c := range cards {
+		if _, ok := CardRegistry[c.Name]; !ok {
+			return fmt.Errorf("unknown card %q", c.Name)
+		}
+		if counts[c.Name] == 0 {
+			names = append(names, c.Name)
+		}
+		counts[c.Name]++
+	}
+	if n := len(cards); n < MinDeckSize || n > MaxDeckSize {
+		return fmt.Errorf("deck has %d cards (must have %d–%d)", n, MinDeckSize, MaxDeckSize)
+	}
+	for _, name := range names {
+		if counts[name] > MaxCopies {
+			return fmt.Errorf("deck has %d copies of %q (max %d)", counts[name], name, MaxCopies)
+		}
+	}
+	return nil
+}
+
+// ValidateDeckWithBanlist checks a main deck against the construction rules
+// and the banlist. A nil banlist restricts nothing.
+func ValidateDeckWithBanlist(cards []*Card, bl Banlist) error {
+	if err := ValidateDeck(cards); err != nil {
+		return err
+	}
+	counts := make(map[string]int)
+	for _, c := range cards {
+		counts[c.Name]++
+	}
+	for _, c := range cards {
+		max, ok := bl[c.Name]
+		if !ok || counts[c.Name] <= max {
+			continue
+		}
+		if max == 0 {
+			return fmt.Errorf("%q is forbidden by the banlist", c.Name)
+		}
+		return fmt.Errorf("deck has %d copies of %q (limited to %d by the banlist)", counts[c.Name], c.Name, max)
+	}
+	return nil
+}
+
+// LoadBanlist reads a banlist YAML file: a map of card name to copies allowed.
+func LoadBanlist(path string) (Banlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bl Banlist
+	if err := yaml.Unmarshal(data, &bl); err != nil {
+		return nil, fmt.Errorf("parse banlist YAML: %w", err)
+	}
+	for name, max := range bl {
+		if _, ok := CardRegistry[name]; !ok {
+			return nil, fmt.Errorf("banlist %s: unknown card %q", path, name)
+		}
+		if max < 0 || max >= MaxCopies {
+			return nil, fmt.Errorf("banlist %s: %q allows %d copies (want 0–%d)", path, name, max, MaxCopies-1)
+		}
+	}
+	return bl, nil
+}
+
+// DeckFileBanlist returns the banlist a decks file names, or nil if it names none.
+func DeckFileBanlist(path string) (Banlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var df DeckFile
+	if err := yaml.Unmarshal(data, &df); err != nil {
+		return nil, fmt.Errorf("parse deck YAML: %w", err)
+	}
+	if df.Banlist == "" {
+		return nil, nil
+	}
+	blPath := df.Banlist
+	if !filepath.IsAbs(blPath) {
+		blPath = filepath.Join(filepath.Dir(path), blPath)
+	}
+	return LoadBanlist(blPath)
+}
+
+// DeckHash returns a commitment to a deck's contents: the hex SHA-256 of its
+// sorted card names. Order is ignored, since decks are shuffled before play.
+func DeckHash(cards []*Card) string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = c.Name
+	}
+	sort.Strings(names)
+	sum := sha256.Sum256([]byte(strings.Join(names, "\n")))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,53 @@
+package game
+
+// DeckStats summarizes a deck's composition for deck builders.
+type DeckStats struct {
+	Total       int            `json:"total"`
+	ByCardType  map[string]int `json:"by_card_type"` // "Agent", "Program", "Trap"
+	ByAttribute map[string]int `json:"by_attribute"` // agents only
+	ByAgentType map[string]int `json:"by_agent_type"`
+	LevelCurve  map[int]int    `json:"level_curve"` // agents per Level
+
+	// Effect and Vanilla count the agents with and without effects.
+	Effect  int `json:"effect"`
+	Vanilla int `json:"vanilla"`
+
+	// AvgATK and AvgDEF are the agents' mean printed stats (0 with no agents).
+	AvgATK float64 `json:"avg_atk"`
+	AvgDEF float64 `json:"avg_def"`
+}
+
+// AnalyzeDeck counts a deck's cards by type, and its agents by Attribute,
+// agent type, Level and effect, with their average ATK and DEF.
+func AnalyzeDeck(cards []*Card) DeckStats {
+	st := DeckStats{
+		Total:       len(cards),
+		ByCardType:  make(map[string]int),
+		ByAttribute: make(map[string]int),
+		ByAgentType: make(map[string]int),
+		LevelCurve:  make(map[int]int),
+	}
+	var agents, atk, def int
+	for _, c := range cards {
+		st.ByCardType[c.CardType.String()]++
+		if c.CardType != CardTypeAgent {
+			continue
+		}
+		agents++
+		atk += c.ATK
+		def += c.DEF
+		st.ByAttribute[c.Attribute.String()]++
+		st.ByAgentType[c.AgentType]++
+		st.LevelCurve[c.Level]++
+		if c.IsEffect {
+			st.Effect++
+		} else {
+			st.Vanilla++
+		}
+	}
+	if agents > 0 {
+		st.AvgATK = float64(atk) / float64(agents)
+		st.AvgDEF = float64(def) / float64(agents)
+	}
+	return st
+}
@@ -2,7 +2,10 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"time"
 
 	"github.com/peterkuimelis/tcgx/internal/log"
 )
@@ -30,6 +33,32 @@ type DuelConfig struct {
 	Seed      int64 // RNG seed (0 for random)
 	NoShuffle bool  // skip deck shuffle (for deterministic tests)
 	MaxTurns  int   // stop after this many turns (0 = no limit)
+	MaxHP     int   // HP gains clamp at this cap (0 = uncapped)
+
+	// FirstPlayer is the player (0 or 1) who takes the first turn. With
+	// CoinToss, a seeded coin toss is held instead and its winner chooses.
+	FirstPlayer int
+	CoinToss    bool
+
+	// Mulligans is how many times each player may reveal an opening hand
+	// with no agents, shuffle it back and draw a new one (0 = never).
+	Mulligans int
+
+	// DeterministicRandom makes "random" card choices (random discards)
+	// always pick the first candidate (for deterministic tests).
+	DeterministicRandom bool
+
+	// DebugRewind lets controllers return ErrRewindTurn to restore the
+	// state from the start of the current turn (local testing only).
+	DebugRewind bool
+
+	// DecisionTimeout, if set, applies a default decision when a player takes
+	// longer than this to answer a prompt. Clock defaults to the wall clock.
+	DecisionTimeout time.Duration
+	Clock           Clock
+
+	// Recorder, if set, records the duel for ReplayDuel.
+	Recorder *ReplayRecorder
 }
 
 // Duel orchestrates an entire duel between two players.
@@ -37,14 +66,39 @@ type Duel struct {
 	State       *GameState
 	Controllers [2]PlayerController
 	Logger      log.EventLogger
+	Trace       bool // log an EventTrace for each chain link as it resolves
 	ctx         context.Context
 	noShuffle   bool
 	maxTurns    int
+	debugRewind bool
+	coinToss    bool
+	mulligans   int
+	turnStart   *GameState // snapshot taken at the last turn boundary
+
+	// Save and resume: the RNG position and decisions since turnStart, and
+	// on a loaded duel the decisions still to replay (see SaveTo).
+	turnStartRNG uint64
+	decisions    []Decision
+	resumed      bool
+	replay       []Decision
+	catchingUp   bool // replaying a loaded turn; events are not logged again
+	recorder     *ReplayRecorder
+
+	clock           Clock
+	decisionTimeout time.Duration
+
+	seed                int64
+	rng                 *rand.Rand // every shuffle and random pick draws from this, so a seed reproduces the duel
+	rngSrc              *countingSource
+	deterministicRandom bool
+
+	maxHP int // 0 = uncapped
 }
 
 // NewDuel creates a new duel from the given config and player controllers.
 func NewDuel(cfg DuelConfig, p0, p1 PlayerController) *Duel {
 	gs := NewGameState()
+	gs.TurnPlayer = cfg.FirstPlayer
 	logger := cfg.Logger
 	if logger == nil {
 		logger = log.NewMemoryLogger()
@@ -67,14 +121,106 @@ func NewDuel(cfg DuelConfig, p0, p1 PlayerController) *Duel {
 		maxTurns = 200 // safety limit
 	}
 
-	return &Duel{
-		State:       gs,
-		Controllers: [2]PlayerController{p0, p1},
-		Logger:      logger,
-		ctx:         context.Background(),
-		noShuffle:   cfg.NoShuffle,
-		maxTurns:    maxTurns,
+	clock := cfg.Clock
+	if clock == nil {
+		clock = wallClock{}
 	}
+
+	if cfg.MaxHP > 0 {
+		for _, p := range gs.Players {
+			p.HP = min(p.HP, cfg.MaxHP)
+		}
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	d := &Duel{
+		State:           gs,
+		Controllers:     [2]PlayerController{p0, p1},
+		Logger:          logger,
+		ctx:             context.Background(),
+		noShuffle:       cfg.NoShuffle,
+		maxTurns:        maxTurns,
+		debugRewind:     cfg.DebugRewind,
+		coinToss:        cfg.CoinToss,
+		mulligans:       cfg.Mulligans,
+		clock:           clock,
+		decisionTimeout: cfg.DecisionTimeout,
+		seed:            seed,
+		rngSrc:          newCountingSource(seed, 0),
+		maxHP:           cfg.MaxHP,
+		recorder:        cfg.Recorder,
+
+		deterministicRandom: cfg.DeterministicRandom,
+	}
+	d.rng = rand.New(d.rngSrc)
+	d.setControllers(p0, p1)
+	if d.recorder != nil {
+		d.recorder.start(cfg, seed, maxTurns)
+	}
+	return d
+}
+
+// setControllers installs the players' controllers behind the duel's wrappers:
+// the decision timeout, replay of recorded decisions, and decision recording.
+func (d *Duel) setControllers(p0, p1 PlayerController) {
+	d.Controllers = [2]PlayerController{p0, p1}
+	for i := 0; i < 2; i++ {
+		if d.decisionTimeout > 0 {
+			d.Controllers[i] = &timeoutController{PlayerController: d.Controllers[i], d: d, player: i}
+		}
+		// Always wrapped: an undone decision replays the turn up to it
+		d.Controllers[i] = &replayController{PlayerController: d.Controllers[i], d: d, player: i}
+		d.Controllers[i] = &recordingController{PlayerController: d.Controllers[i], d: d, player: i}
+	}
+}
+
+// countingSource is the duel's RNG source. It counts the values drawn so a
+// saved duel can put a fresh source back at the same position.
+type countingSource struct {
+	src   rand.Source64
+	calls uint64
+}
+
+// newCountingSource seeds a source and advances it past calls values.
+func newCountingSource(seed int64, calls uint64) *countingSource {
+	s := &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+	for s.calls < calls {
+		s.Uint64()
+	}
+	return s
+}
+
+func (s *countingSource) Int63() int64 {
+	s.calls++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.calls++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.calls = 0
+}
+
+// rngCalls returns how many values the duel's RNG has drawn.
+func (d *Duel) rngCalls() uint64 {
+	if d.rngSrc == nil {
+		return 0
+	}
+	return d.rngSrc.calls
+}
+
+// Seed returns the RNG seed in effect: DuelConfig.Seed, or the time-based seed
+// chosen when it was 0. Starting a duel with the same seed and decks reproduces it.
+func (d *Duel) Seed() int64 {
+	return d.seed
 }
 
 // Run executes the entire duel loop. Returns the winner (0, 1, or -1 for draw).
@@ -82,18 +228,32 @@ func (d *Duel) Run(ctx context.Context) (int, error) {
 	d.ctx = ctx
 	gs := d.State
 
-	// Setup: shuffle decks (unless disabled for tests)
-	if !d.noShuffle {
-		gs.Players[0].ShuffleDeck()
-		gs.Players[1].ShuffleDeck()
-	}
+	// A loaded duel is already set up and resumes at the start of its turn
+	if !d.resumed {
+		if d.coinToss {
+			if err := d.tossCoin(); err != nil {
+				return -1, err
+			}
+		}
+
+		// Setup: shuffle decks (unless disabled for tests)
+		if !d.noShuffle {
+			gs.Players[0].ShuffleDeck(d.rng)
+			gs.Players[1].ShuffleDeck(d.rng)
+		}
 
-	// Draw initial hands (5 cards each)
-	for i := 0; i < InitialHandSize; i++ {
-		for p := 0; p < 2; p++ {
-			card := gs.Players[p].DrawCard()
-			if card == nil {
-				return -1, fmt.Errorf("player %d has insufficient cards for initial hand", p)
+		// Draw initial hands (5 cards each)
+		for i := 0; i < InitialHandSize; i++ {
+			for p := 0; p < 2; p++ {
+				card := gs.Players[p].DrawCard()
+				if card == nil {
+					return -1, fmt.Errorf("player %d has insufficient cards for initial hand", p)
+				}
+			}
+		}
+		for _, p := range []int{gs.TurnPlayer, gs.Opponent(gs.TurnPlayer)} {
+			if err := d.offerMulligans(p); err != nil {
+				return -1, err
 			}
 		}
 	}
@@ -103,10 +263,22 @@ func (d *Duel) Run(ctx context.Context) (int, error) {
 		if gs.Turn >= d.maxTurns {
 			gs.Over = true
 			gs.Winner = -1
+			gs.WinReason = WinReasonTurnLimit
 			gs.Result = fmt.Sprintf("Turn limit reached (%d turns)", d.maxTurns)
 			break
 		}
+		d.turnStart = gs.Snapshot()
+		d.turnStartRNG = d.rngCalls()
+		d.decisions = nil
 		if err := d.runTurn(); err != nil {
+			if d.debugRewind && errors.Is(err, ErrRewindTurn) {
+				d.rewindTurn()
+				continue
+			}
+			if errors.Is(err, ErrUndoDecision) && d.lastChoice() >= 0 {
+				d.undoDecision()
+				continue
+			}
 			return gs.Winner, err
 		}
 		if err := d.ctx.Err(); err != nil {
@@ -117,6 +289,66 @@ func (d *Duel) Run(ctx context.Context) (int, error) {
 	return gs.Winner, nil
 }
 
+// tossCoin holds the pre-game coin toss. Its winner chooses whether to take
+// the first turn.
+func (d *Duel) tossCoin() error {
+	gs := d.State
+	winner := d.rng.Intn(2)
+	goFirst, err := d.Controllers[winner].ChooseYesNo(d.ctx, gs, "You won the coin toss. Go first?")
+	if err != nil {
+		return err
+	}
+	gs.TurnPlayer = winner
+	if !goFirst {
+		gs.TurnPlayer = gs.Opponent(winner)
+	}
+	gs.CoinToss = &CoinToss{Winner: winner, GoFirst: goFirst}
+	d.log(log.NewCoinTossEvent(winner, goFirst))
+	return nil
+}
+
+// offerMulligans lets player redraw an opening hand with no agents, up to
+// the configured number of times. The hand is revealed, shuffled back into
+// the deck (put on the bottom with NoShuffle) and a new one drawn.
+func (d *Duel) offerMulligans(player int) error {
+	gs := d.State
+	p := gs.Players[player]
+	for n := 0; n < d.mulligans && !hasAgent(p.Hand); n++ {
+		yes, err := d.Controllers[player].ChooseYesNo(d.ctx, gs, "Your opening hand has no agents. Mulligan?")
+		if err != nil || !yes {
+			return err
+		}
+		revealed := make([]string, len(p.Hand))
+		for i, c := range p.Hand {
+			revealed[i] = c.Card.Name
+		}
+		d.log(log.NewMulliganEvent(player, revealed))
+
+		hand := p.Hand
+		p.Hand = nil
+		for _, c := range hand {
+			p.PlaceOnDeckBottom(c)
+		}
+		if !d.noShuffle {
+			p.ShuffleDeck(d.rng)
+		}
+		for i := 0; i < len(hand); i++ {
+			p.DrawCard()
+		}
+	}
+	return nil
+}
+
+// hasAgent reports whether cards include an agent.
+func hasAgent(cards []*CardInstance) bool {
+	for _, c := range cards {
+		if c.Card.CardType == CardTypeAgent {
+			return true
+		}
+	}
+	return false
+}
+
 // runTurn executes a single turn for the current turn player.
 func (d *Duel) runTurn() error {
 	gs := d.State
@@ -176,8 +408,12 @@ func (d *Duel) runTurn() error {
 		return err
 	}
 
-	// Switch turn player
-	gs.TurnPlayer = gs.Opponent(gs.TurnPlayer)
+	// Switch turn player, unless an extra turn is pending (Time Dilation Core)
+	if gs.ExtraTurnFor >= 0 {
+		gs.TurnPlayer, gs.ExtraTurnFor = gs.ExtraTurnFor, -1
+	} else {
+		gs.TurnPlayer = gs.Opponent(gs.TurnPlayer)
+	}
 
 	return nil
 }
@@ -190,13 +426,15 @@ func (d *Duel) drawPhase() error {
 
 	// Goat rule: first player DOES draw on turn 1
 	p := gs.CurrentPlayer()
+	if p.SkipNextDraw {
+		p.SkipNextDraw = false
+		d.log(log.NewSkipDrawEvent(gs.Turn, gs.Phase.String(), gs.TurnPlayer))
+		return nil
+	}
 	card := p.DrawCard()
 	if card == nil {
 		// Deck out — current player loses
-		gs.Over = true
-		gs.Winner = gs.Opponent(gs.TurnPlayer)
-		gs.Result = fmt.Sprintf("P%d wins — P%d decked out", gs.Winner+1, gs.TurnPlayer+1)
-		d.log(log.NewWinEvent(gs.Turn, gs.Phase.String(), gs.Winner, "deck out"))
+		d.deckOut(gs.TurnPlayer)
 		return nil
 	}
 	d.log(log.NewDrawEvent(gs.Turn, gs.Phase.String(), gs.TurnPlayer, card.Card.Name))
@@ -204,6 +442,31 @@ func (d *Duel) drawPhase() error {
 	return nil
 }
 
+// deckOut ends the duel with player losing for being unable to draw.
+func (d *Duel) deckOut(player int) {
+	gs := d.State
+	gs.Over = true
+	gs.Winner = gs.Opponent(player)
+	gs.WinReason = WinReasonDeckout
+	gs.Result = fmt.Sprintf("P%d wins — P%d decked out", gs.Winner+1, player+1)
+	d.log(log.NewWinEvent(gs.Turn, gs.Phase.String(), gs.Winner, "deck out"))
+}
+
+// drawForEffect draws n cards for player. A player who must draw from an empty
+// Deck loses at once; it reports whether all n cards were drawn.
+func (d *Duel) drawForEffect(player int, n int) bool {
+	gs := d.State
+	for i := 0; i < n; i++ {
+		card := gs.Players[player].DrawCard()
+		if card == nil {
+			d.deckOut(player)
+			return false
+		}
+		d.log(log.NewDrawEvent(gs.Turn, gs.Phase.String(), player, card.Card.Name))
+	}
+	return true
+}
+
 // standbyPhase executes the Standby Phase.
 func (d *Duel) standbyPhase() error {
 	gs := d.State
@@ -211,34 +474,72 @@ func (d *Duel) standbyPhase() error {
 	d.log(log.NewPhaseChangeEvent(gs.Turn, gs.Phase.String()))
 
 	// Process standby phase triggers (e.g. Sinister Serpent, Snatch Steal HP gain)
-	d.processStandbyTriggers()
+	return d.processStandbyTriggers()
+}
 
-	return nil
+// collectStandbyEffects returns the OnStandby effects on a player's side of
+// the field, as actions to apply them: face-up tech first, then face-up
+// agents, each in zone order.
+func (d *Duel) collectStandbyEffects(player int) []Action {
+	p := d.State.Players[player]
+	var result []Action
+	add := func(card *CardInstance) {
+		for i, eff := range card.Card.Effects {
+			if eff.OnStandby != nil {
+				result = append(result, Action{
+					Type:        ActionActivate,
+					Player:      player,
+					Card:        card,
+					EffectIndex: i,
+					Desc:        "Apply " + eff.Name,
+				})
+			}
+		}
+	}
+	for _, card := range p.TechCards() {
+		if card.Face == FaceUp {
+			add(card)
+		}
+	}
+	for _, card := range p.FaceUpAgents() {
+		add(card)
+	}
+	return result
 }
 
 // processStandbyTriggers checks for effects that trigger during the Standby Phase.
-func (d *Duel) processStandbyTriggers() {
+// The turn player chooses the order of their own simultaneous standby effects; the
+// opponent's are applied afterwards in field order (see collectStandbyEffects).
+func (d *Duel) processStandbyTriggers() error {
 	gs := d.State
 	tp := gs.TurnPlayer
 
-	// Check all face-up tech and agents for standby triggers
-	for p := 0; p < 2; p++ {
-		for _, card := range gs.Players[p].TechCards() {
-			if card.Face != FaceUp {
-				continue
+	pending := d.collectStandbyEffects(tp)
+	for len(pending) > 0 {
+		next := 0
+		if len(pending) > 1 {
+			chosen, err := d.Controllers[tp].ChooseAction(d.ctx, gs, pending)
+			if err != nil {
+				return err
 			}
-			for _, eff := range card.Card.Effects {
-				if eff.OnFieldEffect != nil {
-					eff.OnFieldEffect(d, card, p)
+			for i, a := range pending {
+				if a.Card == chosen.Card && a.EffectIndex == chosen.EffectIndex {
+					next = i
+					break
 				}
 			}
 		}
-		for _, card := range gs.Players[p].FaceUpAgents() {
-			for _, eff := range card.Card.Effects {
-				if eff.OnFieldEffect != nil {
-					eff.OnFieldEffect(d, card, p)
-				}
-			}
+		a := pending[next]
+		pending = append(pending[:next], pending[next+1:]...)
+		if d.isOnField(a.Card) {
+			a.Effect().OnStandby(d, a.Card, tp)
+		}
+	}
+
+	ntp := gs.Opponent(tp)
+	for _, a := range d.collectStandbyEffects(ntp) {
+		if d.isOnField(a.Card) {
+			a.Effect().OnStandby(d, a.Card, ntp)
 		}
 	}
 
@@ -261,6 +562,7 @@ func (d *Duel) processStandbyTriggers() {
 			}
 		}
 	}
+	return nil
 }
 
 // mainPhase executes a Main Phase (1 or 2).
@@ -282,60 +584,77 @@ func (d *Duel) mainPhase(phase Phase) error {
 			return err
 		}
 
-		switch chosen.Type {
-		case ActionNormalSummon:
-			if err := d.executeNormalSummon(chosen); err != nil {
-				return err
-			}
-		case ActionNormalSet:
-			if err := d.executeNormalSet(chosen); err != nil {
-				return err
-			}
-		case ActionSacrificeSummon:
-			if err := d.executeSacrificeSummon(chosen); err != nil {
-				return err
-			}
-		case ActionSacrificeSet:
-			if err := d.executeSacrificeSet(chosen); err != nil {
-				return err
-			}
-		case ActionFlipSummon:
-			if err := d.executeFlipSummon(chosen); err != nil {
-				return err
-			}
-		case ActionChangePosition:
-			d.executeChangePosition(chosen)
-		case ActionSetTech:
-			if err := d.executeSetTech(chosen); err != nil {
-				return err
-			}
-		case ActionActivate:
-			if err := d.executeActivateEffect(chosen); err != nil {
-				return err
-			}
-			// Open response window for opponent to chain
-			if err := d.openResponseWindow(gs.Opponent(tp)); err != nil {
-				return err
-			}
-			// Resolve the chain
-			if err := d.resolveChain(); err != nil {
-				return err
-			}
-		case ActionEnterBattlePhase:
-			gs.Phase = PhaseBattle
-			return nil
-		case ActionEndTurn:
-			return nil
+		if done, err := d.executeMainPhaseAction(chosen); done || err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// executeMainPhaseAction carries out the turn player's chosen Main Phase
+// action, resolving any chain it starts. It reports whether the action ends
+// the phase.
+func (d *Duel) executeMainPhaseAction(chosen Action) (bool, error) {
+	gs := d.State
+	switch chosen.Type {
+	case ActionNormalSummon:
+		return false, d.executeNormalSummon(chosen)
+	case ActionNormalSet:
+		return false, d.executeNormalSet(chosen)
+	case ActionSacrificeSummon:
+		return false, d.executeSacrificeSummon(chosen)
+	case ActionSacrificeSet:
+		return false, d.executeSacrificeSet(chosen)
+	case ActionFlipSummon:
+		return false, d.executeFlipSummon(chosen)
+	case ActionChangePosition:
+		d.executeChangePosition(chosen)
+	case ActionSetTech:
+		return false, d.executeSetTech(chosen)
+	case ActionActivate:
+		if err := d.executeActivateEffect(chosen); err != nil {
+			return false, err
+		}
+		// Open response window for opponent to chain
+		if err := d.openResponseWindow(gs.Opponent(gs.TurnPlayer), log.EventActivate); err != nil {
+			return false, err
+		}
+		// Resolve the chain
+		return false, d.resolveChain()
+	case ActionEnterBattlePhase:
+		gs.Phase = PhaseBattle
+		return true, nil
+	case ActionEndTurn:
+		return true, nil
+	case ActionConcede:
+		d.concede(chosen.Player)
+		return true, nil
+	}
+	return false, nil
+}
+
+// concedeAction is the action that lets player forfeit the duel.
+func concedeAction(player int) Action {
+	return Action{Type: ActionConcede, Player: player, Desc: "Concede the duel"}
+}
+
+// concede ends the duel with player's opponent as the winner.
+func (d *Duel) concede(player int) {
+	gs := d.State
+	gs.Over = true
+	gs.Winner = gs.Opponent(player)
+	gs.WinReason = WinReasonSurrender
+	gs.Result = fmt.Sprintf("P%d wins — P%d conceded", gs.Winner+1, player+1)
+	d.log(log.NewConcedeEvent(gs.Turn, gs.Phase.String(), player))
+	d.log(log.NewWinEvent(gs.Turn, gs.Phase.String(), gs.Winner, "concession"))
+}
+
 // battlePhase executes the Battle Phase.
 func (d *Duel) battlePhase() error {
 	gs := d.State
 	gs.BattleStep = BattleStepStart
+	gs.DestroyedByBattle = [2]bool{}
 	d.log(log.NewPhaseChangeEvent(gs.Turn, gs.Phase.String()))
 
 	// Start Step — just advance for now (fast effects added in Phase 2)
@@ -354,29 +673,57 @@ func (d *Duel) battlePhase() error {
 			return err
 		}
 
-		switch chosen.Type {
-		case ActionAttack:
-			if err := d.executeAttack(chosen); err != nil {
-				return err
-			}
-		case ActionDirectAttack:
-			if err := d.executeDirectAttack(chosen); err != nil {
-				return err
-			}
-		case ActionEndBattlePhase:
-			gs.BattleStep = BattleStepEnd
-			return nil
-		case ActionEnterMainPhase2:
-			gs.BattleStep = BattleStepEnd
-			gs.Phase = PhaseMain2
-			return nil
+		if done, err := d.executeBattlePhaseAction(chosen); done || err != nil {
+			return err
 		}
 	}
 
-	gs.BattleStep = BattleStepEnd
+	d.endBattlePhase()
 	return nil
 }
 
+// executeBattlePhaseAction carries out the turn player's chosen Battle Phase
+// action. It reports whether the action ends the Battle Phase.
+func (d *Duel) executeBattlePhaseAction(chosen Action) (bool, error) {
+	switch chosen.Type {
+	case ActionAttack:
+		return false, d.executeAttack(chosen)
+	case ActionDirectAttack:
+		return false, d.executeDirectAttack(chosen)
+	case ActionEndBattlePhase:
+		d.endBattlePhase()
+		return true, nil
+	case ActionEnterMainPhase2:
+		d.endBattlePhase()
+		d.State.Phase = PhaseMain2
+		return true, nil
+	case ActionConcede:
+		d.concede(chosen.Player)
+		return true, nil
+	}
+	return false, nil
+}
+
+// endBattlePhase runs the End Step: face-up cards' OnBattlePhaseEnd effects
+// (Spoils Protocol).
+func (d *Duel) endBattlePhase() {
+	gs := d.State
+	gs.BattleStep = BattleStepEnd
+	for _, c := range d.faceUpCards() {
+		if gs.Over {
+			return
+		}
+		if d.activationPending(c) {
+			continue
+		}
+		for _, eff := range c.Card.Effects {
+			if eff.OnBattlePhaseEnd != nil {
+				eff.OnBattlePhaseEnd(d, c, c.Controller)
+			}
+		}
+	}
+}
+
 // endPhase executes the End Phase.
 func (d *Duel) endPhase() error {
 	gs := d.State
@@ -406,12 +753,40 @@ func (d *Duel) endPhase() error {
 			p.RemoveFromHand(card)
 			p.SendToScrapheap(card)
 			d.log(log.NewDiscardEvent(gs.Turn, gs.Phase.String(), gs.TurnPlayer, card.Card.Name))
+			d.recalculateContinuousEffects()
 		}
 	}
 
+	// "Until the End Phase" control changes revert, then "until the end of
+	// this turn" modifiers expire
+	d.returnTemporaryControl()
+	d.clearEndOfTurnModifiers()
+
 	return nil
 }
 
+// clearEndOfTurnModifiers strips modifiers and stat swaps (Polarity Invert) that
+// only last until the end of the turn from every agent, wherever it currently is.
+func (d *Duel) clearEndOfTurnModifiers() {
+	gs := d.State
+	for p := 0; p < 2; p++ {
+		pl := gs.Players[p]
+		cards := append(pl.Agents(), pl.Hand...)
+		cards = append(cards, pl.Scrapheap...)
+		cards = append(cards, pl.Purged...)
+		for _, c := range cards {
+			var keep []StatModifier
+			for _, mod := range c.Modifiers {
+				if !mod.EndOfTurn {
+					keep = append(keep, mod)
+				}
+			}
+			c.Modifiers = keep
+			c.StatsSwapped = false
+		}
+	}
+}
+
 // processEndPhaseTriggers processes effects that activate during the End Phase.
 func (d *Duel) processEndPhaseTriggers() {
 	gs := d.State
@@ -499,7 +874,7 @@ func (d *Duel) recalculateContinuousEffects() {
 	// Re-apply from all face-up continuous sources
 	for p := 0; p < 2; p++ {
 		// Check OS cards
-		if fs := gs.Players[p].OS; fs != nil && fs.Face == FaceUp {
+		if fs := gs.Players[p].OS; fs != nil && fs.Face == FaceUp && !d.activationPending(fs) {
 			for _, eff := range fs.Card.Effects {
 				if eff.ContinuousApply != nil {
 					eff.ContinuousApply(d, fs, p)
@@ -516,7 +891,7 @@ func (d *Duel) recalculateContinuousEffects() {
 		}
 		// Check face-up tech
 		for _, st := range gs.Players[p].TechCards() {
-			if st.Face != FaceUp {
+			if st.Face != FaceUp || d.activationPending(st) {
 				continue
 			}
 			for _, eff := range st.Card.Effects {
@@ -526,10 +901,40 @@ func (d *Duel) recalculateContinuousEffects() {
 			}
 		}
 	}
+
+	// "ATK becomes X" effects go last so they see every other modifier
+	for p := 0; p < 2; p++ {
+		for _, m := range gs.Players[p].FaceUpAgents() {
+			for _, eff := range m.Card.Effects {
+				if eff.SetATK == nil {
+					continue
+				}
+				if atk, ok := eff.SetATK(d, m, m.Controller); ok {
+					m.AddModifier(StatModifier{Source: m.ID, ATKMod: atk - m.CurrentATK(), Continuous: true})
+				}
+			}
+		}
+	}
+}
+
+// VerifyDecks checks the cards each player owns, together with aside (cards
+// of theirs kept out of the duel, such as a match's side deck), against the
+// DeckHash they committed to before the duel, logging EventDeckVerified for
+// each player. It reports, per player, whether their cards match the commitment.
+func (d *Duel) VerifyDecks(commitments [2]string, aside [2][]*Card) [2]bool {
+	var ok [2]bool
+	for p := 0; p < 2; p++ {
+		ok[p] = DeckHash(append(d.State.OwnedCards(p), aside[p]...)) == commitments[p]
+		d.log(log.NewDeckVerifiedEvent(d.State.Turn, p, ok[p]))
+	}
+	return ok
 }
 
 // log emits a game event through the logger and notifies both players.
 func (d *Duel) log(event log.GameEvent) {
+	if d.catchingUp {
+		return
+	}
 	d.Logger.Log(event)
 	// Notify controllers (ignore errors for notifications)
 	for i := 0; i < 2; i++ {
@@ -1,8 +1,22 @@
 package game
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/peterkuimelis/tcgx/internal/log"
 )
@@ -354,3 +368,1190 @@ func TestSecondPlayerCanAttackTurn2(t *testing.T) {
 		t.Error("Second player should be able to enter Battle Phase on turn 2")
 	}
 }
+
+// rewindController wraps a ScriptedController and asks to rewind once when it
+// reaches Main Phase 2 of the given turn. It records a fingerprint of the state
+// at the first Main Phase 1 prompt of that turn, before and after the rewind.
+type rewindController struct {
+	*ScriptedController
+	turn     int
+	rewound  bool
+	before   string
+	after    string
+	afterHP1 int
+}
+
+func (rc *rewindController) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	if state.Turn == rc.turn && state.Phase == PhaseMain1 {
+		if !rc.rewound && rc.before == "" {
+			rc.before = stateFingerprint(state)
+		} else if rc.rewound && rc.after == "" {
+			rc.after = stateFingerprint(state)
+			rc.afterHP1 = state.Players[1].HP
+		}
+	}
+	if state.Turn == rc.turn && state.Phase == PhaseMain2 && !rc.rewound {
+		rc.rewound = true
+		return Action{}, ErrRewindTurn
+	}
+	return rc.ScriptedController.ChooseAction(ctx, state, actions)
+}
+
+// stateFingerprint summarizes the observable state of both players.
+func stateFingerprint(gs *GameState) string {
+	names := func(cards []*CardInstance) []string {
+		var out []string
+		for _, c := range cards {
+			out = append(out, fmt.Sprintf("%s#%d", c.Card.Name, c.ID))
+		}
+		return out
+	}
+	s := fmt.Sprintf("T%d %s tp=%d ns=%v", gs.Turn, gs.Phase, gs.TurnPlayer, gs.NormalSummonUsed)
+	for i, p := range gs.Players {
+		s += fmt.Sprintf(" | P%d hp=%d deck=%d hand=%v heap=%v field=[", i, p.HP, len(p.Deck), names(p.Hand), names(p.Scrapheap))
+		for _, m := range p.AgentZones {
+			if m != nil {
+				s += fmt.Sprintf("%s#%d %s %d ", m.Card.Name, m.ID, m.Position, m.CurrentATK())
+			}
+		}
+		s += "]"
+	}
+	return s
+}
+
+func TestDebugRewindRestoresTurnStart(t *testing.T) {
+	raider := vanillaAgent("Raider", 4, 1800, 1000, AttrFIRE)
+	backup := vanillaAgent("Backup", 4, 1200, 1000, AttrFIRE)
+	deck0 := makePaddedDeck([]*Card{raider, backup}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	script := NewScriptedController(t, "P1")
+	// Turn 1: Summon Raider
+	script.AddAction(ActionNormalSummon, "Raider")
+	// Turn 3: Summon Backup and attack directly, then rewind from Main Phase 2
+	script.AddAction(ActionNormalSummon, "Backup")
+	script.AddAction(ActionEnterBattlePhase, "")
+	script.AddDirectAttack("Raider")
+	p0 := &rewindController{ScriptedController: script, turn: 3}
+	p1 := NewScriptedController(t, "P2")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3, DebugRewind: true}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if !p0.rewound {
+		t.Fatal("Expected the controller to request a rewind")
+	}
+	if len(logger.EventsOfType(log.EventRewind)) != 1 {
+		t.Errorf("Expected one Rewind event")
+	}
+	if p0.before == "" || p0.after != p0.before {
+		t.Errorf("Rewound state differs from turn start:\nbefore: %s\nafter:  %s", p0.before, p0.after)
+	}
+	if p0.afterHP1 != StartingHP {
+		t.Errorf("Expected direct attack to be undone (P2 HP %d), got %d", StartingHP, p0.afterHP1)
+	}
+	// Script was already consumed, so the replayed turn makes no plays
+	if findAgent(duel, 0, "Backup") != nil {
+		t.Error("Expected Backup's summon to be undone")
+	}
+	if duel.State.Players[1].HP != StartingHP {
+		t.Errorf("Expected P2 HP %d after replayed turn, got %d", StartingHP, duel.State.Players[1].HP)
+	}
+}
+
+// rngRewinder draws from the duel's RNG in Main Phase 1 of turn, then rewinds
+// once from Main Phase 2 and draws again.
+type rngRewinder struct {
+	*ScriptedController
+	duel    *Duel
+	turn    int
+	rewound bool
+	draws   []int64
+}
+
+func (rr *rngRewinder) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	if state.Turn == rr.turn {
+		switch {
+		case state.Phase == PhaseMain1 && (len(rr.draws) == 0 || rr.rewound && len(rr.draws) == 1):
+			rr.draws = append(rr.draws, rr.duel.rng.Int63())
+		case state.Phase == PhaseMain2 && !rr.rewound:
+			rr.rewound = true
+			return Action{}, ErrRewindTurn
+		}
+	}
+	return rr.ScriptedController.ChooseAction(ctx, state, actions)
+}
+
+// TestDebugRewindRestoresRNG: the replayed turn gets the same random outcomes.
+func TestDebugRewindRestoresRNG(t *testing.T) {
+	p0 := &rngRewinder{ScriptedController: NewScriptedController(t, "P1"), turn: 3}
+	p0.AddAction(ActionEnterBattlePhase, "")
+	cfg := DuelConfig{Deck0: makePaddedDeck(nil, 40), Deck1: makePaddedDeck(nil, 40), Seed: 9, MaxTurns: 3, DebugRewind: true}
+	duel := NewDuel(cfg, p0, NewScriptedController(t, "P2"))
+	p0.duel = duel
+	if _, err := duel.Run(context.Background()); err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+
+	if len(p0.draws) != 2 {
+		t.Fatalf("Expected a draw before and after the rewind, got %d", len(p0.draws))
+	}
+	if p0.draws[0] != p0.draws[1] {
+		t.Errorf("Expected the rewound turn to draw %d again, got %d", p0.draws[0], p0.draws[1])
+	}
+}
+
+func TestSnapshotIsIndependent(t *testing.T) {
+	gs := NewGameState()
+	agent := gs.CreateCardInstance(vanillaAgent("Raider", 4, 1800, 1000, AttrFIRE), 0)
+	equip := gs.CreateCardInstance(&Card{Name: "Blade", CardType: CardTypeProgram}, 0)
+	agent.Equips = []*CardInstance{equip}
+	equip.EquippedTo = agent
+	agent.Counters["charge"] = 1
+	gs.Players[0].PlaceAgent(agent, 0)
+	gs.Players[0].PlaceTech(equip, 0)
+
+	snap := gs.Snapshot()
+	sa := snap.Players[0].AgentZones[0]
+	if sa == agent {
+		t.Fatal("Expected snapshot to clone card instances")
+	}
+	if sa.Equips[0] != snap.Players[0].TechZones[0] || snap.Players[0].TechZones[0].EquippedTo != sa {
+		t.Error("Expected equip links to point into the snapshot")
+	}
+
+	agent.Counters["charge"] = 5
+	agent.AddModifier(StatModifier{ATKMod: 500})
+	gs.Players[0].HP = 100
+	if sa.Counters["charge"] != 1 || sa.CurrentATK() != 1800 || snap.Players[0].HP != StartingHP {
+		t.Error("Expected snapshot to be unaffected by later changes")
+	}
+	if gs.NextID() != snap.NextID() {
+		t.Error("Expected snapshot to continue the same ID sequence")
+	}
+}
+
+func TestCloneForSimulationIsIndependent(t *testing.T) {
+	duel := NewDuel(DuelConfig{Deck0: makePaddedDeck(nil, 10), Deck1: makePaddedDeck(nil, 10)},
+		NewRandomController(1), NewRandomController(2))
+	gs := duel.State
+	agent := gs.CreateCardInstance(vanillaAgent("Raider", 4, 1800, 1000, AttrFIRE), 0)
+	equip := gs.CreateCardInstance(&Card{Name: "Blade", CardType: CardTypeProgram}, 0)
+	agent.Equips = []*CardInstance{equip}
+	equip.EquippedTo = agent
+	gs.Players[0].PlaceAgent(agent, 0)
+	gs.Players[0].PlaceTech(equip, 0)
+
+	sim := duel.CloneForSimulation(NewRandomController(3), NewRandomController(4))
+	simAgent := sim.State.Players[0].AgentZones[0]
+	if simAgent == agent || simAgent.ID != agent.ID {
+		t.Fatal("Expected the simulation to hold a copy of Raider with the same ID")
+	}
+
+	sim.destroyByEffect(simAgent, nil, "simulation")
+	sim.applyDamage(1, 1000, "simulation")
+	sim.State.Players[0].DrawCard()
+
+	if gs.Players[0].AgentZones[0] != agent || gs.Players[0].TechZones[0] != equip || agent.Equips[0] != equip {
+		t.Error("Expected Raider and its equip to stay on the live field")
+	}
+	if gs.Players[1].HP != StartingHP || gs.Players[0].DeckCount() != 10 {
+		t.Error("Expected live HP and deck to be unaffected by the simulation")
+	}
+	if len(duel.Logger.(*log.MemoryLogger).Events()) != 0 {
+		t.Error("Expected simulation events to stay out of the live log")
+	}
+}
+
+// TestApplyActionOnSimulation: actions computed on the live duel play out on
+// the clone, chain included, and leave the live duel as it was.
+func TestApplyActionOnSimulation(t *testing.T) {
+	zap := normalProgram("Zap", &CardEffect{
+		Name:      "Zap",
+		ExecSpeed: ExecSpeed1,
+		Resolve: func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error {
+			d.applyDamage(d.State.Opponent(player), 500, "Zap")
+			return nil
+		},
+	})
+	duel := NewDuel(DuelConfig{Deck0: makePaddedDeck(nil, 10), Deck1: makePaddedDeck(nil, 10)},
+		NewRandomController(1), NewRandomController(2))
+	gs := duel.State
+	gs.Phase = PhaseMain1
+	raider := gs.CreateCardInstance(vanillaAgent("Raider", 4, 1800, 1000, AttrFIRE), 0)
+	zapCard := gs.CreateCardInstance(zap, 0)
+	gs.Players[0].PlaceOnDeckTop(raider)
+	gs.Players[0].PlaceOnDeckTop(zapCard)
+	gs.Players[0].DrawCard()
+	gs.Players[0].DrawCard()
+
+	find := func(typ ActionType, card *CardInstance) Action {
+		for _, a := range duel.computeMainPhaseActions(0) {
+			if a.Type == typ && a.Card == card {
+				return a
+			}
+		}
+		t.Fatalf("Expected a %v action for %s", typ, card.Card.Name)
+		return Action{}
+	}
+	summon := find(ActionNormalSummon, raider)
+	activate := find(ActionActivate, zapCard)
+
+	sim := duel.CloneForSimulation(NewRandomController(3), NewRandomController(4))
+	if err := sim.ApplyAction(summon); err != nil {
+		t.Fatalf("ApplyAction(summon): %v", err)
+	}
+	if err := sim.ApplyAction(activate); err != nil {
+		t.Fatalf("ApplyAction(activate): %v", err)
+	}
+
+	sp := sim.State.Players[0]
+	if len(sp.Agents()) != 1 || sp.Agents()[0].ID != raider.ID || !sim.State.NormalSummonUsed {
+		t.Error("Expected Raider to be Normal Summoned in the simulation")
+	}
+	if len(sp.Hand) != 0 || len(sp.Scrapheap) != 1 || sp.Scrapheap[0].ID != zapCard.ID {
+		t.Error("Expected Zap to resolve and go to the simulation's scrapheap")
+	}
+	if sim.State.Players[1].HP != StartingHP-500 {
+		t.Errorf("Expected the simulated opponent at %d HP, got %d", StartingHP-500, sim.State.Players[1].HP)
+	}
+
+	lp := gs.Players[0]
+	if len(lp.Agents()) != 0 || len(lp.Hand) != 2 || len(lp.Scrapheap) != 0 || gs.NormalSummonUsed {
+		t.Error("Expected the live field and hand to be unaffected by the simulation")
+	}
+	if gs.Players[1].HP != StartingHP {
+		t.Error("Expected live HP to be unaffected by the simulation")
+	}
+}
+
+// TestStandbyEffectOrder: the turn player orders their own standby effects, then
+// the opponent's apply in field order.
+func TestStandbyEffectOrder(t *testing.T) {
+	type firing struct {
+		turn int
+		name string
+	}
+	var order []firing
+	upkeep := func(name string) *Card {
+		return &Card{
+			Name:       name,
+			CardType:   CardTypeProgram,
+			ProgramSub: ProgramContinuous,
+			Effects: []*CardEffect{{
+				Name:       name,
+				ExecSpeed:  ExecSpeed1,
+				EffectType: EffectContinuous,
+				OnStandby: func(d *Duel, card *CardInstance, player int) {
+					order = append(order, firing{d.State.Turn, name})
+				},
+			}},
+		}
+	}
+
+	deck0 := makePaddedDeck([]*Card{upkeep("Upkeep A"), upkeep("Upkeep B")}, 40)
+	deck1 := makePaddedDeck([]*Card{upkeep("Upkeep C")}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := NewScriptedController(t, "P2")
+
+	// Turn 1 (P1): Activate A then B; Turn 3 standby: apply B first
+	p0.AddAction(ActionActivate, "Upkeep A")
+	p0.AddAction(ActionActivate, "Upkeep B")
+	p0.AddAction(ActionActivate, "Upkeep B")
+	// Turn 2 (P2): Activate C
+	p1.AddAction(ActionActivate, "Upkeep C")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	runDuel(t, cfg, p0, p1)
+
+	want := []firing{
+		{2, "Upkeep A"}, {2, "Upkeep B"}, // P2's turn: P1's effects in field order
+		{3, "Upkeep B"}, {3, "Upkeep A"}, // P1's turn: P1 chose B first
+		{3, "Upkeep C"}, // then the opponent's
+	}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("Expected standby order %v, got %v", want, order)
+	}
+}
+
+// standbyPrompts wraps a ScriptedController and counts the action prompts it
+// gets during Standby Phases.
+type standbyPrompts struct {
+	*ScriptedController
+	n int
+}
+
+func (sp *standbyPrompts) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	if state.Phase == PhaseStandby {
+		sp.n++
+	}
+	return sp.ScriptedController.ChooseAction(ctx, state, actions)
+}
+
+// TestStandbyIgnoresOtherPhases: an End Phase trigger on the field isn't a
+// Standby Phase effect, so a single upkeep effect is applied without a prompt.
+func TestStandbyIgnoresOtherPhases(t *testing.T) {
+	fired := 0
+	upkeep := &Card{
+		Name:       "Upkeep",
+		CardType:   CardTypeProgram,
+		ProgramSub: ProgramContinuous,
+		Effects: []*CardEffect{{
+			Name:       "Upkeep",
+			ExecSpeed:  ExecSpeed1,
+			EffectType: EffectContinuous,
+			OnStandby: func(d *Duel, card *CardInstance, player int) {
+				fired++
+			},
+		}},
+	}
+	deck0 := makePaddedDeck([]*Card{upkeep, SolarFlareSerpent()}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+
+	p0 := &standbyPrompts{ScriptedController: NewScriptedController(t, "P1")}
+	p0.AddAction(ActionActivate, "Upkeep")
+	p0.AddAction(ActionNormalSummon, "Solar Flare Serpent")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 3}
+	runDuel(t, cfg, p0, NewScriptedController(t, "P2"))
+
+	if p0.n != 0 {
+		t.Errorf("Expected no Standby Phase prompt, got %d", p0.n)
+	}
+	if fired != 2 {
+		t.Errorf("Expected Upkeep to apply on Turns 2 and 3, got %d", fired)
+	}
+}
+
+// phaseActionRecorder wraps a ScriptedController and records the action types
+// offered during Main Phase 2.
+type phaseActionRecorder struct {
+	*ScriptedController
+	main2 map[ActionType]bool
+}
+
+func (pr *phaseActionRecorder) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	if state.Phase == PhaseMain2 {
+		for _, a := range actions {
+			pr.main2[a.Type] = true
+		}
+	}
+	return pr.ScriptedController.ChooseAction(ctx, state, actions)
+}
+
+// TestNoSecondSummonOrBattleInMain2: after summoning in MP1 and battling, MP2
+// offers neither another normal summon nor a return to the Battle Phase.
+func TestNoSecondSummonOrBattleInMain2(t *testing.T) {
+	alpha := vanillaAgent("Alpha", 4, 1500, 1000, AttrLIGHT)
+	beta := vanillaAgent("Beta", 4, 1400, 1000, AttrDARK)
+
+	deck0 := makePaddedDeck(nil, 40)
+	deck1 := makePaddedDeck([]*Card{alpha, beta}, 40)
+
+	p0 := NewScriptedController(t, "P1")
+	p1 := &phaseActionRecorder{ScriptedController: NewScriptedController(t, "P2"), main2: map[ActionType]bool{}}
+
+	// Turn 2 (P2): Summon Alpha, attack directly, go to MP2, try to summon Beta
+	p1.AddAction(ActionNormalSummon, "Alpha")
+	p1.AddAction(ActionEnterBattlePhase, "")
+	p1.AddDirectAttack("Alpha")
+	p1.AddAction(ActionEnterMainPhase2, "")
+	p1.AddAction(ActionNormalSummon, "Beta")
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if len(p1.main2) == 0 {
+		t.Fatal("Expected P2 to reach Main Phase 2")
+	}
+	if p1.main2[ActionNormalSummon] || p1.main2[ActionNormalSet] {
+		t.Error("Expected no normal summon or set offered in Main Phase 2")
+	}
+	if p1.main2[ActionEnterBattlePhase] {
+		t.Error("Expected no Battle Phase re-entry offered in Main Phase 2")
+	}
+	if n := len(logger.EventsOfType(log.EventNormalSummon)); n != 1 {
+		t.Errorf("Expected exactly 1 normal summon, got %d", n)
+	}
+	if findAgent(duel, 1, "Beta") != nil {
+		t.Error("Expected Beta to stay in hand")
+	}
+}
+
+// fakeClock is a Clock whose time only moves when Advance is called.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	pending []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.pending = append(c.pending, fakeTimer{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward, firing every timer that has come due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	var keep []fakeTimer
+	for _, t := range c.pending {
+		if !t.at.After(c.now) {
+			t.ch <- c.now
+		} else {
+			keep = append(keep, t)
+		}
+	}
+	c.pending = keep
+}
+
+// stallingController never answers an action prompt on its own: it advances
+// the fake clock past the deadline and waits for its context to be cancelled,
+// then takes a moment to wind down.
+type stallingController struct {
+	*ScriptedController
+	clock   *fakeClock
+	advance time.Duration
+	stalls  int
+	running atomic.Bool // a call is in progress
+}
+
+func (sc *stallingController) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	sc.running.Store(true)
+	defer sc.running.Store(false)
+	sc.stalls++
+	sc.clock.Advance(sc.advance)
+	<-ctx.Done()
+	time.Sleep(20 * time.Millisecond)
+	return Action{}, ctx.Err()
+}
+
+// TestDecisionTimeoutDefault: a player who doesn't answer before the deadline
+// gets the default decision and the duel carries on.
+func TestDecisionTimeoutDefault(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p0 := &stallingController{ScriptedController: NewScriptedController(t, "P1"), clock: clock, advance: 31 * time.Second}
+	p1 := NewScriptedController(t, "P2")
+
+	cfg := DuelConfig{
+		Deck0:           makePaddedDeck(nil, 40),
+		Deck1:           makePaddedDeck(nil, 40),
+		MaxTurns:        2,
+		DecisionTimeout: 30 * time.Second,
+		Clock:           clock,
+	}
+	duel, logger := runDuel(t, cfg, p0, p1)
+
+	if p0.stalls == 0 {
+		t.Fatal("Expected P1 to be prompted")
+	}
+	timeouts := logger.EventsOfType(log.EventTimeout)
+	if len(timeouts) != p0.stalls {
+		t.Errorf("Expected %d timeout events, got %d", p0.stalls, len(timeouts))
+	}
+	for _, e := range timeouts {
+		if e.Player != 0 {
+			t.Errorf("Expected only P1 to time out, got P%d", e.Player+1)
+		}
+	}
+	if duel.State.Turn != 2 {
+		t.Errorf("Expected the duel to reach Turn 2, got %d", duel.State.Turn)
+	}
+	if p0.running.Load() {
+		t.Error("Expected the timed-out call to have finished before the duel moved on")
+	}
+}
+
+// TestDefaultActionNeverConcedes: a timed-out prompt with no passive choice
+// takes its first choice rather than the concession listed last.
+func TestDefaultActionNeverConcedes(t *testing.T) {
+	actions := []Action{{Type: ActionActivate, Desc: "Apply Upkeep A"}, {Type: ActionActivate, Desc: "Apply Upkeep B"}, concedeAction(0)}
+	if a := defaultAction(actions); a.Desc != "Apply Upkeep A" {
+		t.Errorf("Expected the first choice, got %q", a.Desc)
+	}
+	actions = append(actions, Action{Type: ActionPass})
+	if a := defaultAction(actions); a.Type != ActionPass {
+		t.Errorf("Expected Pass when offered, got %v", a.Type)
+	}
+}
+
+// TestReplacementDestinationToHand: a destroyed card whose replacement effect
+// names the hand goes there instead of the scrapheap, leaving the field once
+// and forgetting this turn's attacks and battles.
+func TestReplacementDestinationToHand(t *testing.T) {
+	gs := NewGameState()
+	gs.Turn = 1
+	gs.Phase = PhaseMain1
+
+	d := &Duel{
+		State:       gs,
+		Controllers: [2]PlayerController{NewScriptedController(t, "P1"), NewScriptedController(t, "P2")},
+		Logger:      log.NewMemoryLogger(),
+		ctx:         context.Background(),
+	}
+
+	leaves := 0
+	bouncer := vanillaAgent("Bounce Drone", 3, 1200, 800, AttrWIND)
+	bouncer.IsEffect = true
+	bouncer.Effects = []*CardEffect{{
+		Name:       "Bounce Drone Retreat",
+		EffectType: EffectContinuous,
+		ReplacementDestination: func(d *Duel, card *CardInstance) (ZoneType, bool) {
+			return ZoneHand, true
+		},
+		OnLeaveField: func(d *Duel, card *CardInstance, player int) {
+			leaves++
+		},
+	}, {
+		Name:        "Bounce Drone Boost",
+		EffectType:  EffectIgnition,
+		OncePerTurn: true,
+	}}
+	card := &CardInstance{Card: bouncer, ID: gs.NextID(), Owner: 0, Controller: 0, Face: FaceUp, Counters: map[string]int{}}
+	gs.Players[0].PlaceAgent(card, 0)
+	card.AttackedThisTurn, card.AttacksThisTurn = true, 1
+	card.LastBattled, card.LastBattledTurn = card, gs.Turn
+	markOncePerTurn(card, bouncer.Effects[1])
+
+	d.destroyByEffect(card, nil, "test")
+
+	p := gs.Players[0]
+	if p.AgentZones[0] != nil {
+		t.Error("Expected Bounce Drone to leave the field")
+	}
+	if len(p.Scrapheap) != 0 {
+		t.Errorf("Expected an empty scrapheap, got %d cards", len(p.Scrapheap))
+	}
+	if len(p.Hand) != 1 || p.Hand[0] != card || card.Zone != ZoneHand {
+		t.Error("Expected Bounce Drone in its owner's hand")
+	}
+	if leaves != 1 {
+		t.Errorf("Expected OnLeaveField to fire once, got %d", leaves)
+	}
+	if card.AttackedThisTurn || card.AttacksThisTurn != 0 || card.LastBattled != nil || usedOncePerTurn(card, bouncer.Effects[1]) {
+		t.Error("Expected Bounce Drone to come back with no attacks, battles or once-per-turn uses")
+	}
+}
+
+// TestVerifyDecks: a deck left as committed verifies, tokens on the field
+// included, as do cards set aside with it; swapping a card mid-duel fails.
+func TestVerifyDecks(t *testing.T) {
+	side := vanillaAgent("Side Card", 4, 1200, 1200, AttrDARK)
+	deck0 := makePaddedDeck([]*Card{vanillaAgent("Card A", 4, 1000, 1000, AttrLIGHT)}, 40)
+	deck1 := makePaddedDeck(nil, 40)
+	commitments := [2]string{DeckHash(append(slices.Clone(deck0), side)), DeckHash(deck1)}
+	aside := [2][]*Card{{side}}
+
+	cfg := DuelConfig{Deck0: deck0, Deck1: deck1, MaxTurns: 2}
+	duel, logger := runDuel(t, cfg, NewScriptedController(t, "P1"), NewScriptedController(t, "P2"))
+	if err := DecoyHolograms().Effects[0].Resolve(duel, nil, 0, nil); err != nil {
+		t.Fatalf("Decoy Holograms: %v", err)
+	}
+
+	if ok := duel.VerifyDecks(commitments, aside); !ok[0] || !ok[1] {
+		t.Errorf("Expected both untouched decks to verify, got %v", ok)
+	}
+
+	duel.State.Players[1].Deck[0].Card = vanillaAgent("Counterfeit", 8, 3000, 3000, AttrDARK)
+	if ok := duel.VerifyDecks(commitments, aside); !ok[0] || ok[1] {
+		t.Errorf("Expected only P2's tampered deck to fail, got %v", ok)
+	}
+	if n := len(logger.EventsOfType(log.EventDeckVerified)); n != 4 {
+		t.Errorf("Expected 4 deck-verified events, got %d", n)
+	}
+}
+
+// TestValidateDeck: the shipped decks are legal; short decks, a fourth copy
+// and unregistered cards are rejected with an error naming the problem.
+func TestValidateDeck(t *testing.T) {
+	_, deck, err := DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	if err := ValidateDeck(deck); err != nil {
+		t.Fatalf("Expected deck 1 to be legal, got %v", err)
+	}
+
+	overCopy := append(append([]*Card(nil), deck...), deck[0], deck[0], deck[0])
+
+	for _, tc := range []struct {
+		name string
+		deck []*Card
+		want string
+	}{
+		{"under-size", deck[:37], "deck has 37 cards (must have 40–60)"},
+		{"over-copy", overCopy, fmt.Sprintf("copies of %q (max 3)", deck[0].Name)},
+		{"unknown card", append(deck[1:], vanillaAgent("Bootleg Agent", 4, 1000, 1000, AttrDARK)), `unknown card "Bootleg Agent"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDeck(tc.deck)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Expected an error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+// TestBanlist: two copies of a limited card are rejected and one passes; a
+// decks file applies the banlist it names.
+func TestBanlist(t *testing.T) {
+	var names []string
+	for name := range CardRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	limited := names[0]
+
+	// 13 other cards at 3 copies each, plus copies of the limited card
+	build := func(copies int) []*Card {
+		var deck []*Card
+		for _, name := range names[1:14] {
+			for i := 0; i < 3; i++ {
+				deck = append(deck, LookupCard(name))
+			}
+		}
+		for i := 0; i < copies; i++ {
+			deck = append(deck, LookupCard(limited))
+		}
+		return deck
+	}
+	bl := Banlist{limited: 1}
+
+	if err := ValidateDeckWithBanlist(build(1), bl); err != nil {
+		t.Errorf("Expected one copy of a limited card to pass, got %v", err)
+	}
+	err := ValidateDeckWithBanlist(build(2), bl)
+	if want := fmt.Sprintf("2 copies of %q (limited to 1", limited); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected an error containing %q, got %v", want, err)
+	}
+	if err := ValidateDeckWithBanlist(build(2), nil); err != nil {
+		t.Errorf("Expected no restriction without a banlist, got %v", err)
+	}
+	if err := ValidateDeckWithBanlist(build(1), Banlist{limited: 0}); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Errorf("Expected a forbidden card rejected, got %v", err)
+	}
+
+	dir := t.TempDir()
+	decks := filepath.Join(dir, "decks.yaml")
+	if err := os.WriteFile(filepath.Join(dir, "format.yaml"), []byte(fmt.Sprintf("%q: 1\n", limited)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(decks, []byte("banlist: format.yaml\ndecks: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DeckFileBanlist(decks)
+	if err != nil || !reflect.DeepEqual(got, bl) {
+		t.Errorf("Expected the decks file's banlist %v, got %v (%v)", bl, got, err)
+	}
+}
+
+// TestAnalyzeDeck: stats for a crafted deck of three agents, a program and a trap.
+func TestAnalyzeDeck(t *testing.T) {
+	knight := vanillaAgent("Knight", 4, 1800, 1200, AttrLIGHT)
+	knight.AgentType = "Warrior"
+	cards := []*Card{knight, knight, MimicShell(), normalProgram("Boost"), FlipJammer()}
+
+	got := AnalyzeDeck(cards)
+	want := DeckStats{
+		Total:       5,
+		ByCardType:  map[string]int{"Agent": 3, "Program": 1, "Trap": 1},
+		ByAttribute: map[string]int{"LIGHT": 2, "DARK": 1},
+		ByAgentType: map[string]int{"Warrior": 2, "Construct": 1},
+		LevelCurve:  map[int]int{4: 2, 3: 1},
+		Effect:      1,
+		Vanilla:     2,
+		AvgATK:      1600,
+		AvgDEF:      float64(1200+1200+1000) / 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeDeck =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if empty := AnalyzeDeck(nil); empty.AvgATK != 0 || empty.Total != 0 {
+		t.Errorf("Expected zero stats for an empty deck, got %+v", empty)
+	}
+}
+
+// TestWinReason: the duel records why it ended alongside the free-text result.
+func TestWinReason(t *testing.T) {
+	t.Run("deckout", func(t *testing.T) {
+		filler := vanillaAgent("Filler", 1, 0, 0, AttrLIGHT)
+		var smallDeck []*Card
+		for i := 0; i < 6; i++ {
+			smallDeck = append(smallDeck, filler)
+		}
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+
+		cfg := DuelConfig{Deck0: makePaddedDeck(nil, 40), Deck1: smallDeck}
+		duel, _ := runDuel(t, cfg, p0, p1)
+
+		if duel.State.Winner != 0 || duel.State.WinReason != WinReasonDeckout {
+			t.Errorf("Expected P1 to win by deckout, got winner %d reason %q", duel.State.Winner, duel.State.WinReason)
+		}
+	})
+
+	t.Run("hp zero", func(t *testing.T) {
+		gs := NewGameState()
+		gs.Players[0].HP = 0
+		if !gs.CheckWinCondition() {
+			t.Fatal("Expected the game to end at 0 HP")
+		}
+		if gs.Winner != 1 || gs.WinReason != WinReasonHPZero {
+			t.Errorf("Expected P2 to win by HP zero, got winner %d reason %q", gs.Winner, gs.WinReason)
+		}
+	})
+
+	t.Run("turn limit", func(t *testing.T) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+
+		cfg := DuelConfig{Deck0: makePaddedDeck(nil, 40), Deck1: makePaddedDeck(nil, 40), MaxTurns: 2}
+		duel, _ := runDuel(t, cfg, p0, p1)
+
+		if duel.State.Winner != -1 || duel.State.WinReason != WinReasonTurnLimit {
+			t.Errorf("Expected a turn-limit draw, got winner %d reason %q", duel.State.Winner, duel.State.WinReason)
+		}
+	})
+}
+
+// TestConcede: conceding in a Main Phase or the Battle Phase ends the duel on
+// the spot, with the opponent winning by surrender.
+func TestConcede(t *testing.T) {
+	t.Run("main phase", func(t *testing.T) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p1.AddAction(ActionConcede, "")
+
+		cfg := DuelConfig{Deck0: makePaddedDeck(nil, 40), Deck1: makePaddedDeck(nil, 40)}
+		duel, logger := runDuel(t, cfg, p0, p1)
+
+		gs := duel.State
+		if gs.Winner != 0 || gs.WinReason != WinReasonSurrender || gs.Turn != 2 {
+			t.Errorf("Expected P1 to win by surrender on Turn 2, got winner %d reason %q turn %d", gs.Winner, gs.WinReason, gs.Turn)
+		}
+		concessions := logger.EventsOfType(log.EventConcede)
+		if len(concessions) != 1 || concessions[0].Player != 1 || concessions[0].Phase != PhaseMain1.String() {
+			t.Errorf("Expected P2 to concede in Main Phase 1, got %v", concessions)
+		}
+		for _, e := range logger.Events() {
+			if e.Turn == 2 && e.Phase == PhaseEnd.String() {
+				t.Fatalf("Expected the duel to end before the End Phase, got %q", e.Details)
+			}
+		}
+	})
+
+	t.Run("battle phase", func(t *testing.T) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddAction(ActionEnterBattlePhase, "")
+		p0.AddAction(ActionConcede, "")
+
+		cfg := DuelConfig{Deck0: makePaddedDeck(nil, 40), Deck1: makePaddedDeck(nil, 40)}
+		duel, logger := runDuel(t, cfg, p0, p1)
+
+		gs := duel.State
+		if gs.Winner != 1 || gs.WinReason != WinReasonSurrender || gs.Turn != 3 {
+			t.Errorf("Expected P2 to win by surrender on Turn 3, got winner %d reason %q turn %d", gs.Winner, gs.WinReason, gs.Turn)
+		}
+		if e := logger.EventsOfType(log.EventConcede); len(e) != 1 || e[0].Phase != PhaseBattle.String() {
+			t.Errorf("Expected P1 to concede in the Battle Phase, got %v", e)
+		}
+	})
+}
+
+// TestSeedReproducesEventLog: the same seed, decks and controller choices give
+// byte-identical event logs, shuffles included; a zero seed picks one and reports it.
+func TestSeedReproducesEventLog(t *testing.T) {
+	_, deck, err := DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	play := func(seed int64) (*Duel, string) {
+		logger := log.NewMemoryLogger()
+		cfg := DuelConfig{Deck0: deck, Deck1: deck, Seed: seed, MaxTurns: 4, Logger: logger}
+		duel := NewDuel(cfg, NewScriptedController(t, "P1"), NewScriptedController(t, "P2"))
+		if _, err := duel.Run(context.Background()); err != nil {
+			t.Fatalf("Duel error: %v", err)
+		}
+		return duel, log.FormatAll(logger.Events())
+	}
+
+	duel, first := play(42)
+	if duel.Seed() != 42 {
+		t.Errorf("Expected seed 42, got %d", duel.Seed())
+	}
+	if _, again := play(42); again != first {
+		t.Error("Expected seed 42 to reproduce the same event log")
+	}
+	if _, other := play(43); other == first {
+		t.Error("Expected a different seed to shuffle differently")
+	}
+
+	if duel := NewDuel(DuelConfig{Deck0: deck, Deck1: deck}, NewScriptedController(t, "P1"), NewScriptedController(t, "P2")); duel.Seed() == 0 {
+		t.Error("Expected a zero seed to be replaced by the seed in effect")
+	}
+}
+
+// TestDiscardRandomUsesDuelRNG: random discards draw from the seeded duel RNG,
+// and DeterministicRandom pins them to the first card in hand.
+func TestDiscardRandomUsesDuelRNG(t *testing.T) {
+	var deck []*Card
+	for i := 0; i < 5; i++ {
+		deck = append(deck, vanillaAgent(fmt.Sprintf("Card %d", i), 4, 1000, 1000, AttrDARK))
+	}
+	// discard deals P2 a 5-card hand and returns the name of the card
+	// discarded alongside the name at the index the seed should pick.
+	discard := func(cfg DuelConfig) (got, want string) {
+		cfg.Deck0, cfg.Deck1, cfg.NoShuffle = deck, deck, true
+		duel := NewDuel(cfg, NewRandomController(1), NewRandomController(2))
+		p := duel.State.Players[1]
+		for i := 0; i < 5; i++ {
+			p.DrawCard()
+		}
+		want = p.Hand[rand.New(rand.NewSource(duel.Seed())).Intn(5)].Card.Name
+		c := duel.discardRandom(1, "test")
+		if c == nil || len(p.Hand) != 4 || len(p.Scrapheap) != 1 {
+			t.Fatalf("Expected 1 card moved from hand to scrapheap, got hand %d scrapheap %d", len(p.Hand), len(p.Scrapheap))
+		}
+		return c.Card.Name, want
+	}
+
+	picked := make(map[string]bool)
+	for seed := int64(1); seed <= 20; seed++ {
+		got, want := discard(DuelConfig{Seed: seed})
+		if got != want {
+			t.Errorf("Seed %d: expected %s discarded, got %s", seed, want, got)
+		}
+		picked[got] = true
+	}
+	if len(picked) < 2 {
+		t.Errorf("Expected different seeds to discard different cards, got %v", picked)
+	}
+
+	if got, _ := discard(DuelConfig{Seed: 7, DeterministicRandom: true}); got != "Card 4" {
+		t.Errorf("Expected DeterministicRandom to discard the first card in hand (Card 4), got %s", got)
+	}
+
+	empty := NewDuel(DuelConfig{Deck0: deck, Deck1: deck}, NewRandomController(1), NewRandomController(2))
+	if c := empty.discardRandom(0, "test"); c != nil {
+		t.Errorf("Expected nil from an empty hand, got %s", c.Card.Name)
+	}
+}
+
+// saveAtBattle plays as its PlayerController until the first Battle Phase
+// prompt from Turn 3 on, where it saves the duel and stops it without asking.
+type saveAtBattle struct {
+	PlayerController
+	duel *Duel
+	save *bytes.Buffer
+}
+
+var errSaved = errors.New("saved")
+
+func (s *saveAtBattle) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	if state.Phase == PhaseBattle && state.Turn >= 3 {
+		if err := s.duel.SaveTo(s.save); err != nil {
+			return Action{}, err
+		}
+		return Action{}, errSaved
+	}
+	return s.PlayerController.ChooseAction(ctx, state, actions)
+}
+
+// TestSaveAndLoadDuel: a duel saved mid-Battle Phase and loaded again plays on
+// to the same outcome, with the same events as the uninterrupted duel.
+func TestSaveAndLoadDuel(t *testing.T) {
+	_, deck, err := DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	cfg := func(logger log.EventLogger) DuelConfig {
+		return DuelConfig{Deck0: deck, Deck1: deck, Seed: 7, MaxTurns: 30, Logger: logger}
+	}
+
+	whole := log.NewMemoryLogger()
+	want := NewDuel(cfg(whole), NewRandomController(1), NewRandomController(2))
+	if _, err := want.Run(context.Background()); err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+
+	// The loaded duel replays the turn so far without asking the players, so
+	// controllers picked up from the saved duel carry on where they were.
+	before := log.NewMemoryLogger()
+	p0, p1 := NewRandomController(1), NewRandomController(2)
+	save := &bytes.Buffer{}
+	s0 := &saveAtBattle{PlayerController: p0, save: save}
+	s1 := &saveAtBattle{PlayerController: p1, save: save}
+	stopped := NewDuel(cfg(before), s0, s1)
+	s0.duel, s1.duel = stopped, stopped
+	if _, err := stopped.Run(context.Background()); !errors.Is(err, errSaved) {
+		t.Fatalf("Expected the duel to stop after saving, got %v", err)
+	}
+
+	duel, err := LoadDuel(save, p0, p1)
+	if err != nil {
+		t.Fatalf("LoadDuel: %v", err)
+	}
+	if duel.State.Turn != stopped.State.Turn-1 {
+		t.Errorf("Expected the loaded duel to resume at the start of Turn %d, got state after Turn %d", stopped.State.Turn, duel.State.Turn)
+	}
+	after := log.NewMemoryLogger()
+	duel.Logger = after
+	if _, err := duel.Run(context.Background()); err != nil {
+		t.Fatalf("Resumed duel error: %v", err)
+	}
+
+	gs, ws := duel.State, want.State
+	if gs.Winner != ws.Winner || gs.Result != ws.Result || gs.Turn != ws.Turn ||
+		gs.Players[0].HP != ws.Players[0].HP || gs.Players[1].HP != ws.Players[1].HP {
+		t.Errorf("Expected the resumed duel to end as %q on Turn %d (HP %d/%d), got %q on Turn %d (HP %d/%d)",
+			ws.Result, ws.Turn, ws.Players[0].HP, ws.Players[1].HP, gs.Result, gs.Turn, gs.Players[0].HP, gs.Players[1].HP)
+	}
+	resumed := append(before.Events(), after.Events()...)
+	if log.FormatAll(resumed) != log.FormatAll(whole.Events()) {
+		t.Error("Expected the saved and resumed events to match the uninterrupted duel")
+	}
+
+	if _, err := LoadDuel(bytes.NewBufferString(`{"turn_start":{"cards":[{"id":1,"name":"No Such Card"}]}}`), p0, p1); err == nil {
+		t.Error("Expected loading an unknown card to fail")
+	}
+}
+
+// TestSaveAndLoadTokens: tokens on the field survive a save and load.
+func TestSaveAndLoadTokens(t *testing.T) {
+	var deck []*Card
+	for i := 0; i < 10; i++ {
+		deck = append(deck, PrismaticDatafish())
+	}
+	duel := NewDuel(DuelConfig{Deck0: deck, Deck1: deck}, NewRandomController(1), NewRandomController(2))
+	if err := DecoyHolograms().Effects[0].Resolve(duel, nil, 0, nil); err != nil {
+		t.Fatalf("Decoy Holograms: %v", err)
+	}
+
+	data, err := json.Marshal(duel.State)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	var loaded GameState
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	tokens := loaded.Players[0].Agents()
+	if len(tokens) != 4 {
+		t.Fatalf("Expected 4 Holo-Decoy Tokens after loading, got %d", len(tokens))
+	}
+	for _, tok := range tokens {
+		if tok.Card.Name != "Holo-Decoy Token" || !tok.Card.IsToken || tok.Position != PositionDEF {
+			t.Errorf("Expected a Holo-Decoy Token in DEF Position, got %s in %v", tok.Card.Name, tok.Position)
+		}
+	}
+}
+
+// TestReplayDuel: a recorded duel, round-tripped through JSON, replays to the
+// same event sequence.
+func TestReplayDuel(t *testing.T) {
+	_, deck, err := DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	recorder := NewReplayRecorder()
+	logger := log.NewMemoryLogger()
+	cfg := DuelConfig{Deck0: deck, Deck1: deck, Seed: 11, MaxTurns: 30, Logger: logger, Recorder: recorder}
+	if _, err := NewDuel(cfg, NewRandomController(1), NewRandomController(2)).Run(context.Background()); err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+
+	data, err := json.Marshal(recorder.Record())
+	if err != nil {
+		t.Fatalf("marshal record: %v", err)
+	}
+	var record ReplayRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("unmarshal record: %v", err)
+	}
+	if len(record.Decisions) == 0 {
+		t.Fatal("Expected the record to hold the duel's decisions")
+	}
+
+	replayed, err := ReplayDuel(record)
+	if err != nil {
+		t.Fatalf("ReplayDuel: %v", err)
+	}
+	want, got := logger.Events(), replayed.Events()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d replayed events, got %d", len(want), len(got))
+	}
+	for i := range want {
+		w, g := want[i], got[i]
+		if g.Seq != w.Seq || g.Type != w.Type || g.Card != w.Card || g.Details != w.Details {
+			t.Fatalf("Event %d: expected %d %v %q %q, got %d %v %q %q", i, w.Seq, w.Type, w.Card, w.Details, g.Seq, g.Type, g.Card, g.Details)
+		}
+	}
+
+	record.Decisions = record.Decisions[:len(record.Decisions)/2]
+	if _, err := ReplayDuel(record); !errors.Is(err, errReplayExhausted) {
+		t.Errorf("Expected a truncated record to run out of decisions, got %v", err)
+	}
+}
+
+// rewindOnce plays randomly and rewinds once, on its second action prompt of
+// turn, after a decision that turn.
+type rewindOnce struct {
+	*RandomController
+	turn    int
+	asked   int
+	rewound bool
+}
+
+func (ro *rewindOnce) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	if state.Turn == ro.turn && !ro.rewound {
+		if ro.asked++; ro.asked == 2 {
+			ro.rewound = true
+			return Action{}, ErrRewindTurn
+		}
+	}
+	return ro.RandomController.ChooseAction(ctx, state, actions)
+}
+
+// TestReplayRewoundDuel: a rewound turn's first attempt is left out of the
+// record, so the replay plays the turn as it was finally played.
+func TestReplayRewoundDuel(t *testing.T) {
+	_, deck, err := DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	recorder := NewReplayRecorder()
+	logger := log.NewMemoryLogger()
+	p0 := &rewindOnce{RandomController: NewRandomController(1), turn: 5}
+	cfg := DuelConfig{Deck0: deck, Deck1: deck, Seed: 11, MaxTurns: 8, Logger: logger, Recorder: recorder, DebugRewind: true}
+	if _, err := NewDuel(cfg, p0, NewRandomController(2)).Run(context.Background()); err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+	if !p0.rewound {
+		t.Fatal("Expected the controller to request a rewind")
+	}
+	record := recorder.Record()
+	if !record.DebugRewind {
+		t.Error("Expected the record to keep DebugRewind")
+	}
+
+	replayed, err := ReplayDuel(record)
+	if err != nil {
+		t.Fatalf("ReplayDuel: %v", err)
+	}
+
+	// From Turn 5 on, the replay matches the duel after its rewind
+	describe := func(events []log.GameEvent) []string {
+		var out []string
+		for _, e := range events {
+			out = append(out, fmt.Sprintf("%v %s", e.Type, e.Details))
+		}
+		return out
+	}
+	var want, got []log.GameEvent
+	events := logger.Events()
+	for i, e := range events {
+		if e.Type == log.EventRewind {
+			want = events[i+1:]
+		}
+	}
+	for i, e := range replayed.Events() {
+		if e.Turn == 5 {
+			got = replayed.Events()[i:]
+			break
+		}
+	}
+	if !reflect.DeepEqual(describe(got), describe(want)) {
+		t.Errorf("Expected the replay to play Turn 5 on as it was finally played:\nwant %v\ngot  %v", describe(want), describe(got))
+	}
+}
+
+// TestCoinToss: with a fixed seed the toss is reproducible. P1 wins it and
+// chooses to go second, so P2 takes Turn 1.
+func TestCoinToss(t *testing.T) {
+	play := func() (*Duel, *log.MemoryLogger) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddYesNo(false)
+		cfg := DuelConfig{Deck0: makePaddedDeck(nil, 40), Deck1: makePaddedDeck(nil, 40), Seed: 2, CoinToss: true, MaxTurns: 1}
+		return runDuel(t, cfg, p0, p1)
+	}
+
+	duel, logger := play()
+	if toss := duel.State.CoinToss; toss == nil || *toss != (CoinToss{Winner: 0, GoFirst: false}) {
+		t.Fatalf("Expected P1 to win the toss and go second, got %+v", toss)
+	}
+	tosses := logger.EventsOfType(log.EventCoinToss)
+	if len(tosses) != 1 || tosses[0].Player != 0 {
+		t.Errorf("Expected one coin toss event won by P1, got %v", tosses)
+	}
+	turns := logger.EventsOfType(log.EventNewTurn)
+	if len(turns) != 1 || turns[0].Player != 1 {
+		t.Fatalf("Expected P2 to take Turn 1, got %v", turns)
+	}
+	if n := len(duel.State.Players[1].Hand); n != InitialHandSize+1 {
+		t.Errorf("Expected P2 to draw on Turn 1, got a hand of %d", n)
+	}
+
+	again, _ := play()
+	if *again.State.CoinToss != *duel.State.CoinToss {
+		t.Errorf("Expected the same seed to give the same toss, got %+v and %+v", *duel.State.CoinToss, *again.State.CoinToss)
+	}
+}
+
+// TestMulligan: P1's opening hand is all programs, so P1 is offered a
+// mulligan; the hand is revealed, goes to the bottom of the deck, and the new
+// hand has agents, so no second mulligan is offered.
+func TestMulligan(t *testing.T) {
+	var programs []*Card
+	for i := 0; i < InitialHandSize; i++ {
+		programs = append(programs, normalProgram(fmt.Sprintf("Dud %d", i+1)))
+	}
+
+	play := func(mulligan bool) (*Duel, *log.MemoryLogger) {
+		p0 := NewScriptedController(t, "P1")
+		p1 := NewScriptedController(t, "P2")
+		p0.AddYesNo(mulligan)
+		cfg := DuelConfig{Deck0: makePaddedDeck(programs, 40), Deck1: makePaddedDeck(nil, 40), Mulligans: 2, MaxTurns: 1}
+		return runDuel(t, cfg, p0, p1)
+	}
+
+	duel, logger := play(true)
+	mulligans := logger.EventsOfType(log.EventMulligan)
+	if len(mulligans) != 1 || mulligans[0].Player != 0 || !strings.Contains(mulligans[0].Details, "Dud 1") {
+		t.Fatalf("Expected one logged mulligan revealing P1's hand, got %v", mulligans)
+	}
+	p := duel.State.Players[0]
+	if !hasAgent(p.Hand) {
+		t.Error("Expected P1's new hand to have agents")
+	}
+	if len(p.Hand)+len(p.Deck) != 40 || p.Deck[0].Card.Name != "Dud 5" {
+		t.Errorf("Expected the old hand at the bottom of P1's deck, got %d in hand and %d in deck", len(p.Hand), len(p.Deck))
+	}
+
+	duel, logger = play(false)
+	if n := len(logger.EventsOfType(log.EventMulligan)); n != 0 {
+		t.Errorf("Expected no mulligan when declined, got %d", n)
+	}
+	if hasAgent(duel.State.Players[0].Hand[:InitialHandSize]) {
+		t.Error("Expected P1 to keep a hand of programs after declining")
+	}
+}
@@ -14,6 +14,41 @@ const (
 	EffectQuick                 // can chain during opponent's turn (SS2)
 )
 
+// ResponseWindow is where a declared Trigger offers its effect.
+type ResponseWindow int
+
+const (
+	// WindowSerialization collects the effect right after its event, together
+	// with any other simultaneous triggers (Cascade Failure).
+	WindowSerialization ResponseWindow = iota
+	// WindowResponse offers the effect as a fast action in the response window
+	// opened for its event, such as an attack declaration.
+	WindowResponse
+)
+
+// Trigger declares when an effect activates in response to a game event.
+type Trigger struct {
+	On     []log.EventType
+	Window ResponseWindow
+	// Filter optionally narrows a matching event further.
+	Filter func(d *Duel, card *CardInstance, event log.GameEvent) bool
+}
+
+// matches reports whether eventType fires the trigger for card.
+func (t *Trigger) matches(d *Duel, card *CardInstance, eventType log.EventType) bool {
+	for _, on := range t.On {
+		if on == eventType {
+			return t.Filter == nil || t.Filter(d, card, log.GameEvent{Type: eventType})
+		}
+	}
+	return false
+}
+
+// SummonEvents are the events for every kind of summon.
+var SummonEvents = []log.EventType{
+	log.EventNormalSummon, log.EventSacrificeSummon, log.EventFlipSummon, log.EventSpecialSummon,
+}
+
 // CardEffect represents a single activatable effect on a card.
 type CardEffect struct {
 	Name       string
@@ -32,6 +67,14 @@ type CardEffect struct {
 	// Resolve applies the effect when the chain link resolves.
 	Resolve func(d *Duel, card *CardInstance, player int, targets []*CardInstance) error
 
+	// HandTrap lets this effect be activated from the hand in a response
+	// window, like a quick effect, on either player's turn.
+	HandTrap bool
+
+	// OncePerTurn limits each copy of the card to activating this effect once
+	// per turn. Activations are recorded when the effect goes on the chain.
+	OncePerTurn bool
+
 	// Trigger effect fields
 	IsTrigger    bool
 	IsMandatory  bool
@@ -40,26 +83,104 @@ type CardEffect struct {
 	// TriggerFilter checks if a specific event matches this trigger.
 	TriggerFilter func(d *Duel, card *CardInstance, event log.GameEvent) bool
 
+	// Trigger declares the events this effect activates in response to, in
+	// place of IsTrigger/TriggerEvent/TriggerFilter. IsMandatory still applies.
+	Trigger *Trigger
+
 	// OnFieldEffect is called when a continuous card is face-up on the field.
 	// Used for passive/ongoing effects (e.g. continuous programs/traps).
 	OnFieldEffect func(d *Duel, card *CardInstance, player int)
 
+	// OnSummon is called when any agent is summoned while this card is face-up on
+	// the field, after the summon has been counted in AgentsSummonedThisTurn.
+	OnSummon func(d *Duel, card *CardInstance, summoned *CardInstance, player int)
+
+	// OnSpecialSummon is called when any agent is Special Summoned while this card
+	// is face-up on the field, after OnSummon (Balanced Ledger).
+	OnSpecialSummon func(d *Duel, card *CardInstance, summoned *CardInstance, player int)
+
 	// OnLeaveField is called when this card leaves the field. Used for cleanup.
 	OnLeaveField func(d *Duel, card *CardInstance, player int)
 
+	// OnUsedAsTribute is called when this card is sacrificed for a Sacrifice
+	// Summon or Set, once the sacrificing agent is in place (Sacrificial Node).
+	OnUsedAsTribute func(d *Duel, card *CardInstance, controller int)
+
+	// DrawsOrSearches marks an effect that draws cards or adds cards from the
+	// Deck to the hand, for cards that respond to it (Interference Burst).
+	DrawsOrSearches bool
+
+	// SummonLockTraps bars the opponent from activating Traps in response to
+	// this agent's Normal Summon (Stealth Glider).
+	SummonLockTraps bool
+
+	// TributeValue overrides how many sacrifices this agent counts as for
+	// forCard's Sacrifice Summon or Set. A result of 0 keeps the default of 1.
+	TributeValue func(forCard *Card) int
+
+	// OnAgentDestroyed is called on face-up Continuous Traps each time an agent
+	// is destroyed, after it has left the field (Counter-Hack). controller is the
+	// player who controlled the destroyed agent.
+	OnAgentDestroyed func(d *Duel, trap *CardInstance, destroyed *CardInstance, controller int)
+
 	// SpecialSummonCondition checks if a agent can be special summoned from hand/scrapheap.
 	SpecialSummonCondition func(d *Duel, card *CardInstance, player int) bool
 
+	// SpecialSummonFromDeck offers the SpecialSummonCondition summon while the
+	// agent is in the Deck instead of the hand.
+	SpecialSummonFromDeck bool
+
 	// ContinuousApply is called by recalculateContinuousEffects to apply field-wide
 	// stat/rule modifiers. These are stripped and reapplied whenever the board changes.
 	ContinuousApply func(d *Duel, card *CardInstance, player int)
 
+	// SetATK, when it returns ok, sets this agent's ATK to the returned value. It is
+	// applied by recalculateContinuousEffects after every ContinuousApply has run.
+	SetATK func(d *Duel, card *CardInstance, player int) (int, bool)
+
+	// LevelMod returns the change this card makes to target's Level while it is
+	// face-up on the field. Read through Duel.currentLevel.
+	LevelMod func(d *Duel, card *CardInstance, target *CardInstance) int
+
+	// BlocksScrapheapToHand returns true if player can't add cards from their
+	// scrapheap to their hand while this card is face-up on the field.
+	BlocksScrapheapToHand func(d *Duel, card *CardInstance, player int) bool
+
+	// HPCostDiscount returns how much less HP player pays for source's HP cost
+	// while this card is face-up on the field.
+	HPCostDiscount func(d *Duel, card *CardInstance, player int, source *CardInstance) int
+
 	// HasPiercing indicates this effect grants piercing battle damage.
 	HasPiercing bool
 
+	// Piercing, when set, grants piercing battle damage only while it returns
+	// true (e.g. once a counter threshold is reached).
+	Piercing func(d *Duel, card *CardInstance) bool
+
+	// Overrun returns true if attacker, on destroying an ATK position agent by
+	// battle, also deals the overkill again as follow-through damage while this
+	// card is face-up.
+	Overrun func(d *Duel, card *CardInstance, attacker *CardInstance) bool
+
 	// CanDirectAttack checks if this agent can attack directly even when opponent has agents.
 	CanDirectAttack func(d *Duel, card *CardInstance, player int) bool
 
+	// CannotDirectAttack bars this agent from attacking directly, even when the
+	// opponent controls no agents.
+	CannotDirectAttack bool
+
+	// RevealsOpponentHand keeps the controller's opponent's hand revealed to
+	// them while this card is face-up on the field (Total Surveillance).
+	RevealsOpponentHand bool
+
+	// OpponentAttackLimit caps the total attacks the controller's opponent may
+	// declare each turn, across all their agents (Single Strike Doctrine).
+	OpponentAttackLimit int
+
+	// ExtraAttacks returns how many attacks this agent may declare each Battle
+	// Phase on top of its first.
+	ExtraAttacks func(d *Duel, card *CardInstance, player int) int
+
 	// AttackRestriction returns false if the given attacker is not allowed to attack
 	// while this card's effect is active.
 	AttackRestriction func(d *Duel, attacker *CardInstance) bool
@@ -67,9 +188,47 @@ type CardEffect struct {
 	// TargetRestriction returns false if this agent cannot be targeted for an attack.
 	TargetRestriction func(d *Duel, card *CardInstance, player int) bool
 
+	// Taunt returns true if the opponent's attacks must target this agent while it
+	// is a legal attack target.
+	Taunt func(d *Duel, card *CardInstance, player int) bool
+
+	// UnaffectedBy reports whether target is unaffected by source's effects while
+	// this card is face-up on the field (e.g. immunity to opponent's traps).
+	UnaffectedBy func(d *Duel, card *CardInstance, target *CardInstance, source *CardInstance) bool
+
+	// Untargetable reports whether source's effects can't target target while
+	// this card is face-up on the field (Cloak Grid). Non-targeting effects still apply.
+	Untargetable func(d *Duel, card *CardInstance, target *CardInstance, source *CardInstance) bool
+
+	// Failsafe reports whether this face-up tech card prevents its controller's
+	// lethal damage, leaving them at 1 HP. The card is destroyed once used.
+	Failsafe func(d *Duel, card *CardInstance, player int) bool
+
+	// ReplacementDestination, when it returns ok, is where this card goes instead
+	// of the scrapheap when it would be destroyed: ZoneDeck (top), ZoneHand or
+	// ZonePurged. Returning the card's current field zone means it stays put.
+	ReplacementDestination func(d *Duel, card *CardInstance) (ZoneType, bool)
+
+	// BattleShield returns true, spending any per-turn charge, if this card stops
+	// agent from being destroyed by battle and negates the battle damage its
+	// controller would take from that battle.
+	BattleShield func(d *Duel, card *CardInstance, agent *CardInstance) bool
+
+	// NameAs, when it returns ok, is the card whose name and type this face-up
+	// agent is treated as having (Mimic Shell). See hasName.
+	NameAs func(d *Duel, card *CardInstance) (*Card, bool)
+
 	// OnBattleDamage is called when this agent deals battle damage.
 	OnBattleDamage func(d *Duel, card *CardInstance, player int)
 
+	// OnBattlePhaseEnd is called on face-up cards at the end of each Battle
+	// Phase, with gs.DestroyedByBattle still set (Spoils Protocol).
+	OnBattlePhaseEnd func(d *Duel, card *CardInstance, player int)
+
+	// OnStandby is called on face-up cards during each Standby Phase, the turn
+	// player's in the order they choose (Hostile Takeover, Evolving Construct).
+	OnStandby func(d *Duel, card *CardInstance, player int)
+
 	// OnDestroyByBattle is called when this agent destroys another agent by battle.
 	OnDestroyByBattle func(d *Duel, card *CardInstance, player int)
 
@@ -94,3 +253,27 @@ func EffectExecSpeed(card *Card) ExecSpeed {
 		return ExecSpeed1
 	}
 }
+
+// usedOncePerTurn reports whether eff is a OncePerTurn effect that card has
+// already activated this turn.
+func usedOncePerTurn(card *CardInstance, eff *CardEffect) bool {
+	return eff.OncePerTurn && card.usedOPT[card.effectIndex(eff)]
+}
+
+// markOncePerTurn records that card activated eff this turn, if it is OncePerTurn.
+func markOncePerTurn(card *CardInstance, eff *CardEffect) {
+	if !eff.OncePerTurn {
+		return
+	}
+	if card.usedOPT == nil {
+		card.usedOPT = make(map[int]bool)
+	}
+	card.usedOPT[card.effectIndex(eff)] = true
+}
+
+// canActivateEffect reports whether player can activate card's eff now: it
+// isn't a OncePerTurn effect already used this turn, and its CanActivate
+// condition, if any, holds.
+func (d *Duel) canActivateEffect(card *CardInstance, eff *CardEffect, player int) bool {
+	return !usedOncePerTurn(card, eff) && (eff.CanActivate == nil || eff.CanActivate(d, card, player))
+}
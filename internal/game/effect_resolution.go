@@ -19,18 +19,10 @@ func (d *Duel) collectTriggers(eventType log.EventType) []PendingTrigger {
 				continue // can't activate card set this turn
 			}
 			for _, eff := range card.Card.Effects {
-				if !eff.IsTrigger {
+				if !d.triggersOn(card, eff, eventType, WindowSerialization) || d.trapLocked(card, p) {
 					continue
 				}
-				// Check if trigger matches: either exact event type match or TriggerFilter
-				matches := eff.TriggerEvent == eventType
-				if !matches && eff.TriggerFilter != nil {
-					matches = eff.TriggerFilter(d, card, log.GameEvent{Type: eventType})
-				}
-				if !matches {
-					continue
-				}
-				if eff.CanActivate != nil && !eff.CanActivate(d, card, p) {
+				if !d.canActivateEffect(card, eff, p) {
 					continue
 				}
 				triggers = append(triggers, PendingTrigger{
@@ -47,17 +39,10 @@ func (d *Duel) collectTriggers(eventType log.EventType) []PendingTrigger {
 				continue
 			}
 			for _, eff := range card.Card.Effects {
-				if eff.EffectType != EffectTrigger || !eff.IsTrigger {
+				if eff.EffectType != EffectTrigger || !d.triggersOn(card, eff, eventType, WindowSerialization) {
 					continue
 				}
-				matches := eff.TriggerEvent == eventType
-				if !matches && eff.TriggerFilter != nil {
-					matches = eff.TriggerFilter(d, card, log.GameEvent{Type: eventType})
-				}
-				if !matches {
-					continue
-				}
-				if eff.CanActivate != nil && !eff.CanActivate(d, card, p) {
+				if !d.canActivateEffect(card, eff, p) {
 					continue
 				}
 				// Don't double-add if already in pending triggers
@@ -83,6 +68,30 @@ func (d *Duel) collectTriggers(eventType log.EventType) []PendingTrigger {
 	return triggers
 }
 
+// triggersOn reports whether eff fires for eventType in the given window, from
+// either its declared Trigger or the older IsTrigger/TriggerEvent/TriggerFilter fields.
+func (d *Duel) triggersOn(card *CardInstance, eff *CardEffect, eventType log.EventType, window ResponseWindow) bool {
+	if eff.Trigger != nil {
+		return eff.Trigger.Window == window && eff.Trigger.matches(d, card, eventType)
+	}
+	if window != WindowSerialization || !eff.IsTrigger {
+		return false
+	}
+	if eff.TriggerEvent == eventType {
+		return true
+	}
+	return eff.TriggerFilter != nil && eff.TriggerFilter(d, card, log.GameEvent{Type: eventType})
+}
+
+// respondsInWindow reports whether eff may be offered in the current response
+// window. Effects without a declared Trigger keep relying on CanActivate alone.
+func (d *Duel) respondsInWindow(card *CardInstance, eff *CardEffect) bool {
+	if eff.Trigger == nil {
+		return true
+	}
+	return d.triggersOn(card, eff, d.State.ResponseEvent, WindowResponse)
+}
+
 // processEffectSerialization handles simultaneous effect serialization after a game action.
 // It collects trigger effects, orders them (TP mandatory → NTP mandatory → TP optional → NTP optional),
 // builds a chain, opens response window, and resolves.
@@ -184,9 +193,12 @@ func (d *Duel) processEffectSerialization(eventType log.EventType) error {
 	// Open response window for other players to chain
 	// Give priority to opponent of the last chain link's controller
 	lastController := chainTriggers[len(chainTriggers)-1].Controller
-	if err := d.openResponseWindow(gs.Opponent(lastController)); err != nil {
+	if err := d.openResponseWindow(gs.Opponent(lastController), eventType); err != nil {
 		return err
 	}
+	// A summon's trap lock (Stealth Glider) ends with its response window,
+	// and doesn't reach the windows the chain opens as it resolves
+	gs.SuppressTrapResponse = false
 
 	// Resolve the chain
 	return d.resolveChain()
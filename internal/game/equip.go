@@ -34,6 +34,9 @@ func (d *Duel) detachEquip(equip *CardInstance) {
 // triggerOnLeaveField calls OnLeaveField handlers for a card about to leave the field.
 // Must be called before detachEquip/RemoveAgent/RemoveFromTech so that EquippedTo is still set.
 func (d *Duel) triggerOnLeaveField(card *CardInstance) {
+	if card.Card.CardType != CardTypeAgent && d.activationPending(card) {
+		return // its effect never applied
+	}
 	for _, eff := range card.Card.Effects {
 		if eff.OnLeaveField != nil {
 			eff.OnLeaveField(d, card, card.Controller)
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"context"
+	"fmt"
+)
+
+// Match plays a series of duels between two entrants, for simulation and
+// balance testing. Entrant i plays Decks[i] with a controller from Controller.
+type Match struct {
+	Decks [2][]*Card
+
+	// Mirror gives both entrants Decks[0].
+	Mirror bool
+
+	// SwapSeats alternates which entrant sits as player 0 (and goes first)
+	// each game; otherwise entrant 0 always goes first.
+	SwapSeats bool
+
+	// Seed is the base seed: game i is played with duel and controller seeds
+	// derived from Seed+i+1 (never 0, which would pick a random seed), so a
+	// match replays identically.
+	Seed     int64
+	MaxTurns int
+
+	// Controller builds the controller for an entrant in one game. It defaults
+	// to a RandomController.
+	Controller func(entrant int, seed int64) PlayerController
+}
+
+// MatchResult tallies the games of a Match.
+type MatchResult struct {
+	Games     int
+	Wins      [2]int // wins by entrant
+	FirstWins int    // games won by the player who went first
+	Draws     int
+}
+
+// FirstPlayerWinRate returns the share of decided games won by the player
+// who went first, or 0 if every game was a draw.
+func (r MatchResult) FirstPlayerWinRate() float64 {
+	decided := r.Games - r.Draws
+	if decided == 0 {
+		return 0
+	}
+	return float64(r.FirstWins) / float64(decided)
+}
+
+// Play runs games duels and tallies the results.
+func (m *Match) Play(ctx context.Context, games int) (MatchResult, error) {
+	var res MatchResult
+	newController := m.Controller
+	if newController == nil {
+		newController = func(entrant int, seed int64) PlayerController {
+			return NewRandomController(seed)
+		}
+	}
+	decks := m.Decks
+	if m.Mirror {
+		decks[1] = decks[0]
+	}
+
+	for i := 0; i < games; i++ {
+		seed := m.Seed + int64(i) + 1
+		// seats[p] is the entrant sitting as player p
+		seats := [2]int{0, 1}
+		if m.SwapSeats && i%2 == 1 {
+			seats = [2]int{1, 0}
+		}
+		var ctrls [2]PlayerController
+		var cfg DuelConfig
+		for p, entrant := range seats {
+			// Offset controller seeds so the two players don't mirror each other's choices
+			ctrls[p] = newController(entrant, seed*2+int64(entrant))
+		}
+		cfg.Deck0, cfg.Deck1 = decks[seats[0]], decks[seats[1]]
+		cfg.Seed = seed
+		cfg.MaxTurns = m.MaxTurns
+
+		winner, err := NewDuel(cfg, ctrls[0], ctrls[1]).Run(ctx)
+		if err != nil {
+			return res, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		res.Games++
+		if winner < 0 {
+			res.Draws++
+			continue
+		}
+		res.Wins[seats[winner]]++
+		if winner == 0 {
+			res.FirstWins++
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+// playMirror plays games seat-swapped mirror duels of deck between random controllers.
+func playMirror(t *testing.T, deck []*Card, games int, seed int64) MatchResult {
+	t.Helper()
+	m := &Match{
+		Decks:     [2][]*Card{deck},
+		Mirror:    true,
+		SwapSeats: true,
+		Seed:      seed,
+		MaxTurns:  200,
+	}
+	res, err := m.Play(context.Background(), games)
+	if err != nil {
+		t.Fatalf("Match error: %v", err)
+	}
+	return res
+}
+
+// TestMirrorMatchFirstPlayerWinRate plays a small seeded mirror sample and
+// reports how often the player going first wins.
+func TestMirrorMatchFirstPlayerWinRate(t *testing.T) {
+	_, deck, err := DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+
+	res := playMirror(t, deck, 10, 7)
+	if res.Games != 10 {
+		t.Fatalf("Expected 10 games, got %d", res.Games)
+	}
+	if got := res.Wins[0] + res.Wins[1] + res.Draws; got != res.Games {
+		t.Errorf("Expected wins and draws to add up to %d, got %d", res.Games, got)
+	}
+	if res.FirstWins > res.Games-res.Draws {
+		t.Errorf("Expected at most %d first-player wins, got %d", res.Games-res.Draws, res.FirstWins)
+	}
+	t.Logf("Mirror sample: %d games, entrant wins %v, %d draws, first-player win rate %.2f",
+		res.Games, res.Wins, res.Draws, res.FirstPlayerWinRate())
+
+	if again := playMirror(t, deck, 10, 7); again != res {
+		t.Errorf("Expected the same seed to replay the same results, got %+v then %+v", res, again)
+	}
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// RandomController is a PlayerController that picks uniformly among the legal
+// choices. Seeded, it plays the same way every time; it is meant for
+// simulations and balance testing, not as an opponent.
+type RandomController struct {
+	rng *rand.Rand
+}
+
+// NewRandomController creates a random controller seeded with seed.
+func NewRandomController(seed int64) *RandomController {
+	return &RandomController{rng: rand.New(rand.NewSource(seed))}
+}
+
+// ChooseAction implements PlayerController. It never concedes.
+func (rc *RandomController) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	var legal []Action
+	for _, a := range actions {
+		if a.Type != ActionConcede {
+			legal = append(legal, a)
+		}
+	}
+	if len(legal) == 0 {
+		legal = actions
+	}
+	return legal[rc.rng.Intn(len(legal))], nil
+}
+
+// ChooseCards implements PlayerController.
+func (rc *RandomController) ChooseCards(ctx context.Context, state *GameState, prompt string, candidates []*CardInstance, min, max int) ([]*CardInstance, error) {
+	if max > len(candidates) {
+		max = len(candidates)
+	}
+	if min > max {
+		min = max
+	}
+	n := min + rc.rng.Intn(max-min+1)
+	chosen := make([]*CardInstance, 0, n)
+	for _, i := range rc.rng.Perm(len(candidates))[:n] {
+		chosen = append(chosen, candidates[i])
+	}
+	return chosen, nil
+}
+
+// ChooseYesNo implements PlayerController.
+func (rc *RandomController) ChooseYesNo(ctx context.Context, state *GameState, prompt string) (bool, error) {
+	return rc.rng.Intn(2) == 0, nil
+}
+
+// Notify implements PlayerController.
+func (rc *RandomController) Notify(ctx context.Context, event log.GameEvent) error {
+	return nil
+}
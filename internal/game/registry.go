@@ -73,6 +73,64 @@ var CardRegistry = map[string]func() *Card{
 	"Ultimate Street Punk":              UltimateStreetPunk,
 	"Junkyard Lurker":                   JunkyardLurker,
 	"Scorched Circuit Despot":           ScorchedCircuitDespot,
+	"Polarity Invert":                   PolarityInvert,
+	"Mimic Frame":                       MimicFrame,
+	"Thorn Response":                    ThornResponse,
+	"Trap Immunity Field":               TrapImmunityField,
+	"Echo Caller":                       EchoCaller,
+	"Entropy Field":                     EntropyField,
+	"Full Rollback":                     FullRollback,
+	"Aggro Beacon":                      AggroBeacon,
+	"Data Trade":                        DataTrade,
+	"Scaling Matrix":                    ScalingMatrix,
+	"Failsafe Circuit":                  FailsafeCircuit,
+	"Forced Disclosure":                 ForcedDisclosure,
+	"Grid Link":                         GridLink,
+	"Overrun Protocol":                  OverrunProtocol,
+	"Optimizer Sprite":                  OptimizerSprite,
+	"Scrapheap Firewall":                ScrapheapFirewall,
+	"Hot Swap":                          HotSwap,
+	"Summon Tax":                        SummonTax,
+	"Total Lockout":                     TotalLockout,
+	"Phoenix Process":                   PhoenixProcess,
+	"Recalibrate":                       Recalibrate,
+	"Emergency Shields":                 EmergencyShields,
+	"Data Overload":                     DataOverload,
+	"Evolving Construct":                EvolvingConstruct,
+	"Feedback Loop":                     FeedbackLoop,
+	"Rapid Fabricator":                  RapidFabricator,
+	"Bodyguard Splice":                  BodyguardSplice,
+	"Cloak Grid":                        CloakGrid,
+	"Med-Patch":                         MedPatch,
+	"Quantum Draw":                      QuantumDraw,
+	"Knockdown Pulse":                   KnockdownPulse,
+	"Sacrificial Node":                  SacrificialNode,
+	"Contested Dig":                     ContestedDig,
+	"Dual-Fang Unit":                    DualFangUnit,
+	"Defragment":                        Defragment,
+	"Fortress Protocol":                 FortressProtocol,
+	"Aegis Pulse":                       AegisPulse,
+	"Scout Relay":                       ScoutRelay,
+	"Crossed Wires":                     CrossedWires,
+	"Open Exchange":                     OpenExchange,
+	"Single Strike Doctrine":            SingleStrikeDoctrine,
+	"Full Disclosure Draw":              FullDisclosureDraw,
+	"Exposure Field":                    ExposureField,
+	"Balanced Ledger":                   BalancedLedger,
+	"Flip Jammer":                       FlipJammer,
+	"Life Siphon Array":                 LifeSiphonArray,
+	"Mimic Shell":                       MimicShell,
+	"Total Surveillance":                TotalSurveillance,
+	"Time Dilation Core":                TimeDilationCore,
+	"Spoils Protocol":                   SpoilsProtocol,
+	"Interference Burst":                InterferenceBurst,
+	"Decay Protocol":                    DecayProtocol,
+}
+
+// TokenRegistry maps the names of tokens that effects create to their
+// constructors. Tokens can't be put in a deck, so they aren't in CardRegistry.
+var TokenRegistry = map[string]func() *Card{
+	"Holo-Decoy Token": HoloDecoyToken,
 }
 
 // LookupCard looks up a card by name and returns a new instance.
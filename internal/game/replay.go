@@ -0,0 +1,135 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// ReplayRecord is everything needed to play a duel again: its setup and every
+// decision made, in order. It marshals to JSON as is.
+type ReplayRecord struct {
+	Seed                int64      `json:"seed"`
+	Deck0               []string   `json:"deck0"` // card names, bottom of the Deck first
+	Deck1               []string   `json:"deck1"`
+	NoShuffle           bool       `json:"no_shuffle,omitempty"`
+	MaxTurns            int        `json:"max_turns"`
+	MaxHP               int        `json:"max_hp,omitempty"`
+	FirstPlayer         int        `json:"first_player,omitempty"`
+	CoinToss            bool       `json:"coin_toss,omitempty"`
+	Mulligans           int        `json:"mulligans,omitempty"`
+	DebugRewind         bool       `json:"debug_rewind,omitempty"`
+	DeterministicRandom bool       `json:"deterministic_random,omitempty"`
+	Decisions           []Decision `json:"decisions"`
+}
+
+// ReplayRecorder captures a ReplayRecord as a duel is played. Set it as
+// DuelConfig.Recorder.
+type ReplayRecorder struct {
+	record ReplayRecord
+}
+
+// NewReplayRecorder creates an empty recorder.
+func NewReplayRecorder() *ReplayRecorder {
+	return &ReplayRecorder{}
+}
+
+// Record returns what has been recorded so far.
+func (r *ReplayRecorder) Record() ReplayRecord {
+	return r.record
+}
+
+// start records the duel's setup.
+func (r *ReplayRecorder) start(cfg DuelConfig, seed int64, maxTurns int) {
+	names := func(deck []*Card) []string {
+		result := make([]string, len(deck))
+		for i, c := range deck {
+			result[i] = c.Name
+		}
+		return result
+	}
+	r.record = ReplayRecord{
+		Seed:                seed,
+		Deck0:               names(cfg.Deck0),
+		Deck1:               names(cfg.Deck1),
+		NoShuffle:           cfg.NoShuffle,
+		MaxTurns:            maxTurns,
+		MaxHP:               cfg.MaxHP,
+		FirstPlayer:         cfg.FirstPlayer,
+		CoinToss:            cfg.CoinToss,
+		Mulligans:           cfg.Mulligans,
+		DebugRewind:         cfg.DebugRewind,
+		DeterministicRandom: cfg.DeterministicRandom,
+	}
+}
+
+// errReplayExhausted is returned when a replayed duel asks for more decisions
+// than were recorded.
+var errReplayExhausted = errors.New("replay ran out of recorded decisions")
+
+// replayEnd answers nothing; it sits behind the replay of a finished duel.
+type replayEnd struct{}
+
+func (replayEnd) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	return Action{}, errReplayExhausted
+}
+
+func (replayEnd) ChooseCards(ctx context.Context, state *GameState, prompt string, candidates []*CardInstance, min, max int) ([]*CardInstance, error) {
+	return nil, errReplayExhausted
+}
+
+func (replayEnd) ChooseYesNo(ctx context.Context, state *GameState, prompt string) (bool, error) {
+	return false, errReplayExhausted
+}
+
+func (replayEnd) Notify(ctx context.Context, event log.GameEvent) error {
+	return nil
+}
+
+// ReplayDuel plays a recorded duel again from its seed, decklists and
+// decisions, and returns its events. Cards are rebuilt from CardRegistry.
+func ReplayDuel(record ReplayRecord) (*log.MemoryLogger, error) {
+	deck := func(names []string) ([]*Card, error) {
+		cards := make([]*Card, len(names))
+		for i, name := range names {
+			ctor, ok := CardRegistry[name]
+			if !ok {
+				return nil, fmt.Errorf("replay: unknown card %q", name)
+			}
+			cards[i] = ctor()
+		}
+		return cards, nil
+	}
+	deck0, err := deck(record.Deck0)
+	if err != nil {
+		return nil, err
+	}
+	deck1, err := deck(record.Deck1)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.NewMemoryLogger()
+	d := NewDuel(DuelConfig{
+		Deck0:               deck0,
+		Deck1:               deck1,
+		Logger:              logger,
+		Seed:                record.Seed,
+		NoShuffle:           record.NoShuffle,
+		MaxTurns:            record.MaxTurns,
+		MaxHP:               record.MaxHP,
+		FirstPlayer:         record.FirstPlayer,
+		CoinToss:            record.CoinToss,
+		Mulligans:           record.Mulligans,
+		DebugRewind:         record.DebugRewind,
+		DeterministicRandom: record.DeterministicRandom,
+	}, replayEnd{}, replayEnd{})
+	d.replay = record.Decisions
+	d.setControllers(replayEnd{}, replayEnd{})
+	if _, err := d.Run(context.Background()); err != nil {
+		return logger, err
+	}
+	return logger, nil
+}
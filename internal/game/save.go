@@ -0,0 +1,529 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// savedCard is a card instance in a save file. Its definition is rebuilt from
+// CardRegistry (TokenRegistry for tokens) by name; references to other instances are by ID (0 = none).
+type savedCard struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Owner      int        `json:"owner"`
+	Controller int        `json:"controller"`
+	Face       FaceStatus `json:"face"`
+	Position   Position   `json:"position"`
+	Zone       ZoneType   `json:"zone"`
+	ZoneIndex  int        `json:"zone_index"`
+
+	TurnPlaced              int            `json:"turn_placed"`
+	TurnControlChanged      int            `json:"turn_control_changed"`
+	AttackedThisTurn        bool           `json:"attacked_this_turn"`
+	AttacksThisTurn         int            `json:"attacks_this_turn"`
+	PositionChangedThisTurn bool           `json:"position_changed_this_turn"`
+	Counters                map[string]int `json:"counters,omitempty"`
+	UsedOncePerTurn         []int          `json:"used_once_per_turn,omitempty"`
+
+	Modifiers   []savedModifier `json:"modifiers,omitempty"`
+	OriginalATK int             `json:"original_atk"`
+	OriginalDEF int             `json:"original_def"`
+
+	StatsSwapped bool `json:"stats_swapped,omitempty"`
+
+	EquippedTo int   `json:"equipped_to,omitempty"`
+	Equips     []int `json:"equips,omitempty"`
+
+	LastBattled     int `json:"last_battled,omitempty"`
+	LastBattledTurn int `json:"last_battled_turn,omitempty"`
+}
+
+type savedModifier struct {
+	Source     int  `json:"source"`
+	ATKMod     int  `json:"atk_mod"`
+	DEFMod     int  `json:"def_mod"`
+	Permanent  bool `json:"permanent,omitempty"`
+	Continuous bool `json:"continuous,omitempty"`
+	EndOfTurn  bool `json:"end_of_turn,omitempty"`
+}
+
+type savedPlayer struct {
+	HP           int                 `json:"hp"`
+	Deck         []int               `json:"deck"`
+	Hand         []int               `json:"hand"`
+	Scrapheap    []int               `json:"scrapheap"`
+	Purged       []int               `json:"purged"`
+	AgentZones   [AgentZoneCount]int `json:"agent_zones"`
+	TechZones    [TechZoneCount]int  `json:"tech_zones"`
+	OS           int                 `json:"os"`
+	SkipNextDraw bool                `json:"skip_next_draw,omitempty"`
+}
+
+// savedLink is a chain link or pending trigger; the effect is stored as its
+// index on the card.
+type savedLink struct {
+	Card       int   `json:"card"`
+	Effect     int   `json:"effect"`
+	Controller int   `json:"controller"`
+	Targets    []int `json:"targets,omitempty"`
+	Negated    bool  `json:"negated,omitempty"`
+	Resolved   bool  `json:"resolved,omitempty"`
+}
+
+type savedState struct {
+	Cards   []savedCard    `json:"cards"`
+	Players [2]savedPlayer `json:"players"`
+	NextID  int            `json:"next_id"`
+
+	Turn       int        `json:"turn"`
+	TurnPlayer int        `json:"turn_player"`
+	Phase      Phase      `json:"phase"`
+	BattleStep BattleStep `json:"battle_step"`
+
+	NormalSummonUsed       bool    `json:"normal_summon_used"`
+	AgentsSummonedThisTurn [2]int  `json:"agents_summoned_this_turn"`
+	NoBattleDamage         [2]bool `json:"no_battle_damage"`
+	TotalAttacksDeclared   [2]int  `json:"total_attacks_declared"`
+	NoAgentSet             bool    `json:"no_agent_set,omitempty"`
+	DestroyedByBattle      [2]bool `json:"destroyed_by_battle"`
+
+	CurrentAttacker int  `json:"current_attacker,omitempty"`
+	CurrentTarget   int  `json:"current_target,omitempty"`
+	AttackNegated   bool `json:"attack_negated,omitempty"`
+
+	Chain                []savedLink   `json:"chain,omitempty"`
+	InChain              bool          `json:"in_chain,omitempty"`
+	PendingTriggers      []savedLink   `json:"pending_triggers,omitempty"`
+	LastSummonCard       int           `json:"last_summon_card,omitempty"`
+	LastSummonPlayer     int           `json:"last_summon_player,omitempty"`
+	LastBattleDestroyed  []int         `json:"last_battle_destroyed,omitempty"`
+	InResponseWindow     bool          `json:"in_response_window,omitempty"`
+	ResponseEvent        log.EventType `json:"response_event,omitempty"`
+	RevealedHands        [2]bool       `json:"revealed_hands"`
+	SuppressTrapResponse bool          `json:"suppress_trap_response,omitempty"`
+	CoinToss             *CoinToss     `json:"coin_toss,omitempty"`
+	ExtraTurnFor         int           `json:"extra_turn_for"`
+
+	Winner    int       `json:"winner"`
+	Over      bool      `json:"over"`
+	Result    string    `json:"result,omitempty"`
+	WinReason WinReason `json:"win_reason"`
+}
+
+// MarshalJSON encodes the full game state: every zone, HP, counters,
+// modifiers, turn flags and chain state. Cards are identified by registry name
+// plus instance ID.
+func (gs *GameState) MarshalJSON() ([]byte, error) {
+	s := savedState{
+		NextID:                 gs.nextID,
+		Turn:                   gs.Turn,
+		TurnPlayer:             gs.TurnPlayer,
+		Phase:                  gs.Phase,
+		BattleStep:             gs.BattleStep,
+		NormalSummonUsed:       gs.NormalSummonUsed,
+		AgentsSummonedThisTurn: gs.AgentsSummonedThisTurn,
+		NoBattleDamage:         gs.NoBattleDamage,
+		TotalAttacksDeclared:   gs.TotalAttacksDeclared,
+		NoAgentSet:             gs.NoAgentSet,
+		DestroyedByBattle:      gs.DestroyedByBattle,
+		CurrentAttacker:        cardID(gs.CurrentAttacker),
+		CurrentTarget:          cardID(gs.CurrentTarget),
+		AttackNegated:          gs.AttackNegated,
+		LastBattleDestroyed:    cardIDs(gs.LastBattleDestroyed),
+		InResponseWindow:       gs.InResponseWindow,
+		ResponseEvent:          gs.ResponseEvent,
+		RevealedHands:          gs.RevealedHands,
+		SuppressTrapResponse:   gs.SuppressTrapResponse,
+		CoinToss:               gs.CoinToss,
+		ExtraTurnFor:           gs.ExtraTurnFor,
+		Winner:                 gs.Winner,
+		Over:                   gs.Over,
+		Result:                 gs.Result,
+		WinReason:              gs.WinReason,
+	}
+
+	seen := make(map[int]bool)
+	var add func(ci *CardInstance)
+	add = func(ci *CardInstance) {
+		if ci == nil || seen[ci.ID] {
+			return
+		}
+		seen[ci.ID] = true
+		s.Cards = append(s.Cards, saveCard(ci))
+		add(ci.EquippedTo)
+		for _, e := range ci.Equips {
+			add(e)
+		}
+		add(ci.LastBattled)
+	}
+
+	for i, p := range gs.Players {
+		sp := savedPlayer{
+			HP:           p.HP,
+			Deck:         cardIDs(p.Deck),
+			Hand:         cardIDs(p.Hand),
+			Scrapheap:    cardIDs(p.Scrapheap),
+			Purged:       cardIDs(p.Purged),
+			OS:           cardID(p.OS),
+			SkipNextDraw: p.SkipNextDraw,
+		}
+		for z := range p.AgentZones {
+			sp.AgentZones[z] = cardID(p.AgentZones[z])
+		}
+		for z := range p.TechZones {
+			sp.TechZones[z] = cardID(p.TechZones[z])
+		}
+		s.Players[i] = sp
+		for _, zone := range [][]*CardInstance{p.Deck, p.Hand, p.Scrapheap, p.Purged, p.AgentZones[:], p.TechZones[:], {p.OS}} {
+			for _, c := range zone {
+				add(c)
+			}
+		}
+	}
+
+	saveLink := func(card *CardInstance, eff *CardEffect, controller int, targets []*CardInstance) (savedLink, error) {
+		idx := card.effectIndex(eff)
+		if idx < 0 {
+			return savedLink{}, fmt.Errorf("save: %s has no effect %q", card.Card.Name, eff.Name)
+		}
+		add(card)
+		for _, t := range targets {
+			add(t)
+		}
+		return savedLink{Card: card.ID, Effect: idx, Controller: controller, Targets: cardIDs(targets)}, nil
+	}
+	if gs.Chain != nil {
+		s.InChain = true
+		for _, link := range gs.Chain.Links {
+			sl, err := saveLink(link.Card, link.Effect, link.Controller, link.Targets)
+			if err != nil {
+				return nil, err
+			}
+			sl.Negated, sl.Resolved = link.Negated, link.Resolved
+			s.Chain = append(s.Chain, sl)
+		}
+	}
+	for _, pt := range gs.PendingTriggers {
+		sl, err := saveLink(pt.Card, pt.Effect, pt.Controller, nil)
+		if err != nil {
+			return nil, err
+		}
+		s.PendingTriggers = append(s.PendingTriggers, sl)
+	}
+	if gs.LastSummonEvent != nil {
+		add(gs.LastSummonEvent.Card)
+		s.LastSummonCard = cardID(gs.LastSummonEvent.Card)
+		s.LastSummonPlayer = gs.LastSummonEvent.Player
+	}
+	add(gs.CurrentAttacker)
+	add(gs.CurrentTarget)
+	for _, c := range gs.LastBattleDestroyed {
+		add(c)
+	}
+
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON restores a state written by MarshalJSON, rebuilding every card
+// from CardRegistry, or TokenRegistry for tokens. It fails on names neither
+// registry knows.
+func (gs *GameState) UnmarshalJSON(data []byte) error {
+	s := savedState{ExtraTurnFor: -1} // saves from before extra turns have none
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	cards := make(map[int]*CardInstance, len(s.Cards))
+	for _, sc := range s.Cards {
+		ctor, ok := CardRegistry[sc.Name]
+		if !ok {
+			ctor, ok = TokenRegistry[sc.Name]
+		}
+		if !ok {
+			return fmt.Errorf("load: unknown card %q", sc.Name)
+		}
+		cards[sc.ID] = loadCard(sc, ctor())
+	}
+	lookup := func(id int) (*CardInstance, error) {
+		if id == 0 {
+			return nil, nil
+		}
+		c, ok := cards[id]
+		if !ok {
+			return nil, fmt.Errorf("load: no card with ID %d", id)
+		}
+		return c, nil
+	}
+	var lookupErr error
+	get := func(id int) *CardInstance {
+		c, err := lookup(id)
+		if err != nil && lookupErr == nil {
+			lookupErr = err
+		}
+		return c
+	}
+	list := func(ids []int) []*CardInstance {
+		if ids == nil {
+			return nil
+		}
+		result := make([]*CardInstance, len(ids))
+		for i, id := range ids {
+			result[i] = get(id)
+		}
+		return result
+	}
+	for _, sc := range s.Cards {
+		c := cards[sc.ID]
+		c.EquippedTo = get(sc.EquippedTo)
+		c.Equips = list(sc.Equips)
+		c.LastBattled = get(sc.LastBattled)
+	}
+
+	*gs = GameState{
+		nextID:                 s.NextID,
+		Turn:                   s.Turn,
+		TurnPlayer:             s.TurnPlayer,
+		Phase:                  s.Phase,
+		BattleStep:             s.BattleStep,
+		NormalSummonUsed:       s.NormalSummonUsed,
+		AgentsSummonedThisTurn: s.AgentsSummonedThisTurn,
+		NoBattleDamage:         s.NoBattleDamage,
+		TotalAttacksDeclared:   s.TotalAttacksDeclared,
+		NoAgentSet:             s.NoAgentSet,
+		DestroyedByBattle:      s.DestroyedByBattle,
+		CurrentAttacker:        get(s.CurrentAttacker),
+		CurrentTarget:          get(s.CurrentTarget),
+		AttackNegated:          s.AttackNegated,
+		LastBattleDestroyed:    list(s.LastBattleDestroyed),
+		InResponseWindow:       s.InResponseWindow,
+		ResponseEvent:          s.ResponseEvent,
+		RevealedHands:          s.RevealedHands,
+		SuppressTrapResponse:   s.SuppressTrapResponse,
+		CoinToss:               s.CoinToss,
+		ExtraTurnFor:           s.ExtraTurnFor,
+		Winner:                 s.Winner,
+		Over:                   s.Over,
+		Result:                 s.Result,
+		WinReason:              s.WinReason,
+	}
+	for i, sp := range s.Players {
+		p := &Player{
+			HP:           sp.HP,
+			Deck:         list(sp.Deck),
+			Hand:         list(sp.Hand),
+			Scrapheap:    list(sp.Scrapheap),
+			Purged:       list(sp.Purged),
+			OS:           get(sp.OS),
+			SkipNextDraw: sp.SkipNextDraw,
+		}
+		for z, id := range sp.AgentZones {
+			p.AgentZones[z] = get(id)
+		}
+		for z, id := range sp.TechZones {
+			p.TechZones[z] = get(id)
+		}
+		gs.Players[i] = p
+	}
+
+	loadEffect := func(sl savedLink) (*CardInstance, *CardEffect, error) {
+		card := get(sl.Card)
+		if card == nil || sl.Effect < 0 || sl.Effect >= len(card.Card.Effects) {
+			return nil, nil, fmt.Errorf("load: bad effect %d on card %d", sl.Effect, sl.Card)
+		}
+		return card, card.Card.Effects[sl.Effect], nil
+	}
+	if s.InChain {
+		gs.Chain = &Chain{}
+		for i, sl := range s.Chain {
+			card, eff, err := loadEffect(sl)
+			if err != nil {
+				return err
+			}
+			gs.Chain.Links = append(gs.Chain.Links, ChainLink{
+				Index:      i + 1,
+				Card:       card,
+				Effect:     eff,
+				Controller: sl.Controller,
+				Targets:    list(sl.Targets),
+				Negated:    sl.Negated,
+				Resolved:   sl.Resolved,
+			})
+		}
+	}
+	for _, sl := range s.PendingTriggers {
+		card, eff, err := loadEffect(sl)
+		if err != nil {
+			return err
+		}
+		gs.PendingTriggers = append(gs.PendingTriggers, PendingTrigger{Card: card, Effect: eff, Controller: sl.Controller})
+	}
+	if s.LastSummonCard != 0 {
+		gs.LastSummonEvent = &SummonEventInfo{Card: get(s.LastSummonCard), Player: s.LastSummonPlayer}
+	}
+	return lookupErr
+}
+
+func saveCard(ci *CardInstance) savedCard {
+	sc := savedCard{
+		ID:                      ci.ID,
+		Name:                    ci.Card.Name,
+		Owner:                   ci.Owner,
+		Controller:              ci.Controller,
+		Face:                    ci.Face,
+		Position:                ci.Position,
+		Zone:                    ci.Zone,
+		ZoneIndex:               ci.ZoneIndex,
+		TurnPlaced:              ci.TurnPlaced,
+		TurnControlChanged:      ci.TurnControlChanged,
+		AttackedThisTurn:        ci.AttackedThisTurn,
+		AttacksThisTurn:         ci.AttacksThisTurn,
+		PositionChangedThisTurn: ci.PositionChangedThisTurn,
+		OriginalATK:             ci.OriginalATK,
+		OriginalDEF:             ci.OriginalDEF,
+		StatsSwapped:            ci.StatsSwapped,
+		EquippedTo:              cardID(ci.EquippedTo),
+		Equips:                  cardIDs(ci.Equips),
+		LastBattled:             cardID(ci.LastBattled),
+		LastBattledTurn:         ci.LastBattledTurn,
+	}
+	if len(ci.Counters) > 0 {
+		sc.Counters = ci.Counters
+	}
+	for i := range ci.Card.Effects {
+		if ci.usedOPT[i] {
+			sc.UsedOncePerTurn = append(sc.UsedOncePerTurn, i)
+		}
+	}
+	for _, m := range ci.Modifiers {
+		sc.Modifiers = append(sc.Modifiers, savedModifier(m))
+	}
+	return sc
+}
+
+func loadCard(sc savedCard, card *Card) *CardInstance {
+	ci := &CardInstance{
+		Card:                    card,
+		ID:                      sc.ID,
+		Owner:                   sc.Owner,
+		Controller:              sc.Controller,
+		Face:                    sc.Face,
+		Position:                sc.Position,
+		Zone:                    sc.Zone,
+		ZoneIndex:               sc.ZoneIndex,
+		TurnPlaced:              sc.TurnPlaced,
+		TurnControlChanged:      sc.TurnControlChanged,
+		AttackedThisTurn:        sc.AttackedThisTurn,
+		AttacksThisTurn:         sc.AttacksThisTurn,
+		PositionChangedThisTurn: sc.PositionChangedThisTurn,
+		Counters:                make(map[string]int, len(sc.Counters)),
+		OriginalATK:             sc.OriginalATK,
+		OriginalDEF:             sc.OriginalDEF,
+		StatsSwapped:            sc.StatsSwapped,
+		LastBattledTurn:         sc.LastBattledTurn,
+	}
+	for k, v := range sc.Counters {
+		ci.Counters[k] = v
+	}
+	if len(sc.UsedOncePerTurn) > 0 {
+		ci.usedOPT = make(map[int]bool, len(sc.UsedOncePerTurn))
+		for _, i := range sc.UsedOncePerTurn {
+			ci.usedOPT[i] = true
+		}
+	}
+	for _, m := range sc.Modifiers {
+		ci.Modifiers = append(ci.Modifiers, StatModifier(m))
+	}
+	return ci
+}
+
+// cardIDs maps card instances to their IDs, preserving nil.
+func cardIDs(cards []*CardInstance) []int {
+	if cards == nil {
+		return nil
+	}
+	ids := make([]int, len(cards))
+	for i, c := range cards {
+		ids[i] = cardID(c)
+	}
+	return ids
+}
+
+// savedDuel is the save file written by Duel.SaveTo. A duel resumes from the
+// start of the current turn and replays the turn's decisions to get back to
+// the one that was pending; State is the state at save time, for inspection.
+type savedDuel struct {
+	Seed                int64         `json:"seed"`
+	RNGCalls            uint64        `json:"rng_calls"` // RNG position at the start of the turn
+	MaxTurns            int           `json:"max_turns"`
+	MaxHP               int           `json:"max_hp,omitempty"`
+	DeterministicRandom bool          `json:"deterministic_random,omitempty"`
+	DebugRewind         bool          `json:"debug_rewind,omitempty"`
+	DecisionTimeout     time.Duration `json:"decision_timeout,omitempty"`
+
+	TurnStart *GameState `json:"turn_start"`
+	Decisions []Decision `json:"decisions"`
+	State     *GameState `json:"state"`
+}
+
+// ErrNotStarted is returned by SaveTo before the duel's first turn begins.
+var ErrNotStarted = errors.New("duel has not started")
+
+// SaveTo writes the duel as JSON so LoadDuel can resume it. Call it from a
+// controller while the duel waits on that controller's decision.
+func (d *Duel) SaveTo(w io.Writer) error {
+	if d.turnStart == nil {
+		return ErrNotStarted
+	}
+	return json.NewEncoder(w).Encode(savedDuel{
+		Seed:                d.seed,
+		RNGCalls:            d.turnStartRNG,
+		MaxTurns:            d.maxTurns,
+		MaxHP:               d.maxHP,
+		DeterministicRandom: d.deterministicRandom,
+		DebugRewind:         d.debugRewind,
+		DecisionTimeout:     d.decisionTimeout,
+		TurnStart:           d.turnStart,
+		Decisions:           d.decisions,
+		State:               d.State,
+	})
+}
+
+// LoadDuel restores a duel written by SaveTo. Run then replays the saved
+// turn's decisions without logging them again and continues from the decision
+// that was pending, asking p0 and p1 from there on.
+func LoadDuel(r io.Reader, p0, p1 PlayerController) (*Duel, error) {
+	var s savedDuel
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	if s.TurnStart == nil {
+		return nil, fmt.Errorf("load: save has no turn start state")
+	}
+
+	d := &Duel{
+		State:           s.TurnStart,
+		Logger:          log.NewMemoryLogger(),
+		maxTurns:        s.MaxTurns,
+		debugRewind:     s.DebugRewind,
+		clock:           wallClock{},
+		decisionTimeout: s.DecisionTimeout,
+		seed:            s.Seed,
+		maxHP:           s.MaxHP,
+
+		deterministicRandom: s.DeterministicRandom,
+
+		resumed:    true,
+		replay:     s.Decisions,
+		catchingUp: true,
+	}
+	d.rngSrc = newCountingSource(s.Seed, s.RNGCalls)
+	d.rng = rand.New(d.rngSrc)
+	d.setControllers(p0, p1)
+	return d, nil
+}
@@ -0,0 +1,254 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// ErrRewindTurn is returned by a controller to rewind the duel to the start of
+// the current turn. Only honored when DuelConfig.DebugRewind is set.
+var ErrRewindTurn = errors.New("rewind to start of turn")
+
+// ErrUndoDecision is returned by a controller's ChooseAction to take back the
+// player's latest decision: the duel goes back to where it was asked and asks
+// it again. Check CanUndo first.
+var ErrUndoDecision = errors.New("undo last decision")
+
+// Snapshot returns a deep copy of the game state. Card instances are cloned and
+// all references between them (zones, equips, chain links, triggers) point into
+// the copy; card definitions are shared.
+func (gs *GameState) Snapshot() *GameState {
+	clones := make(map[*CardInstance]*CardInstance)
+	var clone func(ci *CardInstance) *CardInstance
+	clone = func(ci *CardInstance) *CardInstance {
+		if ci == nil {
+			return nil
+		}
+		if c, ok := clones[ci]; ok {
+			return c
+		}
+		c := *ci
+		clones[ci] = &c
+		c.Counters = make(map[string]int, len(ci.Counters))
+		for k, v := range ci.Counters {
+			c.Counters[k] = v
+		}
+		if ci.usedOPT != nil {
+			c.usedOPT = make(map[int]bool, len(ci.usedOPT))
+			for k, v := range ci.usedOPT {
+				c.usedOPT[k] = v
+			}
+		}
+		c.Modifiers = append([]StatModifier(nil), ci.Modifiers...)
+		c.EquippedTo = clone(ci.EquippedTo)
+		c.Equips = cloneCards(ci.Equips, clone)
+		c.LastBattled = clone(ci.LastBattled)
+		return &c
+	}
+
+	snap := *gs
+	for i, p := range gs.Players {
+		np := *p
+		np.Deck = cloneCards(p.Deck, clone)
+		np.Hand = cloneCards(p.Hand, clone)
+		np.Scrapheap = cloneCards(p.Scrapheap, clone)
+		np.Purged = cloneCards(p.Purged, clone)
+		for z := range p.AgentZones {
+			np.AgentZones[z] = clone(p.AgentZones[z])
+		}
+		for z := range p.TechZones {
+			np.TechZones[z] = clone(p.TechZones[z])
+		}
+		np.OS = clone(p.OS)
+		snap.Players[i] = &np
+	}
+
+	snap.CurrentAttacker = clone(gs.CurrentAttacker)
+	snap.CurrentTarget = clone(gs.CurrentTarget)
+	if gs.Chain != nil {
+		chain := &Chain{}
+		for _, link := range gs.Chain.Links {
+			link.Card = clone(link.Card)
+			link.Targets = cloneCards(link.Targets, clone)
+			chain.Links = append(chain.Links, link)
+		}
+		snap.Chain = chain
+	}
+	snap.PendingTriggers = nil
+	for _, pt := range gs.PendingTriggers {
+		pt.Card = clone(pt.Card)
+		snap.PendingTriggers = append(snap.PendingTriggers, pt)
+	}
+	snap.LastBattleDestroyed = cloneCards(gs.LastBattleDestroyed, clone)
+	if gs.LastSummonEvent != nil {
+		snap.LastSummonEvent = &SummonEventInfo{
+			Card:   clone(gs.LastSummonEvent.Card),
+			Player: gs.LastSummonEvent.Player,
+		}
+	}
+	return &snap
+}
+
+// Clone returns an independent deep copy of the game state for lookahead
+// search. It is the same copy Snapshot takes for DebugRewind.
+func (gs *GameState) Clone() *GameState {
+	return gs.Snapshot()
+}
+
+// CloneForSimulation returns a duel over a clone of the current state, driven
+// by the given controllers and logging to its own memory logger, so actions
+// can be tried without touching the live duel. Its RNG continues from the
+// live duel's position without advancing it. The clone is already set up:
+// step it with ApplyAction, or Run it to play on from the start of a turn.
+func (d *Duel) CloneForSimulation(p0, p1 PlayerController) *Duel {
+	sim := &Duel{
+		State:     d.State.Clone(),
+		Logger:    log.NewMemoryLogger(),
+		ctx:       d.ctx,
+		noShuffle: d.noShuffle,
+		maxTurns:  d.maxTurns,
+		clock:     d.clock,
+		seed:      d.seed,
+		rngSrc:    newCountingSource(d.seed, d.rngCalls()),
+		maxHP:     d.maxHP,
+		resumed:   true,
+
+		deterministicRandom: d.deterministicRandom,
+	}
+	if sim.ctx == nil {
+		sim.ctx = context.Background()
+	}
+	sim.rng = rand.New(sim.rngSrc)
+	sim.setControllers(p0, p1)
+	return sim
+}
+
+// ApplyAction carries out a Main or Battle Phase action as if the turn player
+// had chosen it, resolving any chain it starts; further decisions go to the
+// duel's controllers. The action may come from the live duel: its cards are
+// matched to this duel's copies by ID.
+func (d *Duel) ApplyAction(a Action) error {
+	gs := d.State
+	if gs.Over {
+		return fmt.Errorf("the duel is over")
+	}
+	a.Card = gs.instance(a.Card)
+	if a.Targets != nil {
+		targets := make([]*CardInstance, len(a.Targets))
+		for i, t := range a.Targets {
+			targets[i] = gs.instance(t)
+		}
+		a.Targets = targets
+	}
+	var err error
+	switch gs.Phase {
+	case PhaseMain1, PhaseMain2:
+		_, err = d.executeMainPhaseAction(a)
+	case PhaseBattle:
+		_, err = d.executeBattlePhaseAction(a)
+	default:
+		err = fmt.Errorf("no actions can be taken in the %s", gs.Phase)
+	}
+	return err
+}
+
+// instance returns the card in this state with ci's ID, or nil if there's
+// none (or ci is nil).
+func (gs *GameState) instance(ci *CardInstance) *CardInstance {
+	if ci == nil {
+		return nil
+	}
+	for _, p := range gs.Players {
+		if p.OS != nil && p.OS.ID == ci.ID {
+			return p.OS
+		}
+		for _, zone := range [][]*CardInstance{p.Deck, p.Hand, p.AgentZones[:], p.TechZones[:], p.Scrapheap, p.Purged} {
+			for _, c := range zone {
+				if c != nil && c.ID == ci.ID {
+					return c
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// cloneCards maps a slice of card instances through clone, preserving nil.
+func cloneCards(cards []*CardInstance, clone func(*CardInstance) *CardInstance) []*CardInstance {
+	if cards == nil {
+		return nil
+	}
+	result := make([]*CardInstance, len(cards))
+	for i, ci := range cards {
+		result[i] = clone(ci)
+	}
+	return result
+}
+
+// rewindTurn restores the state and RNG position captured at the start of the
+// current turn, and takes the turn's decisions out of the replay record. The
+// State pointer is kept so controllers holding it stay valid.
+func (d *Duel) rewindTurn() {
+	d.unrecordTurn()
+	*d.State = *d.turnStart.Snapshot()
+	d.rngSrc = newCountingSource(d.seed, d.turnStartRNG)
+	d.rng = rand.New(d.rngSrc)
+	d.log(log.NewRewindEvent(d.State.Turn+1, d.State.TurnPlayer))
+}
+
+// unrecordTurn drops the turn's decisions from the replay record, for a turn
+// that is about to be played again and so recorded again.
+func (d *Duel) unrecordTurn() {
+	if d.recorder != nil {
+		rec := d.recorder.record.Decisions
+		d.recorder.record.Decisions = rec[:len(rec)-len(d.decisions)]
+	}
+}
+
+// CanUndo reports why player's latest decision can't be taken back, or nil if
+// it can: it must be this turn's latest decision with a choice in it (a
+// prompt with one possible answer doesn't count), with no randomness used
+// since. Call it while the duel waits on player's controller.
+func (d *Duel) CanUndo(player int) error {
+	i := d.lastChoice()
+	if i < 0 {
+		return errors.New("no decision to undo this turn")
+	}
+	if d.decisions[i].Player != player {
+		return errors.New("your opponent has decided since your last decision")
+	}
+	if d.decisions[i].rngCalls != d.rngCalls() {
+		return errors.New("randomness has been used since your last decision")
+	}
+	return nil
+}
+
+// lastChoice returns the index of the turn's latest decision that wasn't
+// forced, or -1.
+func (d *Duel) lastChoice() int {
+	for i := len(d.decisions) - 1; i >= 0; i-- {
+		if !d.decisions[i].forced {
+			return i
+		}
+	}
+	return -1
+}
+
+// undoDecision takes back the turn's latest choice: the turn restarts from its
+// snapshot and RNG position and silently replays the decisions before it.
+func (d *Duel) undoDecision() {
+	gs := d.State
+	i := d.lastChoice()
+	d.log(log.NewUndoEvent(gs.Turn, gs.Phase.String(), d.decisions[i].Player))
+
+	d.unrecordTurn()
+	d.replay = d.decisions[:i]
+	d.catchingUp = true
+	*d.State = *d.turnStart.Snapshot()
+	d.rngSrc = newCountingSource(d.seed, d.turnStartRNG)
+	d.rng = rand.New(d.rngSrc)
+}
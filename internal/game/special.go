@@ -28,7 +28,17 @@ func (d *Duel) executeSpecialSummon(card *CardInstance, player int, position Pos
 	d.log(log.NewSpecialSummonEvent(gs.Turn, gs.Phase.String(), player, card.Card.Name, card.CurrentATK(), zone))
 
 	// Store summon info for trigger effects
-	gs.LastSummonEvent = &SummonEventInfo{Card: card, Player: player}
+	d.recordSummon(card, player)
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.OnSpecialSummon != nil && !gs.Over {
+				eff.OnSpecialSummon(d, c, card, player)
+			}
+		}
+	}
+	if gs.Over {
+		return nil
+	}
 
 	d.recalculateContinuousEffects()
 
@@ -40,6 +50,21 @@ func (d *Duel) executeSpecialSummon(card *CardInstance, player int, position Pos
 	return nil
 }
 
+// recordSummon stores a just-summoned agent for trigger matching, counts it
+// toward the player's summons this turn and fires OnSummon effects (Summon Tax).
+func (d *Duel) recordSummon(card *CardInstance, player int) {
+	gs := d.State
+	gs.LastSummonEvent = &SummonEventInfo{Card: card, Player: player}
+	gs.AgentsSummonedThisTurn[player]++
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.OnSummon != nil && !gs.Over {
+				eff.OnSummon(d, c, card, player)
+			}
+		}
+	}
+}
+
 // addSpecialSummonActions adds main phase actions for agents with special summon conditions.
 // This is called from computeMainPhaseActions.
 func (d *Duel) addSpecialSummonActions(player int, actions []Action) []Action {
@@ -56,7 +81,7 @@ func (d *Duel) addSpecialSummonActions(player int, actions []Action) []Action {
 			continue
 		}
 		for ei, eff := range card.Card.Effects {
-			if eff.SpecialSummonCondition == nil {
+			if eff.SpecialSummonCondition == nil || eff.SpecialSummonFromDeck {
 				continue
 			}
 			if !eff.SpecialSummonCondition(d, card, player) {
@@ -72,20 +97,86 @@ func (d *Duel) addSpecialSummonActions(player int, actions []Action) []Action {
 		}
 	}
 
+	// Check deck for agents that special summon themselves from there (one action per name)
+	offered := make(map[string]bool)
+	for _, card := range p.Deck {
+		if card.Card.CardType != CardTypeAgent || offered[card.Card.Name] {
+			continue
+		}
+		for ei, eff := range card.Card.Effects {
+			if eff.SpecialSummonCondition == nil || !eff.SpecialSummonFromDeck {
+				continue
+			}
+			if !eff.SpecialSummonCondition(d, card, player) {
+				continue
+			}
+			offered[card.Card.Name] = true
+			actions = append(actions, Action{
+				Type:        ActionActivate,
+				Player:      player,
+				Card:        card,
+				EffectIndex: ei,
+				Desc:        fmt.Sprintf("Special Summon %s from Deck", card.Card.Name),
+			})
+		}
+	}
+
 	return actions
 }
 
 // removeFromScrapheap removes a card from a player's scrapheap by instance ID.
+// Continuous effects are recalculated since some scale with scrapheap size.
 func (d *Duel) removeFromScrapheap(player int, card *CardInstance) {
 	p := d.State.Players[player]
 	for i, c := range p.Scrapheap {
 		if c.ID == card.ID {
 			p.Scrapheap = append(p.Scrapheap[:i], p.Scrapheap[i+1:]...)
+			d.recalculateContinuousEffects()
 			return
 		}
 	}
 }
 
+// canAddFromScrapheap reports whether the player may add cards from their
+// scrapheap to their hand (Scrapheap Firewall, etc. can forbid it).
+func (d *Duel) canAddFromScrapheap(player int) bool {
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.BlocksScrapheapToHand != nil && eff.BlocksScrapheapToHand(d, c, player) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// addFromScrapheapToHand moves a card from the player's scrapheap to their hand.
+// It does nothing if the card has left the scrapheap or the move is blocked.
+func (d *Duel) addFromScrapheapToHand(player int, card *CardInstance, reason string) bool {
+	gs := d.State
+	if card.Zone != ZoneScrapheap || !d.canAddFromScrapheap(player) {
+		return false
+	}
+	d.removeFromScrapheap(player, card)
+	card.Zone = ZoneHand
+	gs.Players[player].Hand = append(gs.Players[player].Hand, card)
+	d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), player, card.Card.Name, reason))
+	return true
+}
+
+// returnFromScrapheapToDeck moves a card from the player's scrapheap into their
+// deck. The caller shuffles the deck afterwards.
+func (d *Duel) returnFromScrapheapToDeck(player int, card *CardInstance, reason string) bool {
+	gs := d.State
+	if card.Zone != ZoneScrapheap {
+		return false
+	}
+	d.removeFromScrapheap(player, card)
+	gs.Players[player].PlaceOnDeckTop(card)
+	d.log(log.NewShuffleIntoDeckEvent(gs.Turn, gs.Phase.String(), player, card.Card.Name, reason))
+	return true
+}
+
 // purgeFromScrapheap removes a card from scrapheap and moves it to purged zone.
 func (d *Duel) purgeFromScrapheap(player int, card *CardInstance, reason string) {
 	gs := d.State
@@ -117,6 +208,46 @@ func (d *Duel) purgeFromField(card *CardInstance, reason string) {
 	d.log(log.NewPurgeEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, reason))
 }
 
+// returnToHand removes an agent from the field and returns it to its owner's hand.
+// Equips are destroyed and all field state (modifiers, counters, control) is reset.
+func (d *Duel) returnToHand(card *CardInstance, reason string) {
+	gs := d.State
+
+	d.triggerOnLeaveField(card)
+	d.destroyEquips(card)
+	gs.Players[card.Controller].RemoveAgent(card)
+
+	owner := gs.Players[card.Owner]
+	resetFieldState(card)
+	card.Zone = ZoneHand
+	card.ZoneIndex = len(owner.Hand)
+	owner.Hand = append(owner.Hand, card)
+	d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, reason))
+}
+
+// resetFieldState clears everything a card picked up while on the field
+// (control, position, equips, modifiers, counters, once-per-turn uses, attacks
+// and battle history) as it leaves for a hidden zone, so it comes back as a
+// new card.
+func resetFieldState(card *CardInstance) {
+	card.Controller = card.Owner
+	card.Face = FaceDown
+	card.Position = PositionATK
+	card.EquippedTo = nil
+	card.Equips = nil
+	card.Modifiers = nil
+	card.Counters = make(map[string]int)
+	card.OriginalATK = 0
+	card.OriginalDEF = 0
+	card.StatsSwapped = false
+	card.usedOPT = nil
+	card.AttackedThisTurn = false
+	card.AttacksThisTurn = 0
+	card.PositionChangedThisTurn = false
+	card.LastBattled = nil
+	card.LastBattledTurn = 0
+}
+
 // changeControl moves a agent from one player's field to another's.
 func (d *Duel) changeControl(card *CardInstance, newController int) error {
 	gs := d.State
@@ -143,3 +274,42 @@ func (d *Duel) changeControl(card *CardInstance, newController int) error {
 
 	return nil
 }
+
+// changeControlUntilEndPhase moves an agent to newController's field and marks
+// it to return to its owner during this turn's End Phase (Crossed Wires).
+func (d *Duel) changeControlUntilEndPhase(card *CardInstance, newController int) error {
+	if err := d.changeControl(card, newController); err != nil {
+		return err
+	}
+	card.Counters["control_until_end"] = d.State.Turn
+	return nil
+}
+
+// returnTemporaryControl gives agents taken until the End Phase back to their
+// owners. Agents whose owner has no free zone yet wait for another to leave.
+func (d *Duel) returnTemporaryControl() {
+	gs := d.State
+	var pending []*CardInstance
+	for p := 0; p < 2; p++ {
+		for _, m := range gs.Players[p].Agents() {
+			if m.Controller != m.Owner && m.Counters["control_until_end"] == gs.Turn {
+				pending = append(pending, m)
+			}
+		}
+	}
+	for len(pending) > 0 {
+		var waiting []*CardInstance
+		for _, m := range pending {
+			if gs.Players[m.Owner].FreeAgentZone() == -1 {
+				waiting = append(waiting, m)
+				continue
+			}
+			delete(m.Counters, "control_until_end")
+			_ = d.changeControl(m, m.Owner)
+		}
+		if len(waiting) == len(pending) {
+			return
+		}
+		pending = waiting
+	}
+}
@@ -3,6 +3,8 @@ package game
 import (
 	"fmt"
 	"math/rand"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
 )
 
 const (
@@ -24,6 +26,8 @@ type Player struct {
 	AgentZones [AgentZoneCount]*CardInstance
 	TechZones  [TechZoneCount]*CardInstance
 	OS         *CardInstance
+
+	SkipNextDraw bool // the next Draw Phase draw is skipped (Rapid Fabricator)
 }
 
 // DeckCount returns the number of cards remaining in the deck.
@@ -50,6 +54,36 @@ func (p *Player) DrawCard() *CardInstance {
 	return card
 }
 
+// PeekDeck returns the top card of the deck without drawing it, or nil if the deck is empty.
+func (p *Player) PeekDeck() *CardInstance {
+	if len(p.Deck) == 0 {
+		return nil
+	}
+	return p.Deck[len(p.Deck)-1]
+}
+
+// PlaceOnDeckTop puts a card on top of the deck, so it is the next card drawn.
+func (p *Player) PlaceOnDeckTop(card *CardInstance) {
+	card.Zone = ZoneDeck
+	p.Deck = append(p.Deck, card)
+}
+
+// PlaceOnDeckBottom puts a card on the bottom of the deck, so it is the last card drawn.
+func (p *Player) PlaceOnDeckBottom(card *CardInstance) {
+	card.Zone = ZoneDeck
+	p.Deck = append([]*CardInstance{card}, p.Deck...)
+}
+
+// RemoveFromDeck removes a card from the deck by instance ID.
+func (p *Player) RemoveFromDeck(card *CardInstance) {
+	for i, c := range p.Deck {
+		if c.ID == card.ID {
+			p.Deck = append(p.Deck[:i], p.Deck[i+1:]...)
+			return
+		}
+	}
+}
+
 // RemoveFromHand removes a card from the hand by instance ID.
 func (p *Player) RemoveFromHand(card *CardInstance) {
 	for i, c := range p.Hand {
@@ -212,13 +246,34 @@ func (p *Player) FaceUpAgents() []*CardInstance {
 	return result
 }
 
-// ShuffleDeck randomizes the deck order.
-func (p *Player) ShuffleDeck() {
-	rand.Shuffle(len(p.Deck), func(i, j int) {
+// ShuffleDeck randomizes the deck order using the duel's RNG.
+func (p *Player) ShuffleDeck(rng *rand.Rand) {
+	rng.Shuffle(len(p.Deck), func(i, j int) {
 		p.Deck[i], p.Deck[j] = p.Deck[j], p.Deck[i]
 	})
 }
 
+// OwnedCards returns every card the player owns, wherever it is: deck, hand,
+// either side of the field (control can change), scrapheap or purged zone.
+// Tokens aren't included: they never came from the player's deck.
+func (gs *GameState) OwnedCards(player int) []*Card {
+	var cards []*Card
+	add := func(ci *CardInstance) {
+		if ci != nil && ci.Owner == player && !ci.Card.IsToken {
+			cards = append(cards, ci.Card)
+		}
+	}
+	for _, p := range gs.Players {
+		for _, zone := range [][]*CardInstance{p.Deck, p.Hand, p.AgentZones[:], p.TechZones[:], p.Scrapheap, p.Purged} {
+			for _, ci := range zone {
+				add(ci)
+			}
+		}
+		add(p.OS)
+	}
+	return cards
+}
+
 // SummonEventInfo holds information about a summon that just occurred, for trigger matching.
 type SummonEventInfo struct {
 	Card   *CardInstance
@@ -227,6 +282,12 @@ type SummonEventInfo struct {
 
 // --- GameState ---
 
+// CoinToss is the result of the coin toss that decides who goes first.
+type CoinToss struct {
+	Winner  int  `json:"winner"`
+	GoFirst bool `json:"go_first"` // the winner's choice
+}
+
 // GameState holds the complete state of a duel.
 type GameState struct {
 	Players    [2]*Player
@@ -234,27 +295,46 @@ type GameState struct {
 	TurnPlayer int // 0 or 1: whose turn it is
 	Phase      Phase
 	BattleStep BattleStep
+	CoinToss   *CoinToss // the pre-game coin toss, if one was held
+
+	// ExtraTurnFor is the player who takes the next turn regardless of whose
+	// turn it is now, or -1. At most one extra turn is pending at a time.
+	ExtraTurnFor int
 
 	// Per-turn flags
-	NormalSummonUsed bool
+	NormalSummonUsed       bool
+	AgentsSummonedThisTurn [2]int  // summons of every kind, per player
+	NoBattleDamage         [2]bool // a player takes no battle damage for the rest of the turn
+	TotalAttacksDeclared   [2]int  // attacks declared by each player's agents, all together
+	NoAgentSet             bool    // neither player can Set agents for the rest of the turn
+	DestroyedByBattle      [2]bool // a player's agent was destroyed by battle this Battle Phase
 
 	// Battle tracking
 	CurrentAttacker *CardInstance
 	CurrentTarget   *CardInstance // nil for direct attack
+	AttackNegated   bool          // the current attack ends once its response window closes
 
 	// Chain system
-	Chain            *Chain
-	PendingTriggers  []PendingTrigger
-	LastSummonEvent  *SummonEventInfo // info about most recent summon for trigger matching
-	InResponseWindow bool             // true when inside openResponseWindow
+	Chain               *Chain
+	PendingTriggers     []PendingTrigger
+	LastSummonEvent     *SummonEventInfo // info about most recent summon for trigger matching
+	LastBattleDestroyed []*CardInstance  // agents just destroyed by battle, for trigger matching
+	InResponseWindow    bool             // true when inside openResponseWindow
+	ResponseEvent       log.EventType    // the event that opened the current response window
+	RevealedHands       [2]bool          // a player's hand is currently revealed to their opponent
+
+	// SuppressTrapResponse keeps the turn player's opponent from activating
+	// Traps in response to the summon being processed (Stealth Glider)
+	SuppressTrapResponse bool
 
 	// ID counter for card instances
 	nextID int
 
 	// Game result
-	Winner int // 0, 1, or -1 (no winner yet)
-	Over   bool
-	Result string
+	Winner    int // 0, 1, or -1 (no winner yet)
+	Over      bool
+	Result    string
+	WinReason WinReason
 }
 
 // NewGameState creates a fresh duel state.
@@ -264,10 +344,11 @@ func NewGameState() *GameState {
 			{HP: StartingHP},
 			{HP: StartingHP},
 		},
-		Turn:       0,
-		TurnPlayer: 0,
-		Phase:      PhaseNone,
-		Winner:     -1,
+		Turn:         0,
+		TurnPlayer:   0,
+		Phase:        PhaseNone,
+		ExtraTurnFor: -1,
+		Winner:       -1,
 	}
 	return gs
 }
@@ -283,6 +364,26 @@ func (gs *GameState) Opponent(player int) int {
 	return 1 - player
 }
 
+// HandRevealed reports whether player's hand is revealed to their opponent,
+// for the moment (Forced Disclosure) or while the opponent controls a face-up
+// card that keeps it revealed (Total Surveillance).
+func (gs *GameState) HandRevealed(player int) bool {
+	if gs.RevealedHands[player] {
+		return true
+	}
+	for _, st := range gs.Players[gs.Opponent(player)].TechCards() {
+		if st.Face != FaceUp || gs.activationPending(st) {
+			continue
+		}
+		for _, eff := range st.Card.Effects {
+			if eff.RevealsOpponentHand {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // CurrentPlayer returns the Player struct for the turn player.
 func (gs *GameState) CurrentPlayer() *Player {
 	return gs.Players[gs.TurnPlayer]
@@ -301,18 +402,21 @@ func (gs *GameState) CheckWinCondition() bool {
 
 	if p0Dead && p1Dead {
 		gs.Over = true
+		gs.WinReason = WinReasonHPZero
 		gs.Winner = -1
 		gs.Result = "Draw — both players' HP reached 0"
 		return true
 	}
 	if p0Dead {
 		gs.Over = true
+		gs.WinReason = WinReasonHPZero
 		gs.Winner = 1
 		gs.Result = fmt.Sprintf("P2 wins — P1's HP reached 0")
 		return true
 	}
 	if p1Dead {
 		gs.Over = true
+		gs.WinReason = WinReasonHPZero
 		gs.Winner = 0
 		gs.Result = fmt.Sprintf("P1 wins — P2's HP reached 0")
 		return true
@@ -323,6 +427,10 @@ func (gs *GameState) CheckWinCondition() bool {
 // ResetTurnFlags resets per-turn tracking for a new turn.
 func (gs *GameState) ResetTurnFlags() {
 	gs.NormalSummonUsed = false
+	gs.AgentsSummonedThisTurn = [2]int{}
+	gs.NoBattleDamage = [2]bool{}
+	gs.TotalAttacksDeclared = [2]int{}
+	gs.NoAgentSet = false
 	gs.CurrentAttacker = nil
 	gs.CurrentTarget = nil
 
@@ -331,9 +439,19 @@ func (gs *GameState) ResetTurnFlags() {
 		for _, m := range gs.Players[p].AgentZones {
 			if m != nil {
 				m.AttackedThisTurn = false
+				m.AttacksThisTurn = 0
 				m.PositionChangedThisTurn = false
 			}
 		}
+		// Once-per-turn usage is cleared wherever the card has gone since
+		pl := gs.Players[p]
+		for _, zone := range [][]*CardInstance{pl.Deck, pl.Hand, pl.Scrapheap, pl.Purged, pl.AgentZones[:], pl.TechZones[:], {pl.OS}} {
+			for _, c := range zone {
+				if c != nil {
+					c.usedOPT = nil
+				}
+			}
+		}
 	}
 }
 
@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/peterkuimelis/tcgx/internal/log"
 )
@@ -33,14 +34,16 @@ func (d *Duel) computeMainPhaseActions(player int) []Action {
 					Desc:   fmt.Sprintf("Normal Summon %s (ATK %d) to Zone %d", card.Card.Name, card.Card.ATK, freeZones[0]+1),
 				})
 				// Normal Set (L1-4)
-				actions = append(actions, Action{
-					Type:   ActionNormalSet,
-					Player: player,
-					Card:   card,
-					Zone:   freeZones[0],
-					Desc:   fmt.Sprintf("Set %s in Zone %d", card.Card.Name, freeZones[0]+1),
-				})
-			} else if sacrifices > 0 && p.AgentCount() >= sacrifices {
+				if !gs.NoAgentSet {
+					actions = append(actions, Action{
+						Type:   ActionNormalSet,
+						Player: player,
+						Card:   card,
+						Zone:   freeZones[0],
+						Desc:   fmt.Sprintf("Set %s in Zone %d", card.Card.Name, freeZones[0]+1),
+					})
+				}
+			} else if sacrifices > 0 && tributeTotal(p.Agents(), card.Card) >= sacrifices {
 				// Sacrifice Summon/Set — need enough agents to sacrifice
 				// We check if there's a zone available after sacrificing.
 				// (Sacrificing opens a zone, so we always have space if we can sacrifice.)
@@ -50,12 +53,14 @@ func (d *Duel) computeMainPhaseActions(player int) []Action {
 					Card:   card,
 					Desc:   fmt.Sprintf("Sacrifice Summon %s (requires %d sacrifice(s))", card.Card.Name, sacrifices),
 				})
-				actions = append(actions, Action{
-					Type:   ActionSacrificeSet,
-					Player: player,
-					Card:   card,
-					Desc:   fmt.Sprintf("Sacrifice Set %s (requires %d sacrifice(s))", card.Card.Name, sacrifices),
-				})
+				if !gs.NoAgentSet {
+					actions = append(actions, Action{
+						Type:   ActionSacrificeSet,
+						Player: player,
+						Card:   card,
+						Desc:   fmt.Sprintf("Sacrifice Set %s (requires %d sacrifice(s))", card.Card.Name, sacrifices),
+					})
+				}
 			}
 		}
 	}
@@ -124,7 +129,7 @@ func (d *Duel) computeMainPhaseActions(player int) []Action {
 			continue
 		}
 		for ei, eff := range card.Card.Effects {
-			if eff.CanActivate != nil && !eff.CanActivate(d, card, player) {
+			if !d.canActivateEffect(card, eff, player) {
 				continue
 			}
 			// OS programs don't need tech zone, they use the OS zone
@@ -161,7 +166,7 @@ func (d *Duel) computeMainPhaseActions(player int) []Action {
 			continue
 		}
 		for ei, eff := range card.Card.Effects {
-			if eff.CanActivate != nil && !eff.CanActivate(d, card, player) {
+			if !d.canActivateEffect(card, eff, player) {
 				continue
 			}
 			// Skip trigger effects — they activate in response windows
@@ -187,7 +192,7 @@ func (d *Duel) computeMainPhaseActions(player int) []Action {
 			if eff.EffectType != EffectIgnition {
 				continue
 			}
-			if eff.CanActivate != nil && !eff.CanActivate(d, m, player) {
+			if !d.canActivateEffect(m, eff, player) {
 				continue
 			}
 			actions = append(actions, Action{
@@ -203,10 +208,11 @@ func (d *Duel) computeMainPhaseActions(player int) []Action {
 	// Special summon actions (agents with special summon conditions)
 	actions = d.addSpecialSummonActions(player, actions)
 
-	// Phase transitions
+	// Phase transitions. Battle Phase is only reachable from Main Phase 1, so once
+	// it has ended (Main Phase 2) it can't be entered again this turn.
 	if gs.Phase == PhaseMain1 {
 		// Can enter battle phase (but not on turn 1)
-		if gs.Turn > 1 || gs.TurnPlayer == 1 {
+		if gs.Turn > 1 {
 			actions = append(actions, Action{
 				Type: ActionEnterBattlePhase,
 				Desc: "Enter Battle Phase",
@@ -219,6 +225,7 @@ func (d *Duel) computeMainPhaseActions(player int) []Action {
 		Type: ActionEndTurn,
 		Desc: "End Turn",
 	})
+	actions = append(actions, concedeAction(player))
 
 	return actions
 }
@@ -242,10 +249,21 @@ func (d *Duel) executeNormalSummon(action Action) error {
 	d.log(log.NewNormalSummonEvent(gs.Turn, gs.Phase.String(), action.Player, card.Card.Name, card.CurrentATK(), zone))
 
 	// Store summon info for trigger effects
-	gs.LastSummonEvent = &SummonEventInfo{Card: card, Player: action.Player}
+	d.recordSummon(card, action.Player)
 
 	d.recalculateContinuousEffects()
 
+	// A trap-locking agent (Stealth Glider) keeps the opponent's Traps out of
+	// this window. processEffectSerialization lifts the lock once the window
+	// closes; the defer covers a summon nothing responds to.
+	for _, eff := range card.Card.Effects {
+		if eff.SummonLockTraps {
+			gs.SuppressTrapResponse = true
+			defer func() { gs.SuppressTrapResponse = false }()
+			break
+		}
+	}
+
 	// Post-summon response window (e.g. Cascade Failure)
 	if err := d.processEffectSerialization(log.EventNormalSummon); err != nil {
 		return err
@@ -280,15 +298,9 @@ func (d *Duel) executeSacrificeSummon(action Action) error {
 	gs := d.State
 	p := gs.Players[action.Player]
 	card := action.Card
-	sacCount := card.Card.SacrificesRequired()
 
 	// Ask player to choose sacrifice targets
-	candidates := p.Agents()
-	sacrifices, err := d.Controllers[action.Player].ChooseCards(
-		d.ctx, gs,
-		fmt.Sprintf("Choose %d agent(s) to sacrifice for %s", sacCount, card.Card.Name),
-		candidates, sacCount, sacCount,
-	)
+	sacrifices, err := d.chooseSacrifices(action.Player, card, card.Card.Name)
 	if err != nil {
 		return err
 	}
@@ -323,7 +335,8 @@ func (d *Duel) executeSacrificeSummon(action Action) error {
 	d.log(log.NewSacrificeSummonEvent(gs.Turn, gs.Phase.String(), action.Player, card.Card.Name, card.CurrentATK(), freeZone, sacrificeNames))
 
 	// Store summon info for trigger effects
-	gs.LastSummonEvent = &SummonEventInfo{Card: card, Player: action.Player}
+	d.recordSummon(card, action.Player)
+	d.triggerUsedAsTribute(sacrifices, action.Player)
 
 	d.recalculateContinuousEffects()
 
@@ -340,14 +353,8 @@ func (d *Duel) executeSacrificeSet(action Action) error {
 	gs := d.State
 	p := gs.Players[action.Player]
 	card := action.Card
-	sacCount := card.Card.SacrificesRequired()
 
-	candidates := p.Agents()
-	sacrifices, err := d.Controllers[action.Player].ChooseCards(
-		d.ctx, gs,
-		fmt.Sprintf("Choose %d agent(s) to sacrifice for setting %s", sacCount, card.Card.Name),
-		candidates, sacCount, sacCount,
-	)
+	sacrifices, err := d.chooseSacrifices(action.Player, card, "setting "+card.Card.Name)
 	if err != nil {
 		return err
 	}
@@ -376,10 +383,89 @@ func (d *Duel) executeSacrificeSet(action Action) error {
 	gs.NormalSummonUsed = true
 
 	d.log(log.NewSetAgentEvent(gs.Turn, gs.Phase.String(), action.Player, freeZone))
+	d.triggerUsedAsTribute(sacrifices, action.Player)
 
 	return nil
 }
 
+// chooseSacrifices asks the player for agents worth enough sacrifices to
+// Sacrifice Summon or Set card, prompting again while the chosen agents fall short.
+func (d *Duel) chooseSacrifices(player int, card *CardInstance, purpose string) ([]*CardInstance, error) {
+	gs := d.State
+	required := card.Card.SacrificesRequired()
+	candidates := gs.Players[player].Agents()
+
+	// The fewest agents that can cover the requirement, highest values first
+	values := make([]int, len(candidates))
+	for i, c := range candidates {
+		values[i] = c.Card.TributeValue(card.Card)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	minCount, total := 0, 0
+	for _, v := range values {
+		if total >= required {
+			break
+		}
+		total += v
+		minCount++
+	}
+
+	sacrifices, err := d.Controllers[player].ChooseCards(
+		d.ctx, gs,
+		fmt.Sprintf("Choose %d agent(s) to sacrifice for %s", required, purpose),
+		candidates, minCount, required,
+	)
+	if err != nil {
+		return nil, err
+	}
+	sacrifices = append([]*CardInstance(nil), sacrifices...)
+	for tributeTotal(sacrifices, card.Card) < required {
+		chosen := make(map[int]bool, len(sacrifices))
+		for _, c := range sacrifices {
+			chosen[c.ID] = true
+		}
+		var remaining []*CardInstance
+		for _, c := range candidates {
+			if !chosen[c.ID] {
+				remaining = append(remaining, c)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil, fmt.Errorf("not enough sacrifices for %s", card.Card.Name)
+		}
+		more, err := d.Controllers[player].ChooseCards(d.ctx, gs,
+			fmt.Sprintf("Choose another agent to sacrifice for %s", purpose), remaining, 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(more) == 0 {
+			return nil, fmt.Errorf("not enough sacrifices for %s", card.Card.Name)
+		}
+		sacrifices = append(sacrifices, more[0])
+	}
+	return sacrifices, nil
+}
+
+// tributeTotal sums how many sacrifices the given agents count as for forCard.
+func tributeTotal(agents []*CardInstance, forCard *Card) int {
+	total := 0
+	for _, a := range agents {
+		total += a.Card.TributeValue(forCard)
+	}
+	return total
+}
+
+// triggerUsedAsTribute fires the OnUsedAsTribute effects of the agents just sacrificed.
+func (d *Duel) triggerUsedAsTribute(sacrifices []*CardInstance, controller int) {
+	for _, sac := range sacrifices {
+		for _, eff := range sac.Card.Effects {
+			if eff.OnUsedAsTribute != nil && !d.State.Over {
+				eff.OnUsedAsTribute(d, sac, controller)
+			}
+		}
+	}
+}
+
 // executeFlipSummon flips a face-down DEF agent to face-up ATK.
 func (d *Duel) executeFlipSummon(action Action) error {
 	gs := d.State
@@ -392,7 +478,7 @@ func (d *Duel) executeFlipSummon(action Action) error {
 	d.log(log.NewFlipSummonEvent(gs.Turn, gs.Phase.String(), action.Player, card.Card.Name, card.CurrentATK(), card.ZoneIndex))
 
 	// Store summon info for trigger effects
-	gs.LastSummonEvent = &SummonEventInfo{Card: card, Player: action.Player}
+	d.recordSummon(card, action.Player)
 
 	// Check for flip effects on this agent
 	d.queueFlipEffects(card, action.Player)
@@ -410,7 +496,7 @@ func (d *Duel) queueFlipEffects(card *CardInstance, controller int) {
 		if eff.EffectType != EffectFlip {
 			continue
 		}
-		if eff.CanActivate != nil && !eff.CanActivate(d, card, controller) {
+		if !d.canActivateEffect(card, eff, controller) {
 			continue
 		}
 		d.State.PendingTriggers = append(d.State.PendingTriggers, PendingTrigger{
@@ -421,6 +507,19 @@ func (d *Duel) queueFlipEffects(card *CardInstance, controller int) {
 	}
 }
 
+// forcePosition switches a face-up agent's battle position by a card effect
+// (Knockdown Pulse). Unlike executeChangePosition it ignores, and doesn't use
+// up, the once-per-turn voluntary change.
+func (d *Duel) forcePosition(card *CardInstance, position Position) {
+	gs := d.State
+	if card.Position == position {
+		return
+	}
+	card.Position = position
+	d.log(log.NewChangePositionEvent(gs.Turn, gs.Phase.String(), card.Controller, card.Card.Name, position.String()))
+	d.recalculateContinuousEffects()
+}
+
 // executeChangePosition changes a agent's battle position.
 func (d *Duel) executeChangePosition(action Action) {
 	gs := d.State
@@ -434,4 +533,5 @@ func (d *Duel) executeChangePosition(action Action) {
 	card.PositionChangedThisTurn = true
 
 	d.log(log.NewChangePositionEvent(gs.Turn, gs.Phase.String(), action.Player, card.Card.Name, card.Position.String()))
+	d.recalculateContinuousEffects() // position-dependent buffs (Fortress Protocol)
 }
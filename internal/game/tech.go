@@ -189,15 +189,25 @@ func (d *Duel) executeActivateTrap(action Action) error {
 // --- Effect helper functions used by card closures ---
 
 // destroyByEffect removes a card from the field and sends it to scrapheap.
-func (d *Duel) destroyByEffect(card *CardInstance, reason string) {
+func (d *Duel) destroyByEffect(card *CardInstance, source *CardInstance, reason string) {
 	gs := d.State
 	controller := card.Controller
 
+	if source != nil && d.isUnaffectedBy(card, source) {
+		d.log(log.NewUnaffectedEvent(gs.Turn, gs.Phase.String(), controller, card.Card.Name, source.Card.Name))
+		return
+	}
+
+	if d.survivesDestruction(card, reason) {
+		return
+	}
+
 	d.log(log.NewDestroyEvent(gs.Turn, gs.Phase.String(), controller, card.Card.Name, reason))
 
 	// Trigger OnLeaveField handlers before detaching/removing
 	d.triggerOnLeaveField(card)
 
+	wasAgent := card.Zone == ZoneAgent
 	switch card.Zone {
 	case ZoneAgent:
 		// Destroy equips attached to this agent
@@ -213,27 +223,216 @@ func (d *Duel) destroyByEffect(card *CardInstance, reason string) {
 		gs.Players[controller].OS = nil
 	}
 
-	gs.Players[card.Owner].SendToScrapheap(card)
-	d.log(log.NewSendToScrapheapEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, "destroyed by "+reason))
+	d.sendDestroyed(card, "destroyed by "+reason)
 	d.recalculateContinuousEffects()
+	if wasAgent {
+		d.triggerAgentDestroyed(card, controller)
+	}
+}
+
+// triggerAgentDestroyed fires the OnAgentDestroyed effects of face-up
+// Continuous Traps for an agent that was just destroyed.
+func (d *Duel) triggerAgentDestroyed(destroyed *CardInstance, controller int) {
+	for _, c := range d.faceUpCards() {
+		if c.Card.CardType != CardTypeTrap || c.Card.TrapSub != TrapContinuous || d.activationPending(c) {
+			continue
+		}
+		for _, eff := range c.Card.Effects {
+			if eff.OnAgentDestroyed != nil && !d.State.Over {
+				eff.OnAgentDestroyed(d, c, destroyed, controller)
+			}
+		}
+	}
+}
+
+// destroyDestination returns where a destroyed card goes in place of the
+// scrapheap, if one of its replacement effects applies (Phoenix Process, etc.).
+func (d *Duel) destroyDestination(card *CardInstance) (ZoneType, bool) {
+	for _, eff := range card.Card.Effects {
+		if eff.ReplacementDestination == nil {
+			continue
+		}
+		if zone, ok := eff.ReplacementDestination(d, card); ok {
+			return zone, true
+		}
+	}
+	return ZoneScrapheap, false
+}
+
+// survivesDestruction reports whether a replacement effect keeps card in its
+// current field zone instead of letting it be destroyed.
+func (d *Duel) survivesDestruction(card *CardInstance, reason string) bool {
+	zone, ok := d.destroyDestination(card)
+	if !ok || zone != card.Zone {
+		return false
+	}
+	gs := d.State
+	d.log(log.NewUnaffectedEvent(gs.Turn, gs.Phase.String(), card.Controller, card.Card.Name, reason))
+	return true
+}
+
+// sendDestroyed moves a destroyed card, already removed from the field, to its
+// owner's scrapheap or to the destination its replacement effect names.
+func (d *Duel) sendDestroyed(card *CardInstance, reason string) {
+	gs := d.State
+	owner := gs.Players[card.Owner]
+	zone, _ := d.destroyDestination(card)
+	switch zone {
+	case ZoneDeck:
+		resetFieldState(card)
+		owner.PlaceOnDeckTop(card)
+		d.log(log.NewReturnToDeckEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, reason))
+	case ZoneHand:
+		resetFieldState(card)
+		card.Zone = ZoneHand
+		card.ZoneIndex = len(owner.Hand)
+		owner.Hand = append(owner.Hand, card)
+		d.log(log.NewAddToHandEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, reason))
+	case ZonePurged:
+		resetFieldState(card)
+		card.Zone = ZonePurged
+		card.Face = FaceUp
+		owner.Purged = append(owner.Purged, card)
+		d.log(log.NewPurgeEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, reason))
+	default:
+		owner.SendToScrapheap(card)
+		d.log(log.NewSendToScrapheapEvent(gs.Turn, gs.Phase.String(), card.Owner, card.Card.Name, reason))
+	}
+}
+
+// faceUpCards returns every face-up card on the field: agents, tech and OS.
+func (d *Duel) faceUpCards() []*CardInstance {
+	gs := d.State
+	var cards []*CardInstance
+	for p := 0; p < 2; p++ {
+		cards = append(cards, gs.Players[p].FaceUpAgents()...)
+		for _, st := range gs.Players[p].TechCards() {
+			if st.Face == FaceUp {
+				cards = append(cards, st)
+			}
+		}
+		if fs := gs.Players[p].OS; fs != nil && fs.Face == FaceUp {
+			cards = append(cards, fs)
+		}
+	}
+	return cards
+}
+
+// isUnaffectedBy checks whether any face-up card grants target immunity to source's effects.
+func (d *Duel) isUnaffectedBy(target, source *CardInstance) bool {
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.UnaffectedBy != nil && eff.UnaffectedBy(d, c, target, source) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// currentLevel returns an agent's Level after any face-up Level modifiers
+// (The Undercity Grid, etc.). A Level never drops below 1.
+func (d *Duel) currentLevel(agent *CardInstance) int {
+	level := agent.Card.Level
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.LevelMod != nil {
+				level += eff.LevelMod(d, c, agent)
+			}
+		}
+	}
+	if level < 1 {
+		level = 1
+	}
+	return level
+}
+
+// highestATKAgents returns the face-up ATK Position agents tied for the highest
+// ATK on the field (Headshot Routine). The order is stable: the first player's
+// discardRandom discards a card chosen at random from the player's hand,
+// drawing from the duel RNG. It returns nil if the hand is empty.
+func (d *Duel) discardRandom(player int, reason string) *CardInstance {
+	gs := d.State
+	p := gs.Players[player]
+	if len(p.Hand) == 0 {
+		return nil
+	}
+	i := 0
+	if !d.deterministicRandom {
+		i = d.rng.Intn(len(p.Hand))
+	}
+	c := p.Hand[i]
+	p.RemoveFromHand(c)
+	p.SendToScrapheap(c)
+	ev := log.NewDiscardEvent(gs.Turn, gs.Phase.String(), player, c.Card.Name)
+	ev.Details += fmt.Sprintf(" at random (%s)", reason)
+	d.log(ev)
+	d.recalculateContinuousEffects()
+	return c
+}
+
+// zones left to right, then the second player's.
+func (d *Duel) highestATKAgents() []*CardInstance {
+	var highest []*CardInstance
+	maxATK := -1
+	for p := 0; p < 2; p++ {
+		for _, m := range d.State.Players[p].FaceUpATKAgents() {
+			atk := m.CurrentATK()
+			if atk > maxATK {
+				maxATK = atk
+				highest = []*CardInstance{m}
+			} else if atk == maxATK {
+				highest = append(highest, m)
+			}
+		}
+	}
+	return highest
+}
+
+// isUntargetable checks whether any face-up card stops source's effects from targeting target.
+func (d *Duel) isUntargetable(target, source *CardInstance) bool {
+	for _, c := range d.faceUpCards() {
+		for _, eff := range c.Card.Effects {
+			if eff.Untargetable != nil && eff.Untargetable(d, c, target, source) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// canTarget reports whether source's effects can target target.
+func (d *Duel) canTarget(source, target *CardInstance) bool {
+	return !d.isUnaffectedBy(target, source) && !d.isUntargetable(target, source)
+}
+
+// targetableBy filters candidates down to those source's effects can target.
+func (d *Duel) targetableBy(source *CardInstance, candidates []*CardInstance) []*CardInstance {
+	var result []*CardInstance
+	for _, c := range candidates {
+		if d.canTarget(source, c) {
+			result = append(result, c)
+		}
+	}
+	return result
 }
 
 // destroyAllAgents destroys all agents on the field (Void Purge / Cascade Failure).
-func (d *Duel) destroyAllAgents(reason string) {
+func (d *Duel) destroyAllAgents(source *CardInstance, reason string) {
 	gs := d.State
 	for p := 0; p < 2; p++ {
 		for _, m := range gs.Players[p].Agents() {
-			d.destroyByEffect(m, reason)
+			d.destroyByEffect(m, source, reason)
 		}
 	}
 }
 
 // destroyAllTech destroys all program/trap cards on the field (EMP Cascade).
-func (d *Duel) destroyAllTech(reason string) {
+func (d *Duel) destroyAllTech(source *CardInstance, reason string) {
 	gs := d.State
 	for p := 0; p < 2; p++ {
 		for _, st := range gs.Players[p].TechCards() {
-			d.destroyByEffect(st, reason)
+			d.destroyByEffect(st, source, reason)
 		}
 	}
 }
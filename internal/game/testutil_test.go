@@ -247,10 +247,18 @@ func makePaddedDeck(topCards []*Card, minSize int) []*Card {
 
 // runDuelToCompletion runs a duel and returns the logger for inspection.
 func runDuelToCompletion(t *testing.T, cfg DuelConfig, p0, p1 *ScriptedController) *log.MemoryLogger {
+	t.Helper()
+	_, logger := runDuel(t, cfg, p0, p1)
+	return logger
+}
+
+// runDuel runs a duel and returns both the finished duel (for state inspection) and the logger.
+func runDuel(t *testing.T, cfg DuelConfig, p0, p1 PlayerController) (*Duel, *log.MemoryLogger) {
 	t.Helper()
 	logger := log.NewMemoryLogger()
 	cfg.Logger = logger
 	cfg.NoShuffle = true // deterministic tests
+	cfg.DeterministicRandom = true
 	if cfg.MaxTurns == 0 {
 		cfg.MaxTurns = 100 // reasonable default for tests
 	}
@@ -267,5 +275,15 @@ func runDuelToCompletion(t *testing.T, cfg DuelConfig, p0, p1 *ScriptedControlle
 	t.Logf("Duel result: winner=%d (%s)", winner, duel.State.Result)
 	t.Logf("Event log:\n%s", log.FormatAll(logger.Events()))
 
-	return logger
+	return duel, logger
+}
+
+// findAgent returns the first agent on the player's field with the given name, or nil.
+func findAgent(d *Duel, player int, name string) *CardInstance {
+	for _, m := range d.State.Players[player].Agents() {
+		if m.Card.Name == name {
+			return m
+		}
+	}
+	return nil
 }
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"context"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// timeoutController wraps a player's controller and applies a default decision
+// when the player doesn't answer within the duel's decision timeout.
+type timeoutController struct {
+	PlayerController
+	d      *Duel
+	player int
+}
+
+// awaitDecision runs a controller call against the decision timeout. On timeout
+// the call's context is cancelled and the call is waited out, so it never runs
+// alongside the rest of the duel; then the timeout is logged and fallback is
+// returned.
+func awaitDecision[T any](tc *timeoutController, ctx context.Context, prompt string, call func(ctx context.Context) (T, error), fallback func() T) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		v   T
+		err error
+	}
+	deadline := tc.d.clock.After(tc.d.decisionTimeout)
+	ch := make(chan result, 1)
+	go func() {
+		v, err := call(ctx)
+		ch <- result{v, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.v, r.err
+	case <-deadline:
+		cancel()
+		<-ch
+		gs := tc.d.State
+		tc.d.log(log.NewDecisionTimeoutEvent(gs.Turn, gs.Phase.String(), tc.player, prompt))
+		return fallback(), nil
+	}
+}
+
+func (tc *timeoutController) ChooseAction(ctx context.Context, state *GameState, actions []Action) (Action, error) {
+	return awaitDecision(tc, ctx, "choose an action",
+		func(ctx context.Context) (Action, error) {
+			return tc.PlayerController.ChooseAction(ctx, state, actions)
+		},
+		func() Action { return defaultAction(actions) })
+}
+
+func (tc *timeoutController) ChooseCards(ctx context.Context, state *GameState, prompt string, candidates []*CardInstance, min, max int) ([]*CardInstance, error) {
+	return awaitDecision(tc, ctx, prompt,
+		func(ctx context.Context) ([]*CardInstance, error) {
+			return tc.PlayerController.ChooseCards(ctx, state, prompt, candidates, min, max)
+		},
+		func() []*CardInstance {
+			if min > len(candidates) {
+				min = len(candidates)
+			}
+			return candidates[:min]
+		})
+}
+
+func (tc *timeoutController) ChooseYesNo(ctx context.Context, state *GameState, prompt string) (bool, error) {
+	return awaitDecision(tc, ctx, prompt,
+		func(ctx context.Context) (bool, error) {
+			return tc.PlayerController.ChooseYesNo(ctx, state, prompt)
+		},
+		func() bool { return false })
+}
+
+// defaultAction picks the passive choice applied when a decision times out:
+// pass a response window, otherwise move the turn along. A prompt with none of
+// those (ordering Standby Phase effects) takes its first choice, never a
+// concession.
+func defaultAction(actions []Action) Action {
+	for _, want := range []ActionType{ActionPass, ActionEndTurn, ActionEnterMainPhase2, ActionEndBattlePhase} {
+		for _, a := range actions {
+			if a.Type == want {
+				return a
+			}
+		}
+	}
+	for _, a := range actions {
+		if a.Type != ActionConcede {
+			return a
+		}
+	}
+	return actions[0]
+}
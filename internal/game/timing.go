@@ -8,14 +8,20 @@ import (
 
 // openResponseWindow gives both players the chance to chain fast effects.
 // startingPlayer gets priority first. Players alternate until both pass consecutively.
-func (d *Duel) openResponseWindow(startingPlayer int) error {
+// event is what opened the window, for effects with a WindowResponse Trigger.
+func (d *Duel) openResponseWindow(startingPlayer int, event log.EventType) error {
 	gs := d.State
 	if gs.Over {
 		return nil
 	}
 
 	gs.InResponseWindow = true
-	defer func() { gs.InResponseWindow = false }()
+	prevEvent := gs.ResponseEvent
+	gs.ResponseEvent = event
+	defer func() {
+		gs.InResponseWindow = false
+		gs.ResponseEvent = prevEvent
+	}()
 
 	passCount := 0
 	currentPlayer := startingPlayer
@@ -74,7 +80,7 @@ func (d *Duel) openResponseWindow(startingPlayer int) error {
 				card.Face = FaceUp
 			}
 			// If activating from hand (quick-play), place in tech zone
-			if card.Zone == ZoneHand {
+			if card.Zone == ZoneHand && card.Card.CardType == CardTypeProgram {
 				p := gs.Players[currentPlayer]
 				zone := p.FreeTechZone()
 				if zone == -1 {
@@ -108,6 +114,13 @@ func (d *Duel) openResponseWindow(startingPlayer int) error {
 	return nil
 }
 
+// trapLocked reports whether card is a Trap its controller cannot activate in
+// response to the current summon (Stealth Glider).
+func (d *Duel) trapLocked(card *CardInstance, player int) bool {
+	gs := d.State
+	return gs.SuppressTrapResponse && card.Card.CardType == CardTypeTrap && player != gs.TurnPlayer
+}
+
 // computeFastEffectActions returns activatable fast effects (SS2+) for a player.
 func (d *Duel) computeFastEffectActions(player int) []Action {
 	gs := d.State
@@ -128,7 +141,10 @@ func (d *Duel) computeFastEffectActions(player int) []Action {
 			if topSS > 0 && !canChainWith(topSS, eff.ExecSpeed) {
 				continue
 			}
-			if eff.CanActivate != nil && !eff.CanActivate(d, card, player) {
+			if !d.respondsInWindow(card, eff) || d.trapLocked(card, player) {
+				continue
+			}
+			if !d.canActivateEffect(card, eff, player) {
 				continue
 			}
 			actions = append(actions, Action{
@@ -141,6 +157,31 @@ func (d *Duel) computeFastEffectActions(player int) []Action {
 		}
 	}
 
+	// Hand traps (Bodyguard Splice, etc.), on either player's turn
+	for _, card := range p.Hand {
+		for ei, eff := range card.Card.Effects {
+			if !eff.HandTrap || eff.ExecSpeed < ExecSpeed2 {
+				continue
+			}
+			if topSS > 0 && !canChainWith(topSS, eff.ExecSpeed) {
+				continue
+			}
+			if !d.respondsInWindow(card, eff) {
+				continue
+			}
+			if !d.canActivateEffect(card, eff, player) {
+				continue
+			}
+			actions = append(actions, Action{
+				Type:        ActionActivate,
+				Player:      player,
+				Card:        card,
+				EffectIndex: ei,
+				Desc:        fmt.Sprintf("Activate %s from hand", card.Card.Name),
+			})
+		}
+	}
+
 	// Quick-Play programs from hand (during own turn only in main phase, any phase from set field)
 	// For simplicity: allow from hand if it's their turn or they have it set
 	if player == gs.TurnPlayer {
@@ -158,7 +199,7 @@ func (d *Duel) computeFastEffectActions(player int) []Action {
 				if topSS > 0 && !canChainWith(topSS, eff.ExecSpeed) {
 					continue
 				}
-				if eff.CanActivate != nil && !eff.CanActivate(d, card, player) {
+				if !d.canActivateEffect(card, eff, player) {
 					continue
 				}
 				actions = append(actions, Action{
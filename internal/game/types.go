@@ -60,6 +60,38 @@ func (s BattleStep) String() string {
 	}
 }
 
+// WinReason is why a duel ended. String gives the wire name clients branch on.
+type WinReason int
+
+const (
+	WinReasonNone      WinReason = iota
+	WinReasonHPZero              // a player's HP reached 0 (both at once is a draw)
+	WinReasonDeckout             // a player had to draw from an empty Deck
+	WinReasonSurrender           // a player conceded
+	WinReasonTurnLimit           // the turn limit was reached; always a draw
+	WinReasonAltWin              // a card's alternate win condition
+	WinReasonTimeOut             // a player ran out of time
+)
+
+func (r WinReason) String() string {
+	switch r {
+	case WinReasonHPZero:
+		return "hp_zero"
+	case WinReasonDeckout:
+		return "deckout"
+	case WinReasonSurrender:
+		return "surrender"
+	case WinReasonTurnLimit:
+		return "turn_limit"
+	case WinReasonAltWin:
+		return "alt_win"
+	case WinReasonTimeOut:
+		return "timeout"
+	default:
+		return ""
+	}
+}
+
 type Position int
 
 const (
@@ -181,6 +213,8 @@ type Card struct {
 	ATK         int
 	DEF         int
 	IsEffect    bool
+	IsToken     bool   // created by an effect rather than drawn from a deck
+	AlsoNamed   string // a second name the card has (The Undercity Grid is also "NetGrid")
 	ProgramSub  ProgramSubtype
 	TrapSub     TrapSubtype
 	Effects     []*CardEffect
@@ -201,6 +235,19 @@ func (c *Card) SacrificesRequired() int {
 	return 2
 }
 
+// TributeValue returns how many sacrifices this card counts as when it is
+// sacrificed for forCard's Sacrifice Summon or Set (Junkyard Lurker).
+func (c *Card) TributeValue(forCard *Card) int {
+	for _, eff := range c.Effects {
+		if eff.TributeValue != nil {
+			if v := eff.TributeValue(forCard); v > 0 {
+				return v
+			}
+		}
+	}
+	return 1
+}
+
 // --- Stat Modifiers ---
 
 // StatModifier represents an ATK/DEF modification from an effect.
@@ -210,6 +257,7 @@ type StatModifier struct {
 	DEFMod     int
 	Permanent  bool // survives source leaving the field
 	Continuous bool // recalculated by continuous effects system
+	EndOfTurn  bool // removed during the End Phase cleanup
 }
 
 // --- CardInstance (runtime card on field/hand/scrapheap) ---
@@ -230,17 +278,28 @@ type CardInstance struct {
 	TurnPlaced              int
 	TurnControlChanged      int
 	AttackedThisTurn        bool
+	AttacksThisTurn         int
 	PositionChangedThisTurn bool
 	Counters                map[string]int
+	usedOPT                 map[int]bool // OncePerTurn effects (by index) activated this turn
 
 	// Stat modifiers
 	Modifiers   []StatModifier
 	OriginalATK int // for effects that "set ATK to X" (0 = use Card.ATK)
 	OriginalDEF int // for effects that "set DEF to X" (0 = use Card.DEF)
 
+	// StatsSwapped swaps the original ATK and DEF until the end of the turn
+	// (Polarity Invert). Only the originals swap: every modifier, including
+	// one applied before the swap, still applies to the stat it names
+	StatsSwapped bool
+
 	// Equip tracking
 	EquippedTo *CardInstance   // if this is an equip card, what it's attached to
 	Equips     []*CardInstance // equip cards attached to this agent
+
+	// Battle tracking: the agent this one last battled, and on which turn
+	LastBattled     *CardInstance
+	LastBattledTurn int
 }
 
 func (ci *CardInstance) String() string {
@@ -267,12 +326,25 @@ func (ci *CardInstance) DisplayString() string {
 	return ci.Card.Name
 }
 
-// CurrentATK returns the effective ATK (base + all modifiers).
-func (ci *CardInstance) CurrentATK() int {
-	base := ci.Card.ATK
+// baseStats returns the ATK and DEF modifiers apply to: OriginalATK/DEF if
+// set, otherwise the printed values, swapped while StatsSwapped is set.
+func (ci *CardInstance) baseStats() (atk, def int) {
+	atk, def = ci.Card.ATK, ci.Card.DEF
 	if ci.OriginalATK != 0 {
-		base = ci.OriginalATK
+		atk = ci.OriginalATK
+	}
+	if ci.OriginalDEF != 0 {
+		def = ci.OriginalDEF
 	}
+	if ci.StatsSwapped {
+		atk, def = def, atk
+	}
+	return atk, def
+}
+
+// CurrentATK returns the effective ATK (base + all modifiers).
+func (ci *CardInstance) CurrentATK() int {
+	base, _ := ci.baseStats()
 	for _, mod := range ci.Modifiers {
 		base += mod.ATKMod
 	}
@@ -284,10 +356,7 @@ func (ci *CardInstance) CurrentATK() int {
 
 // CurrentDEF returns the effective DEF (base + all modifiers).
 func (ci *CardInstance) CurrentDEF() int {
-	base := ci.Card.DEF
-	if ci.OriginalDEF != 0 {
-		base = ci.OriginalDEF
-	}
+	_, base := ci.baseStats()
 	for _, mod := range ci.Modifiers {
 		base += mod.DEFMod
 	}
@@ -302,6 +371,16 @@ func (ci *CardInstance) AddModifier(mod StatModifier) {
 	ci.Modifiers = append(ci.Modifiers, mod)
 }
 
+// effectIndex returns the index of eff among the card's effects, or -1.
+func (ci *CardInstance) effectIndex(eff *CardEffect) int {
+	for i, e := range ci.Card.Effects {
+		if e == eff {
+			return i
+		}
+	}
+	return -1
+}
+
 // RemoveModifiersBySource removes all modifiers from the given source card.
 func (ci *CardInstance) RemoveModifiersBySource(sourceID int) {
 	filtered := ci.Modifiers[:0]
@@ -367,7 +446,8 @@ const (
 	ActionEnterMainPhase2
 	ActionEndTurn
 	ActionEndBattlePhase
-	ActionPass // explicitly pass priority
+	ActionPass    // explicitly pass priority
+	ActionConcede // forfeit the duel
 )
 
 func (a ActionType) String() string {
@@ -402,6 +482,8 @@ func (a ActionType) String() string {
 		return "End Battle Phase"
 	case ActionPass:
 		return "Pass"
+	case ActionConcede:
+		return "Concede"
 	default:
 		return "Unknown"
 	}
@@ -424,3 +506,25 @@ func (a Action) String() string {
 	}
 	return a.Type.String()
 }
+
+// Effect returns the card effect an ActionActivate uses, or nil for other actions.
+func (a Action) Effect() *CardEffect {
+	if a.Type != ActionActivate || a.Card == nil || a.EffectIndex >= len(a.Card.Card.Effects) {
+		return nil
+	}
+	return a.Card.Card.Effects[a.EffectIndex]
+}
+
+// OpensChain reports whether the action activates an effect onto the chain,
+// giving the opponent a chance to respond: an effect with an execution speed,
+// whether or not it targets. Special Summon procedures don't.
+func (a Action) OpensChain() bool {
+	eff := a.Effect()
+	return eff != nil && eff.ExecSpeed >= ExecSpeed1 && eff.SpecialSummonCondition == nil
+}
+
+// RequiresTargets reports whether the action will prompt for targets on activation.
+func (a Action) RequiresTargets() bool {
+	eff := a.Effect()
+	return eff != nil && eff.Target != nil
+}
@@ -1,5 +1,7 @@
 package log
 
+import "fmt"
+
 // EventType enumerates all observable game events.
 type EventType int
 
@@ -38,6 +40,31 @@ const (
 	EventHandSizeDiscard
 	EventFlipNoSummon  // flipped face-up by attack, not a flip summon
 	EventAttackStopped // attack cannot proceed due to restriction (e.g. Gravity Clamp)
+	EventCostPaid      // a cost (HP, discard, purge, tribute) was paid to activate an effect
+	EventRewind        // debug: duel rewound to the start of the current turn
+	EventUnaffected    // a card was unaffected by an effect due to an immunity
+	EventRevealHand    // a player's hand was revealed to their opponent
+	EventTimeout       // a player's decision timed out and the default was applied
+	EventReturnToDeck  // a card was returned to its owner's deck
+	EventDeckVerified  // a player's cards were checked against their deck commitment
+	EventSkipDraw      // a player's Draw Phase draw was skipped
+	EventRevealCard    // a single card was revealed to both players
+	EventConcede       // a player conceded the duel
+	EventDamageBlocked // battle damage to a player was prevented
+	EventTrace         // a chain link's resolution, traced for debugging
+	EventCoinToss      // the pre-game coin toss, and whether its winner went first
+	EventMulligan      // a player revealed an opening hand with no agents and redrew
+	EventUndo          // a player took back their latest decision
+
+	eventTypeCount // number of event types; keep last
+)
+
+// Cost kinds reported by EventCostPaid.
+const (
+	CostHP      = "HP"
+	CostDiscard = "discard"
+	CostPurge   = "purge"
+	CostTribute = "tribute"
 )
 
 func (e EventType) String() string {
@@ -110,11 +137,88 @@ func (e EventType) String() string {
 		return "FlipNoSummon"
 	case EventAttackStopped:
 		return "AttackStopped"
+	case EventCostPaid:
+		return "CostPaid"
+	case EventRewind:
+		return "Rewind"
+	case EventUnaffected:
+		return "Unaffected"
+	case EventRevealHand:
+		return "RevealHand"
+	case EventTimeout:
+		return "Timeout"
+	case EventReturnToDeck:
+		return "ReturnToDeck"
+	case EventDeckVerified:
+		return "DeckVerified"
+	case EventSkipDraw:
+		return "SkipDraw"
+	case EventRevealCard:
+		return "RevealCard"
+	case EventConcede:
+		return "Concede"
+	case EventDamageBlocked:
+		return "DamageBlocked"
+	case EventTrace:
+		return "Trace"
+	case EventCoinToss:
+		return "CoinToss"
+	case EventMulligan:
+		return "Mulligan"
+	case EventUndo:
+		return "Undo"
 	default:
 		return "Unknown"
 	}
 }
 
+// ParseEventType returns the event type with the given String name.
+func ParseEventType(name string) (EventType, error) {
+	for e := EventType(0); e < eventTypeCount; e++ {
+		if e.String() == name {
+			return e, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown event type %q", name)
+}
+
+// MarshalText encodes the event type by name, so JSON carries "Draw" rather than a number.
+func (e EventType) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText decodes an event type name written by MarshalText.
+func (e *EventType) UnmarshalText(text []byte) error {
+	t, err := ParseEventType(string(text))
+	if err != nil {
+		return err
+	}
+	*e = t
+	return nil
+}
+
+// Importance classifies how significant an event is to someone following the duel.
+type Importance int
+
+const (
+	ImportanceDebug  Importance = iota // rules bookkeeping: phase changes, damage calc, trigger queueing
+	ImportanceNormal                   // ordinary plays: draws, activations, destruction
+	ImportanceHigh                     // headline events: summons, attacks, HP changes, the result
+)
+
+// Importance returns the event type's importance.
+func (e EventType) Importance() Importance {
+	switch e {
+	case EventNormalSummon, EventSacrificeSummon, EventFlipSummon, EventSpecialSummon,
+		EventAttackDeclare, EventDirectAttackDeclare, EventHPChange, EventWin, EventDraw_Tie, EventConcede:
+		return ImportanceHigh
+	case EventPhaseChange, EventDamageCalc, EventTriggerQueued, EventChainLink, EventShuffle, EventCostPaid, EventTrace:
+		return ImportanceDebug
+	default:
+		return ImportanceNormal
+	}
+}
+
 // GameEvent represents a single observable event in a duel.
 type GameEvent struct {
 	Seq     int       // monotonic sequence number
@@ -124,4 +228,9 @@ type GameEvent struct {
 	Type    EventType // event type
 	Card    string    // card name (if applicable)
 	Details string    // human-readable detail string
+
+	// For EventCostPaid: the kind of cost (CostHP, etc.) and how much was
+	// paid, in HP or cards
+	CostKind   string
+	CostAmount int
 }
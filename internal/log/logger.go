@@ -1,6 +1,7 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -54,20 +55,90 @@ func (l *MemoryLogger) LastEvent() GameEvent {
 
 // --- TextLogger: writes human-readable lines to an io.Writer ---
 
+// LogLevel controls how much of the duel a TextLogger prints.
+type LogLevel int
+
+const (
+	LevelQuiet  LogLevel = iota // summons, attacks, HP changes and the result
+	LevelNormal                 // everything except rules bookkeeping
+	LevelDebug                  // every event
+)
+
+// minImportance returns the least important event printed at this level.
+func (lv LogLevel) minImportance() Importance {
+	switch lv {
+	case LevelQuiet:
+		return ImportanceHigh
+	case LevelNormal:
+		return ImportanceNormal
+	default:
+		return ImportanceDebug
+	}
+}
+
 type TextLogger struct {
 	MemoryLogger
-	w io.Writer
+	w     io.Writer
+	Level LogLevel // events below this level are recorded but not printed
 }
 
 func NewTextLogger(w io.Writer) *TextLogger {
-	return &TextLogger{w: w}
+	return &TextLogger{w: w, Level: LevelDebug}
 }
 
 func (l *TextLogger) Log(event GameEvent) {
 	l.MemoryLogger.Log(event)
+	if event.Type.Importance() < l.Level.minImportance() {
+		return
+	}
 	fmt.Fprintln(l.w, FormatEvent(event))
 }
 
+// --- JSONLogger: writes one JSON object per event to an io.Writer ---
+
+// JSONLogger streams events as newline-delimited JSON for machine consumers,
+// recording them like MemoryLogger as well.
+type JSONLogger struct {
+	MemoryLogger
+	enc *json.Encoder
+}
+
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{enc: json.NewEncoder(w)}
+}
+
+// jsonEvent is the wire form of a GameEvent, with the type by name.
+type jsonEvent struct {
+	Seq     int    `json:"seq"`
+	Turn    int    `json:"turn"`
+	Phase   string `json:"phase"`
+	Player  int    `json:"player"`
+	Type    string `json:"type"`
+	Card    string `json:"card,omitempty"`
+	Details string `json:"details"`
+
+	CostKind   string `json:"cost_kind,omitempty"`
+	CostAmount int    `json:"cost_amount,omitempty"`
+}
+
+func (l *JSONLogger) Log(event GameEvent) {
+	l.MemoryLogger.Log(event)
+	e := l.LastEvent()
+	// Write errors are dropped, as TextLogger drops them
+	_ = l.enc.Encode(jsonEvent{
+		Seq:     e.Seq,
+		Turn:    e.Turn,
+		Phase:   e.Phase,
+		Player:  e.Player,
+		Type:    e.Type.String(),
+		Card:    e.Card,
+		Details: e.Details,
+
+		CostKind:   e.CostKind,
+		CostAmount: e.CostAmount,
+	})
+}
+
 // --- Formatting ---
 
 // playerName returns "P1" or "P2" for display.
@@ -421,3 +492,272 @@ func NewShuffleEvent(turn int, phase string, player int) GameEvent {
 		Details: fmt.Sprintf("P%d shuffled their deck", player+1),
 	}
 }
+
+func NewCostPaidEvent(turn int, phase string, player int, cardName string, costKind string, amount int) GameEvent {
+	return GameEvent{
+		Turn:       turn,
+		Phase:      phase,
+		Player:     player,
+		Type:       EventCostPaid,
+		Card:       cardName,
+		Details:    fmt.Sprintf("%s pays cost for %s: %d %s", playerName(player), cardName, amount, costKind),
+		CostKind:   costKind,
+		CostAmount: amount,
+	}
+}
+
+func NewRewindEvent(turn int, player int) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Player:  player,
+		Type:    EventRewind,
+		Details: fmt.Sprintf("<<< Rewound to start of turn %d (%s) >>>", turn, playerName(player)),
+	}
+}
+
+func NewUnaffectedEvent(turn int, phase string, player int, cardName string, sourceName string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventUnaffected,
+		Card:    cardName,
+		Details: fmt.Sprintf("%s is unaffected by %s", cardName, sourceName),
+	}
+}
+
+func NewRevealHandEvent(turn int, phase string, player int, cards []string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventRevealHand,
+		Details: fmt.Sprintf("%s reveals their hand: %s", playerName(player), strings.Join(cards, ", ")),
+	}
+}
+
+func NewDecisionTimeoutEvent(turn int, phase string, player int, prompt string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventTimeout,
+		Details: fmt.Sprintf("%s timed out (%s); default applied", playerName(player), prompt),
+	}
+}
+
+// NewReturnToDeckTopEvent omits the card's name: a card placed from hand stays hidden.
+func NewReturnToDeckTopEvent(turn int, phase string, player int, reason string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventReturnToDeck,
+		Details: fmt.Sprintf("%s places a card from hand on top of their Deck (%s)", playerName(player), reason),
+	}
+}
+
+// NewReturnToDeckEvent records a card from the field being placed on top of its owner's Deck.
+func NewReturnToDeckEvent(turn int, phase string, player int, cardName string, reason string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventReturnToDeck,
+		Card:    cardName,
+		Details: fmt.Sprintf("%s is placed on top of %s's Deck (%s)", cardName, playerName(player), reason),
+	}
+}
+
+// NewReturnToDeckBottomEvent omits the card's name, like NewReturnToDeckTopEvent.
+func NewReturnToDeckBottomEvent(turn int, phase string, player int, reason string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventReturnToDeck,
+		Details: fmt.Sprintf("%s places a card from hand on the bottom of their Deck (%s)", playerName(player), reason),
+	}
+}
+
+// NewShuffleIntoDeckEvent records a card being returned to its owner's Deck to be shuffled in.
+func NewShuffleIntoDeckEvent(turn int, phase string, player int, cardName string, reason string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventReturnToDeck,
+		Card:    cardName,
+		Details: fmt.Sprintf("%s is shuffled into %s's Deck (%s)", cardName, playerName(player), reason),
+	}
+}
+
+// NewDeckVerifiedEvent records whether a player's cards matched their deck commitment.
+func NewDeckVerifiedEvent(turn int, player int, ok bool) GameEvent {
+	details := fmt.Sprintf("%s's deck matches its commitment", playerName(player))
+	if !ok {
+		details = fmt.Sprintf("%s's deck does NOT match its commitment", playerName(player))
+	}
+	return GameEvent{
+		Turn:    turn,
+		Player:  player,
+		Type:    EventDeckVerified,
+		Details: details,
+	}
+}
+
+// NewSkipDrawEvent records a player skipping their Draw Phase draw.
+func NewSkipDrawEvent(turn int, phase string, player int) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventSkipDraw,
+		Details: fmt.Sprintf("%s skips their draw", playerName(player)),
+	}
+}
+
+// NewRevealCardEvent records a player revealing one card, e.g. from the top of their deck.
+func NewRevealCardEvent(turn int, phase string, player int, cardName string, from string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventRevealCard,
+		Card:    cardName,
+		Details: fmt.Sprintf("%s reveals %s from %s", playerName(player), cardName, from),
+	}
+}
+
+// NewConcedeEvent records a player forfeiting the duel.
+func NewConcedeEvent(turn int, phase string, player int) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventConcede,
+		Details: fmt.Sprintf("%s concedes", playerName(player)),
+	}
+}
+
+// NewDamageBlockedEvent records battle damage a player did not take.
+func NewDamageBlockedEvent(turn int, phase string, player int, amount int, reason string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventDamageBlocked,
+		Details: fmt.Sprintf("%d damage to %s prevented (%s)", amount, playerName(player), reason),
+	}
+}
+
+// NewTraceEvent records a chain link as it starts to resolve.
+func NewTraceEvent(turn int, phase string, player int, cardName string, chainIndex int, negated bool, targets []string) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventTrace,
+		Card:    cardName,
+		Details: fmt.Sprintf("Trace: CL%d %s (%s) negated=%t targets=[%s]", chainIndex, cardName, playerName(player), negated, strings.Join(targets, ", ")),
+	}
+}
+
+// NewCoinTossEvent records the pre-game coin toss and its winner's choice.
+func NewCoinTossEvent(winner int, goFirst bool) GameEvent {
+	choice := "second"
+	if goFirst {
+		choice = "first"
+	}
+	return GameEvent{
+		Player:  winner,
+		Type:    EventCoinToss,
+		Details: fmt.Sprintf("%s wins the coin toss and chooses to go %s", playerName(winner), choice),
+	}
+}
+
+// NewMulliganEvent records a player revealing their opening hand to redraw it.
+func NewMulliganEvent(player int, revealed []string) GameEvent {
+	return GameEvent{
+		Player:  player,
+		Type:    EventMulligan,
+		Details: fmt.Sprintf("%s reveals a hand with no agents (%s) and draws a new hand", playerName(player), strings.Join(revealed, ", ")),
+	}
+}
+
+// NewUndoEvent records a player taking back their latest decision; the duel
+// returns to where that decision was asked.
+func NewUndoEvent(turn int, phase string, player int) GameEvent {
+	return GameEvent{
+		Turn:    turn,
+		Phase:   phase,
+		Player:  player,
+		Type:    EventUndo,
+		Details: fmt.Sprintf("<<< %s takes back their last decision >>>", playerName(player)),
+	}
+}
+
+// --- Hidden information ---
+
+// Spectator is the RedactFor viewer for someone watching rather than playing:
+// both players' hidden cards are redacted.
+const Spectator = -1
+
+// RedactFor returns events as viewer may see them: draws, Sets and cards added
+// to the other player's hand have the card replaced by "a card". Public events
+// are unchanged; the input slice is not modified.
+func RedactFor(events []GameEvent, viewer int) []GameEvent {
+	result := make([]GameEvent, len(events))
+	for i, e := range events {
+		switch e.Type {
+		case EventDraw, EventSetAgent, EventSetTech, EventAddToHand:
+			if e.Player != viewer && e.Card != "" {
+				e.Details = strings.ReplaceAll(e.Details, e.Card, "a card")
+				e.Card = ""
+			}
+		}
+		result[i] = e
+	}
+	return result
+}
+
+// --- Timeline ---
+
+// TurnSummary is one turn's major actions, in order: summons and Sets,
+// activations, attack declarations and phase changes.
+type TurnSummary struct {
+	Turn    int
+	Player  int // the turn player
+	Actions []GameEvent
+}
+
+// isTimelineAction reports whether events of type t appear on a timeline.
+func isTimelineAction(t EventType) bool {
+	switch t {
+	case EventPhaseChange, EventNormalSummon, EventSacrificeSummon, EventFlipSummon, EventSpecialSummon,
+		EventSetAgent, EventSetTech, EventActivate, EventAttackDeclare, EventDirectAttackDeclare:
+		return true
+	}
+	return false
+}
+
+// Timeline groups the major actions in events by turn. A turn that starts
+// again (after a rewind) replaces its earlier summary; events before the
+// first turn are left out.
+func Timeline(events []GameEvent) []TurnSummary {
+	var turns []TurnSummary
+	for _, e := range events {
+		if e.Type == EventNewTurn {
+			if n := len(turns); n > 0 && turns[n-1].Turn == e.Turn {
+				turns = turns[:n-1]
+			}
+			turns = append(turns, TurnSummary{Turn: e.Turn, Player: e.Player})
+			continue
+		}
+		if len(turns) > 0 && isTimelineAction(e.Type) {
+			last := &turns[len(turns)-1]
+			last.Actions = append(last.Actions, e)
+		}
+	}
+	return turns
+}
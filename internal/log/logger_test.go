@@ -0,0 +1,185 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestTextLoggerQuiet: a Quiet logger omits phase changes but keeps the result,
+// while still recording every event.
+func TestTextLoggerQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewTextLogger(&buf)
+	l.Level = LevelQuiet
+
+	l.Log(NewPhaseChangeEvent(1, "Main Phase 1"))
+	l.Log(NewWinEvent(1, "Battle Phase", 0, "HP reduced to 0"))
+
+	out := buf.String()
+	if strings.Contains(out, "Phase →") {
+		t.Errorf("Expected phase change omitted, got:\n%s", out)
+	}
+	if !strings.Contains(out, FormatEvent(l.LastEvent())) {
+		t.Errorf("Expected win event printed, got:\n%s", out)
+	}
+	if n := len(l.Events()); n != 2 {
+		t.Errorf("Expected both events recorded, got %d", n)
+	}
+}
+
+// TestTextLoggerDefaultLevel: a new TextLogger prints every event.
+func TestTextLoggerDefaultLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewTextLogger(&buf)
+
+	l.Log(NewPhaseChangeEvent(1, "Main Phase 1"))
+	if !strings.Contains(buf.String(), "Phase → Main Phase 1") {
+		t.Errorf("Expected phase change printed, got:\n%s", buf.String())
+	}
+}
+
+// TestJSONLogger: each event is written as one JSON line that decodes back to
+// the recorded event.
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSONLogger(&buf)
+	l.Log(NewPhaseChangeEvent(1, "Main Phase 1"))
+	l.Log(NewDrawEvent(1, "Draw Phase", 0, "Scout Drone"))
+	l.Log(NewWinEvent(3, "Battle Phase", 1, "HP reduced to 0"))
+	l.Log(NewCostPaidEvent(3, "Main Phase 1", 0, "Memory Corruption", CostHP, 1000))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := l.Events()
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(want), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var e jsonEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		et, err := ParseEventType(e.Type)
+		if err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		got := GameEvent{Seq: e.Seq, Turn: e.Turn, Phase: e.Phase, Player: e.Player, Type: et, Card: e.Card, Details: e.Details,
+			CostKind: e.CostKind, CostAmount: e.CostAmount}
+		if got != want[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i+1, want[i], got)
+		}
+	}
+}
+
+// TestEventTypeNames: every event type has its own name, and the name parses
+// back to the same type, in JSON too.
+func TestEventTypeNames(t *testing.T) {
+	seen := make(map[string]EventType)
+	for et := EventType(0); et < eventTypeCount; et++ {
+		name := et.String()
+		if name == "" || name == "Unknown" {
+			t.Errorf("EventType %d has no name", int(et))
+			continue
+		}
+		if prev, dup := seen[name]; dup {
+			t.Errorf("EventType %d and %d are both named %q", int(prev), int(et), name)
+		}
+		seen[name] = et
+		if got, err := ParseEventType(name); err != nil || got != et {
+			t.Errorf("ParseEventType(%q) = %d, %v; expected %d", name, int(got), err, int(et))
+		}
+	}
+	if _, err := ParseEventType("NoSuchEvent"); err == nil {
+		t.Error("Expected an unknown name to fail to parse")
+	}
+
+	data, err := json.Marshal(GameEvent{Type: EventDiscard})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"Type":"Discard"`) {
+		t.Errorf("Expected the type marshaled by name, got %s", data)
+	}
+	var e GameEvent
+	if err := json.Unmarshal(data, &e); err != nil || e.Type != EventDiscard {
+		t.Errorf("Expected Discard to round-trip, got %v (%v)", e.Type, err)
+	}
+}
+
+// TestRedactFor: P1 sees that P2 drew but not what; P2 sees their own draw,
+// and public events are left alone.
+func TestRedactFor(t *testing.T) {
+	events := []GameEvent{
+		NewDrawEvent(2, "Draw Phase", 1, "Scout Drone"),
+		NewAddToHandEvent(2, "Main Phase 1", 1, "Grid Link", "searched"),
+		NewDiscardEvent(2, "Main Phase 1", 1, "Scout Drone"),
+	}
+
+	p1 := RedactFor(events, 0)
+	if p1[0].Card != "" || strings.Contains(p1[0].Details, "Scout Drone") || !strings.Contains(p1[0].Details, "P2 draws a card") {
+		t.Errorf("Expected P2's draw hidden from P1, got %+v", p1[0])
+	}
+	if p1[1].Card != "" || strings.Contains(p1[1].Details, "Grid Link") {
+		t.Errorf("Expected P2's search hidden from P1, got %+v", p1[1])
+	}
+	if p1[2] != events[2] {
+		t.Errorf("Expected the discard to stay public, got %+v", p1[2])
+	}
+
+	p2 := RedactFor(events, 1)
+	for i := range events {
+		if p2[i] != events[i] {
+			t.Errorf("Expected P2 to see their own event %d unchanged, got %+v", i, p2[i])
+		}
+	}
+	if events[0].Card != "Scout Drone" {
+		t.Error("Expected the input events to be left unmodified")
+	}
+}
+
+// TestTimeline: a two-turn game gives one summary per turn, holding only its
+// major actions; a rewound turn is summarized as replayed.
+func TestTimeline(t *testing.T) {
+	events := []GameEvent{
+		NewCoinTossEvent(0, true),
+		NewTurnEvent(1, 0),
+		NewPhaseChangeEvent(1, "Draw Phase"),
+		NewDrawEvent(1, "Draw Phase", 0, "Scout Drone"),
+		NewPhaseChangeEvent(1, "Main Phase 1"),
+		NewActivateEvent(1, "Main Phase 1", 0, "Greed Protocol"),
+		NewRewindEvent(1, 0),
+		NewTurnEvent(1, 0),
+		NewPhaseChangeEvent(1, "Draw Phase"),
+		NewDrawEvent(1, "Draw Phase", 0, "Scout Drone"),
+		NewPhaseChangeEvent(1, "Main Phase 1"),
+		NewNormalSummonEvent(1, "Main Phase 1", 0, "Scout Drone", 1200, 0),
+		NewPhaseChangeEvent(1, "End Phase"),
+		NewTurnEvent(2, 1),
+		NewPhaseChangeEvent(2, "Draw Phase"),
+		NewDrawEvent(2, "Draw Phase", 1, "Void Drifter"),
+		NewPhaseChangeEvent(2, "Main Phase 1"),
+		NewNormalSummonEvent(2, "Main Phase 1", 1, "Void Drifter", 1900, 0),
+		NewPhaseChangeEvent(2, "Battle Phase"),
+		NewAttackDeclareEvent(2, 1, "Void Drifter", "Scout Drone"),
+		NewDamageCalcEvent(2, 1, "Void Drifter (1900) vs Scout Drone (1200)"),
+		NewPhaseChangeEvent(2, "End Phase"),
+	}
+
+	turns := Timeline(events)
+	if len(turns) != 2 {
+		t.Fatalf("Expected 2 turns, got %d: %+v", len(turns), turns)
+	}
+	for i, want := range []struct{ turn, player, actions int }{{1, 0, 4}, {2, 1, 6}} {
+		got := turns[i]
+		if got.Turn != want.turn || got.Player != want.player || len(got.Actions) != want.actions {
+			t.Errorf("Turn %d: expected P%d with %d actions, got P%d with %d: %+v",
+				want.turn, want.player+1, want.actions, got.Player+1, len(got.Actions), got.Actions)
+		}
+	}
+	for _, a := range turns[0].Actions {
+		if a.Type == EventActivate {
+			t.Error("Expected the rewound activation dropped from Turn 1")
+		}
+	}
+}
@@ -27,19 +27,19 @@ func NewMCPController(player int, session *GameSession) *MCPController {
 
 // ChooseAction implements game.PlayerController.
 func (c *MCPController) ChooseAction(ctx context.Context, state *game.GameState, actions []game.Action) (game.Action, error) {
-	var views []net.ActionView
-	for i, a := range actions {
-		views = append(views, net.ActionView{Index: i, Desc: a.String()})
-	}
-
 	c.session.pendingCh <- &PendingDecision{
 		Type:    DecisionChooseAction,
 		Player:  c.player,
 		State:   net.BuildStateView(state, c.player),
-		Actions: views,
+		Actions: net.BuildActionViews(actions),
+		actions: actions,
+		turn:    state.Turn,
 	}
 
 	resp := <-c.responseCh
+	if _, ok := resp.(UndoResponse); ok {
+		return game.Action{}, game.ErrUndoDecision
+	}
 	ar := resp.(ActionResponse)
 
 	if ar.Index < 0 || ar.Index >= len(actions) {
@@ -50,15 +50,7 @@ func (c *MCPController) ChooseAction(ctx context.Context, state *game.GameState,
 
 // ChooseCards implements game.PlayerController.
 func (c *MCPController) ChooseCards(ctx context.Context, state *game.GameState, prompt string, candidates []*game.CardInstance, min, max int) ([]*game.CardInstance, error) {
-	var views []net.CardView
-	for i, card := range candidates {
-		cv := net.CardView{Index: i, Name: card.Card.Name}
-		if card.Card.CardType == game.CardTypeAgent {
-			cv.ATK = card.CurrentATK()
-			cv.DEF = card.CurrentDEF()
-		}
-		views = append(views, cv)
-	}
+	views := net.BuildCardViews(candidates)
 
 	c.session.pendingCh <- &PendingDecision{
 		Type:       DecisionChooseCards,
@@ -68,6 +60,7 @@ func (c *MCPController) ChooseCards(ctx context.Context, state *game.GameState,
 		Candidates: views,
 		Min:        min,
 		Max:        max,
+		turn:       state.Turn,
 	}
 
 	resp := <-c.responseCh
@@ -89,6 +82,7 @@ func (c *MCPController) ChooseYesNo(ctx context.Context, state *game.GameState,
 		Player: c.player,
 		State:  net.BuildStateView(state, c.player),
 		Prompt: prompt,
+		turn:   state.Turn,
 	}
 
 	resp := <-c.responseCh
@@ -100,6 +94,7 @@ func (c *MCPController) ChooseYesNo(ctx context.Context, state *game.GameState,
 // Only the Claude controller appends events to avoid duplicates.
 func (c *MCPController) Notify(ctx context.Context, event log.GameEvent) error {
 	if c.player == c.session.claudePlayer {
+		event = log.RedactFor([]log.GameEvent{event}, c.player)[0]
 		c.session.appendEvent(net.EventView{
 			Turn:    event.Turn,
 			Phase:   event.Phase,
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+// ActionDescription spells out a pending action for describe_actions: what it
+// is, which card does it, what it targets, and a short rationale.
+type ActionDescription struct {
+	Index     int      `json:"index"`
+	Type      string   `json:"type"`
+	Card      string   `json:"card,omitempty"` // the acting card
+	ATK       int      `json:"atk,omitempty"`  // the acting agent's current ATK
+	Targets   []string `json:"targets,omitempty"`
+	Zone      int      `json:"zone"`
+	Rationale string   `json:"rationale"`
+}
+
+// describeActions describes actions as viewer sees them: the opponent's
+// face-down agents are named only as such.
+func describeActions(actions []game.Action, viewer int) []ActionDescription {
+	descs := make([]ActionDescription, len(actions))
+	for i, a := range actions {
+		d := ActionDescription{
+			Index:     i,
+			Type:      a.Type.String(),
+			Zone:      a.Zone,
+			Rationale: a.String(),
+		}
+		if a.Card != nil {
+			d.Card = a.Card.Card.Name
+			if a.Card.Card.CardType == game.CardTypeAgent {
+				d.ATK = a.Card.CurrentATK()
+			}
+		}
+		for _, t := range a.Targets {
+			d.Targets = append(d.Targets, displayName(t, viewer))
+		}
+
+		switch a.Type {
+		case game.ActionAttack:
+			if len(a.Targets) == 1 {
+				d.Rationale = fmt.Sprintf("%d ATK vs %s", d.ATK, battleTarget(a.Targets[0], viewer))
+			}
+		case game.ActionDirectAttack:
+			d.Rationale = fmt.Sprintf("%d ATK direct attack", d.ATK)
+		}
+		descs[i] = d
+	}
+	return descs
+}
+
+// displayName is a card's name, or "face-down agent" (or "card") if viewer
+// can't see it.
+func displayName(ci *game.CardInstance, viewer int) string {
+	if ci.Face != game.FaceDown || ci.Controller == viewer {
+		return ci.Card.Name
+	}
+	if ci.Zone == game.ZoneAgent {
+		return "face-down agent"
+	}
+	return "face-down card"
+}
+
+// battleTarget describes an attack target with the stat the attacker meets.
+func battleTarget(ci *game.CardInstance, viewer int) string {
+	name := displayName(ci, viewer)
+	switch {
+	case ci.Face == game.FaceDown && ci.Controller != viewer:
+		return name
+	case ci.Position == game.PositionDEF:
+		return fmt.Sprintf("%s (%d DEF)", name, ci.CurrentDEF())
+	default:
+		return fmt.Sprintf("%s (%d ATK)", name, ci.CurrentATK())
+	}
+}
@@ -34,6 +34,9 @@ type PendingDecision struct {
 	Candidates []tcgxnet.CardView   `json:"candidates,omitempty"`
 	Min        int                  `json:"min,omitempty"`
 	Max        int                  `json:"max,omitempty"`
+
+	actions []game.Action // for describe_actions
+	turn    int           // the turn it was asked on, for undo_last
 }
 
 // Response types sent back from MCP tools to controllers.
@@ -50,15 +53,21 @@ type YesNoResponse struct {
 	Answer bool
 }
 
+// UndoResponse answers a pending action choice by taking back Claude's
+// previous decision instead.
+type UndoResponse struct{}
+
 // ToolResponse is the JSON envelope returned by all MCP tools.
 type ToolResponse struct {
-	Events   []tcgxnet.EventView `json:"events"`
-	State    *tcgxnet.StateView  `json:"state,omitempty"`
-	Pending  *PendingView        `json:"pending,omitempty"`
-	GameOver bool                `json:"game_over"`
-	Winner   int                 `json:"winner,omitempty"`
-	Result   string              `json:"result,omitempty"`
-	Port     string              `json:"port,omitempty"`
+	Events    []tcgxnet.EventView `json:"events"`
+	State     *tcgxnet.StateView  `json:"state,omitempty"`
+	Pending   *PendingView        `json:"pending,omitempty"`
+	GameOver  bool                `json:"game_over"`
+	Winner    int                 `json:"winner,omitempty"`
+	Result    string              `json:"result,omitempty"`
+	WinReason string              `json:"win_reason,omitempty"`
+	Port      string              `json:"port,omitempty"`
+	Seed      int64               `json:"seed,omitempty"`
 }
 
 // PendingView is the pending decision as presented in the tool response JSON.
@@ -84,22 +93,31 @@ type GameSession struct {
 
 	pendingCh      chan *PendingDecision
 	currentPending *PendingDecision
-
-	mu       sync.Mutex
-	events   []tcgxnet.EventView
-	gameOver bool
-	winner   int
-	result   string
+	lastAnswered   *PendingDecision // Claude's latest decision, for undo_last
+
+	mu        sync.Mutex
+	events    []tcgxnet.EventView
+	gameOver  bool
+	winner    int
+	result    string
+	winReason string
 }
 
 // NewGameSession creates a new game session. It starts a TCP listener,
 // waits for the human player to connect via `tcgx join`, then starts the duel.
-func NewGameSession(decksFile string, claudeDeck, claudePlayer int, port string) (*GameSession, error) {
-	claudeDeckName, claudeCards, err := game.DeckByNumber(decksFile, claudeDeck)
+// A seed of 0 picks a random one; the seed in effect is available via Seed.
+func NewGameSession(decksFile string, claudeDeck tcgxnet.DeckSource, claudePlayer int, port string, seed int64) (*GameSession, error) {
+	banlist, err := game.DeckFileBanlist(decksFile)
+	if err != nil {
+		return nil, fmt.Errorf("load banlist: %w", err)
+	}
+	claudeDeckName, claudeCards, err := claudeDeck.Load(decksFile)
 	if err != nil {
 		return nil, fmt.Errorf("load claude deck: %w", err)
 	}
-	_ = claudeDeckName
+	if err := game.ValidateDeckWithBanlist(claudeCards, banlist); err != nil {
+		return nil, fmt.Errorf("claude deck %q: %w", claudeDeckName, err)
+	}
 
 	// Start TCP listener for human player
 	ln, err := stdnet.Listen("tcp", ":"+port)
@@ -122,19 +140,23 @@ func NewGameSession(decksFile string, claudeDeck, claudePlayer int, port string)
 		ln.Close()
 		return nil, fmt.Errorf("read join message: %w", err)
 	}
-	humanDeck := joinMsg.DeckNumber
-	if humanDeck == 0 {
-		humanDeck = 2
-	}
-
-	humanDeckName, humanCards, err := game.DeckByNumber(decksFile, humanDeck)
+	humanDeckName, humanCards, err := tcgxnet.DeckSourceFromJoin(joinMsg).Load(decksFile)
 	if err != nil {
 		conn.Close()
 		ln.Close()
 		return nil, fmt.Errorf("load human deck: %w", err)
 	}
-	_ = humanDeckName
+	if err := game.ValidateDeckWithBanlist(humanCards, banlist); err != nil {
+		conn.Close()
+		ln.Close()
+		return nil, fmt.Errorf("human deck %q: %w", humanDeckName, err)
+	}
 
+	return startSession(ln, conn, claudeCards, humanCards, claudePlayer, seed), nil
+}
+
+// startSession starts the duel between Claude and the human connected on conn.
+func startSession(ln stdnet.Listener, conn stdnet.Conn, claudeCards, humanCards []*game.Card, claudePlayer int, seed int64) *GameSession {
 	sess := &GameSession{
 		claudePlayer: claudePlayer,
 		pendingCh:    make(chan *PendingDecision, 1),
@@ -166,6 +188,7 @@ func NewGameSession(decksFile string, claudeDeck, claudePlayer int, port string)
 		Deck0:  deck0,
 		Deck1:  deck1,
 		Logger: log.NewMemoryLogger(),
+		Seed:   seed,
 	}
 
 	sess.duel = game.NewDuel(cfg, ctrl0, ctrl1)
@@ -186,7 +209,8 @@ func NewGameSession(decksFile string, claudeDeck, claudePlayer int, port string)
 		}
 
 		// Notify human over TCP
-		_ = sess.humanCtrl.SendGameOver(winner, result)
+		reason := sess.duel.State.WinReason
+		_ = sess.humanCtrl.SendGameOver(winner, result, reason)
 
 		// Clean up TCP resources
 		sess.humanConn.Close()
@@ -203,10 +227,16 @@ func NewGameSession(decksFile string, claudeDeck, claudePlayer int, port string)
 		sess.gameOver = true
 		sess.winner = winner
 		sess.result = result
+		sess.winReason = reason.String()
 		sess.mu.Unlock()
 	}()
 
-	return sess, nil
+	return sess
+}
+
+// Seed returns the duel's RNG seed, for reproducing the game later.
+func (s *GameSession) Seed() int64 {
+	return s.duel.Seed()
 }
 
 // appendEvent adds an event to the session's event log. Thread-safe.
@@ -242,6 +272,7 @@ func (s *GameSession) waitForPending() (*ToolResponse, error) {
 		resp.GameOver = true
 		resp.Winner = s.winner
 		resp.Result = s.result
+		resp.WinReason = s.winReason
 		s.mu.Unlock()
 		resp.State = pending.State
 		resp.Pending = nil
@@ -0,0 +1,313 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	stdnet "net"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/peterkuimelis/tcgx/internal/game"
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// testDeck returns a 40-card deck of distinct cards, so shuffles show in the opening hand.
+func testDeck() []*game.Card {
+	names := []string{"Greed Protocol", "Void Purge", "EMP Cascade", "ICE Breaker", "Blackout Patch",
+		"Reactive Plating", "Reflector Array", "Cascade Failure"}
+	var deck []*game.Card
+	for i := 0; i < 5; i++ {
+		for _, name := range names {
+			deck = append(deck, game.LookupCard(name))
+		}
+	}
+	return deck
+}
+
+// startTestSession starts a session with Claude as player 0 and a human
+// client on loopback that reads and discards everything sent to it.
+func startTestSession(t *testing.T, seed int64) *GameSession {
+	t.Helper()
+	ln, err := stdnet.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	client, err := stdnet.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	go io.Copy(io.Discard, client)
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	return startSession(ln, conn, testDeck(), testDeck(), 0, seed)
+}
+
+func TestSameSeedSameOpeningState(t *testing.T) {
+	var states [2]string
+	for i := range states {
+		sess := startTestSession(t, 42)
+		if seed := sess.Seed(); seed != 42 {
+			t.Fatalf("Expected seed 42, got %d", seed)
+		}
+		resp, err := sess.waitForPending()
+		if err != nil {
+			t.Fatalf("waitForPending: %v", err)
+		}
+		data, err := json.Marshal(resp.State)
+		if err != nil {
+			t.Fatalf("marshal state: %v", err)
+		}
+		states[i] = string(data)
+	}
+	if states[0] != states[1] {
+		t.Errorf("Expected identical opening states for the same seed:\n%s\n%s", states[0], states[1])
+	}
+}
+
+// TestDescribeActions: in a Battle Phase decision each attack carries the
+// attacker's current ATK and the target's displayed name, and describing the
+// actions leaves the decision pending.
+func TestDescribeActions(t *testing.T) {
+	gs := game.NewGameState()
+	attacker := gs.CreateCardInstance(game.LookupCard("Void Drifter"), 0)
+	attacker.Face, attacker.Position = game.FaceUp, game.PositionATK
+	attacker.AddModifier(game.StatModifier{ATKMod: 300})
+	gs.Players[0].PlaceAgent(attacker, 0)
+
+	hidden := gs.CreateCardInstance(game.LookupCard("Mimic Shell"), 1)
+	hidden.Face, hidden.Position = game.FaceDown, game.PositionDEF
+	gs.Players[1].PlaceAgent(hidden, 0)
+	wall := gs.CreateCardInstance(game.LookupCard("Mimic Shell"), 1)
+	wall.Face, wall.Position = game.FaceUp, game.PositionDEF
+	gs.Players[1].PlaceAgent(wall, 1)
+
+	pending := &PendingDecision{
+		Type:   DecisionChooseAction,
+		Player: 0,
+		actions: []game.Action{
+			{Type: game.ActionAttack, Player: 0, Card: attacker, Targets: []*game.CardInstance{hidden}},
+			{Type: game.ActionAttack, Player: 0, Card: attacker, Targets: []*game.CardInstance{wall}},
+			{Type: game.ActionEndBattlePhase, Player: 0},
+		},
+	}
+	activeSession = &GameSession{claudePlayer: 0, currentPending: pending}
+	t.Cleanup(func() { activeSession = nil })
+
+	res, err := handleDescribeActions(context.Background(), mcp.CallToolRequest{})
+	if err != nil || res.IsError {
+		t.Fatalf("describe_actions failed: %v %+v", err, res)
+	}
+	var descs []ActionDescription
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &descs); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	atk := attacker.CurrentATK()
+	want := []struct {
+		target, rationale string
+	}{
+		{"face-down agent", fmt.Sprintf("%d ATK vs face-down agent", atk)},
+		{"Mimic Shell", fmt.Sprintf("%d ATK vs Mimic Shell (1000 DEF)", atk)},
+	}
+	for i, w := range want {
+		d := descs[i]
+		if d.Type != "Attack" || d.Card != "Void Drifter" || d.ATK != atk {
+			t.Errorf("action %d: expected an attack by Void Drifter at %d ATK, got %+v", i, atk, d)
+		}
+		if len(d.Targets) != 1 || d.Targets[0] != w.target || d.Rationale != w.rationale {
+			t.Errorf("action %d: expected target %q (%q), got %v (%q)", i, w.target, w.rationale, d.Targets, d.Rationale)
+		}
+	}
+	if activeSession.currentPending != pending {
+		t.Error("Expected the decision to stay pending")
+	}
+}
+
+// playController plays the named cards in order whenever one is playable and
+// otherwise passes or ends the turn.
+type playController struct {
+	plays []string
+}
+
+func (c *playController) ChooseAction(ctx context.Context, state *game.GameState, actions []game.Action) (game.Action, error) {
+	if len(c.plays) > 0 {
+		for _, a := range actions {
+			if a.Card != nil && a.Card.Card.Name == c.plays[0] &&
+				(a.Type == game.ActionNormalSummon || a.Type == game.ActionActivate) {
+				c.plays = c.plays[1:]
+				return a, nil
+			}
+		}
+	}
+	for _, t := range []game.ActionType{game.ActionPass, game.ActionEndTurn, game.ActionEnterMainPhase2} {
+		for _, a := range actions {
+			if a.Type == t {
+				return a, nil
+			}
+		}
+	}
+	return actions[len(actions)-1], nil
+}
+
+func (c *playController) ChooseCards(ctx context.Context, state *game.GameState, prompt string, candidates []*game.CardInstance, min, max int) ([]*game.CardInstance, error) {
+	return candidates[:min], nil
+}
+
+func (c *playController) ChooseYesNo(ctx context.Context, state *game.GameState, prompt string) (bool, error) {
+	return false, nil
+}
+
+func (c *playController) Notify(ctx context.Context, event log.GameEvent) error { return nil }
+
+// TestGetZone: after Void Purge the scrapheap lists the destroyed agents in
+// the order they went there, and the opponent's hand stays hidden.
+func TestGetZone(t *testing.T) {
+	// The end of the deck slice is its top.
+	deck := func(top ...string) []*game.Card {
+		var cards []*game.Card
+		for len(cards) < game.MinDeckSize-len(top) {
+			cards = append(cards, game.LookupCard("Prismatic Datafish"))
+		}
+		for i := len(top) - 1; i >= 0; i-- {
+			cards = append(cards, game.LookupCard(top[i]))
+		}
+		return cards
+	}
+	duel := game.NewDuel(game.DuelConfig{
+		Deck0:     deck("Void Drifter", "Steel Juggernaut", "Void Purge"),
+		Deck1:     deck("Blazing Automaton"),
+		NoShuffle: true,
+		MaxTurns:  3,
+	},
+		&playController{plays: []string{"Void Drifter", "Steel Juggernaut", "Void Purge"}},
+		&playController{plays: []string{"Blazing Automaton"}})
+	if _, err := duel.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	activeSession = &GameSession{duel: duel, claudePlayer: 0}
+	t.Cleanup(func() { activeSession = nil })
+
+	getZone := func(player, zone string) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"player": player, "zone": zone}
+		res, err := handleGetZone(context.Background(), req)
+		if err != nil {
+			t.Fatalf("get_zone %s %s: %v", player, zone, err)
+		}
+		return res
+	}
+	names := func(res *mcp.CallToolResult) []string {
+		var views []struct{ Name string }
+		if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &views); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		var out []string
+		for _, v := range views {
+			out = append(out, v.Name)
+		}
+		return out
+	}
+
+	if got, want := names(getZone("you", "scrapheap")), []string{"Void Drifter", "Steel Juggernaut", "Void Purge"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected your scrapheap %v, got %v", want, got)
+	}
+	if got, want := names(getZone("opponent", "scrapheap")), []string{"Blazing Automaton"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the opponent's scrapheap %v, got %v", want, got)
+	}
+	if got := names(getZone("you", "purged")); len(got) != 0 {
+		t.Errorf("Expected an empty purged zone, got %v", got)
+	}
+	if res := getZone("opponent", "hand"); !res.IsError {
+		t.Error("Expected the opponent's hand to be hidden")
+	}
+}
+
+// TestUndoLast: undoing an accidental direct attack puts the attacker back
+// as it was and asks the same Battle Phase decision again.
+func TestUndoLast(t *testing.T) {
+	deck := func(top ...string) []*game.Card {
+		var cards []*game.Card
+		for len(cards) < game.MinDeckSize-len(top) {
+			cards = append(cards, game.LookupCard("Prismatic Datafish"))
+		}
+		for i := len(top) - 1; i >= 0; i-- {
+			cards = append(cards, game.LookupCard(top[i]))
+		}
+		return cards
+	}
+	sess := &GameSession{claudePlayer: 0, pendingCh: make(chan *PendingDecision, 1), winner: -1}
+	sess.claudeCtrl = NewMCPController(0, sess)
+	sess.duel = game.NewDuel(game.DuelConfig{
+		Deck0:     deck("Void Drifter"),
+		Deck1:     deck(),
+		NoShuffle: true,
+		MaxTurns:  4,
+	}, sess.claudeCtrl, &playController{})
+	go sess.duel.Run(context.Background())
+	activeSession = sess
+	t.Cleanup(func() { activeSession = nil })
+
+	if _, err := sess.waitForPending(); err != nil {
+		t.Fatalf("waitForPending: %v", err)
+	}
+	// take answers the pending action choice with the first action of type at
+	// (for card, if given).
+	take := func(at game.ActionType, card string) {
+		t.Helper()
+		for i, a := range sess.currentPending.actions {
+			if a.Type == at && (card == "" || a.Card != nil && a.Card.Card.Name == card) {
+				req := mcp.CallToolRequest{}
+				req.Params.Arguments = map[string]any{"index": i}
+				if res, err := handleTakeAction(context.Background(), req); err != nil || res.IsError {
+					t.Fatalf("take_action %d: %v %+v", i, err, res)
+				}
+				return
+			}
+		}
+		t.Fatalf("Turn %d: no %s action for %q among %+v", sess.duel.State.Turn, at, card, sess.currentPending.Actions)
+	}
+	undo := func() *mcp.CallToolResult {
+		res, err := handleUndoLast(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatalf("undo_last: %v", err)
+		}
+		return res
+	}
+
+	// Turn 1: summon Void Drifter; Turn 3: attack directly with it
+	take(game.ActionNormalSummon, "Void Drifter")
+	take(game.ActionEndTurn, "")
+	if res := undo(); !res.IsError {
+		t.Error("Expected undo across the opponent's turn to be rejected")
+	}
+	take(game.ActionEnterBattlePhase, "")
+	battle := sess.currentPending
+	take(game.ActionDirectAttack, "Void Drifter")
+	if !sess.duel.State.Players[0].Agents()[0].AttackedThisTurn {
+		t.Fatal("Expected Void Drifter to have attacked")
+	}
+
+	if res := undo(); res.IsError {
+		t.Fatalf("undo_last failed: %+v", res)
+	}
+	drifter := sess.duel.State.Players[0].Agents()[0]
+	if drifter.AttackedThisTurn {
+		t.Error("Expected Void Drifter not to have attacked after the undo")
+	}
+	before, _ := json.Marshal(battle.Actions)
+	after, _ := json.Marshal(sess.currentPending.Actions)
+	if sess.currentPending.Type != DecisionChooseAction || string(before) != string(after) {
+		t.Errorf("Expected the Battle Phase decision again:\n%s\n%s", before, after)
+	}
+	if res := undo(); !res.IsError {
+		t.Error("Expected a second undo with nothing new to take back to be rejected")
+	}
+}
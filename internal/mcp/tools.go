@@ -2,6 +2,8 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -36,6 +38,10 @@ func RegisterTools(s *server.MCPServer) {
 	s.AddTool(selectCardsTool(), handleSelectCards)
 	s.AddTool(answerYesNoTool(), handleAnswerYesNo)
 	s.AddTool(getGameStateTool(), handleGetGameState)
+	s.AddTool(describeActionsTool(), handleDescribeActions)
+	s.AddTool(getZoneTool(), handleGetZone)
+	s.AddTool(undoLastTool(), handleUndoLast)
+	s.AddTool(getSeedTool(), handleGetSeed)
 }
 
 // --- Tool definitions ---
@@ -47,12 +53,14 @@ func startGameTool() mcp.Tool {
 			"This call blocks until the human connects."),
 		mcp.WithNumber("claude_deck", mcp.Required(), mcp.Description("Deck number for Claude (1-indexed from decks.yaml)")),
 		mcp.WithNumber("claude_player", mcp.Required(), mcp.Description("Which player Claude is: 0 = goes first, 1 = goes second")),
+		mcp.WithString("claude_decks_file", mcp.Description("Optional decks file for Claude's deck (defaults to the server's decks file)")),
+		mcp.WithNumber("seed", mcp.Description("Optional RNG seed; the same seed and decks reproduce the same shuffles (0 or omitted = random)")),
 	)
 }
 
 func takeActionTool() mcp.Tool {
 	return mcp.NewTool("take_action",
-		mcp.WithDescription("Choose an action from the pending action list. Use this when the pending decision type is 'choose_action'."),
+		mcp.WithDescription("Choose an action from the pending action list. Use this when the pending decision type is 'choose_action'. Main and Battle Phase lists end with a 'Concede the duel' action that forfeits immediately."),
 		mcp.WithNumber("index", mcp.Required(), mcp.Description("0-based index of the action to take from the actions list")),
 	)
 }
@@ -77,6 +85,35 @@ func getGameStateTool() mcp.Tool {
 	)
 }
 
+func describeActionsTool() mcp.Tool {
+	return mcp.NewTool("describe_actions",
+		mcp.WithDescription("Describe each pending action in detail: its type, the acting card and its current ATK, its targets, its zone, and a short rationale such as '1900 ATK vs face-down agent'. "+
+			"Use this when the pending decision type is 'choose_action'. Read-only: the decision stays pending."),
+	)
+}
+
+func getZoneTool() mcp.Tool {
+	return mcp.NewTool("get_zone",
+		mcp.WithDescription("List the cards in a player's scrapheap, purged zone, or hand, oldest first, with each agent's ATK and DEF. "+
+			"Only your own hand can be listed. Read-only."),
+		mcp.WithString("player", mcp.Required(), mcp.Description("Whose zone: 'you' or 'opponent'")),
+		mcp.WithString("zone", mcp.Required(), mcp.Description("'scrapheap', 'purged', or 'hand' (yours only)")),
+	)
+}
+
+func undoLastTool() mcp.Tool {
+	return mcp.NewTool("undo_last",
+		mcp.WithDescription("Take back your most recent decision and get it back as the pending decision. "+
+			"Only available while you are choosing an action, and only if your opponent hasn't decided anything and no randomness (shuffles, coin flips) has been used since."),
+	)
+}
+
+func getSeedTool() mcp.Tool {
+	return mcp.NewTool("get_seed",
+		mcp.WithDescription("Get the RNG seed of the running game. Pass it to start_game to replay the same shuffles. Read-only."),
+	)
+}
+
 // --- Tool handlers ---
 
 func handleStartGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -86,6 +123,8 @@ func handleStartGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.Cal
 
 	claudeDeck := request.GetInt("claude_deck", 0)
 	claudePlayer := request.GetInt("claude_player", 0)
+	claudeDecksFile := request.GetString("claude_decks_file", "")
+	seed := int64(request.GetInt("seed", 0))
 
 	if claudeDeck < 1 {
 		return mcp.NewToolResultError("claude_deck must be >= 1"), nil
@@ -94,7 +133,8 @@ func handleStartGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.Cal
 		return mcp.NewToolResultError("claude_player must be 0 or 1"), nil
 	}
 
-	sess, err := NewGameSession(decksFile, claudeDeck, claudePlayer, port)
+	claudeSource := tcgxnet.DeckSource{File: claudeDecksFile, Number: claudeDeck}
+	sess, err := NewGameSession(decksFile, claudeSource, claudePlayer, port, seed)
 	if err != nil {
 		return mcp.NewToolResultErrorf("Failed to start game: %v", err), nil
 	}
@@ -107,6 +147,7 @@ func handleStartGame(ctx context.Context, request mcp.CallToolRequest) (*mcp.Cal
 	}
 
 	resp.Port = port
+	resp.Seed = sess.Seed()
 
 	return mcp.NewToolResultText(respondJSON(resp)), nil
 }
@@ -134,6 +175,7 @@ func handleTakeAction(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 	}
 
 	sess.claudeCtrl.responseCh <- ActionResponse{Index: index}
+	sess.lastAnswered = pending
 
 	resp, err := sess.waitForPending()
 	if err != nil {
@@ -188,6 +230,7 @@ func handleSelectCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 	}
 
 	sess.claudeCtrl.responseCh <- CardsResponse{Indices: indices}
+	sess.lastAnswered = pending
 
 	resp, err := sess.waitForPending()
 	if err != nil {
@@ -221,6 +264,7 @@ func handleAnswerYesNo(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 	answer := request.GetBool("answer", false)
 
 	sess.claudeCtrl.responseCh <- YesNoResponse{Answer: answer}
+	sess.lastAnswered = pending
 
 	resp, err := sess.waitForPending()
 	if err != nil {
@@ -246,13 +290,15 @@ func handleGetGameState(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 	gameOver := sess.gameOver
 	winner := sess.winner
 	result := sess.result
+	winReason := sess.winReason
 	sess.mu.Unlock()
 
 	resp := &ToolResponse{
-		Events:   events,
-		GameOver: gameOver,
-		Winner:   winner,
-		Result:   result,
+		Events:    events,
+		GameOver:  gameOver,
+		Winner:    winner,
+		Result:    result,
+		WinReason: winReason,
 	}
 
 	if gameOver {
@@ -289,3 +335,92 @@ func handleGetGameState(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 
 	return mcp.NewToolResultText(respondJSON(resp)), nil
 }
+
+func handleDescribeActions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if activeSession == nil {
+		return mcp.NewToolResultError("No game is running. Use start_game first."), nil
+	}
+
+	sess := activeSession
+	pending := sess.currentPending
+	if pending == nil {
+		return mcp.NewToolResultError("No pending decision."), nil
+	}
+	if pending.Player != sess.claudePlayer {
+		return mcp.NewToolResultError("Waiting for human player to respond via their terminal."), nil
+	}
+	if pending.Type != DecisionChooseAction {
+		return mcp.NewToolResultErrorf("No actions to describe: pending decision is '%s', not 'choose_action'.", pending.Type), nil
+	}
+
+	data, err := json.Marshal(describeActions(pending.actions, sess.claudePlayer))
+	if err != nil {
+		return mcp.NewToolResultErrorf("marshal error: %v", err), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleGetZone(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if activeSession == nil {
+		return mcp.NewToolResultError("No game is running. Use start_game first."), nil
+	}
+
+	sess := activeSession
+	if sess.duel == nil {
+		return mcp.NewToolResultError("The duel has not started yet."), nil
+	}
+
+	views, err := zoneCards(sess.duel.State, sess.claudePlayer, request.GetString("player", ""), request.GetString("zone", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	data, err := json.Marshal(views)
+	if err != nil {
+		return mcp.NewToolResultErrorf("marshal error: %v", err), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleUndoLast(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if activeSession == nil {
+		return mcp.NewToolResultError("No game is running. Use start_game first."), nil
+	}
+
+	sess := activeSession
+	pending := sess.currentPending
+	if pending == nil {
+		return mcp.NewToolResultError("No pending decision."), nil
+	}
+	if pending.Player != sess.claudePlayer {
+		return mcp.NewToolResultError("Waiting for human player to respond via their terminal."), nil
+	}
+	if pending.Type != DecisionChooseAction {
+		return mcp.NewToolResultErrorf("Can't undo now: pending decision is '%s', not 'choose_action'.", pending.Type), nil
+	}
+	last := sess.lastAnswered
+	if last == nil {
+		return mcp.NewToolResultError("Nothing to undo."), nil
+	}
+	if last.turn != sess.duel.State.Turn {
+		return mcp.NewToolResultErrorf("Can't undo across turns: your last decision was on Turn %d.", last.turn), nil
+	}
+	if err := sess.duel.CanUndo(sess.claudePlayer); err != nil {
+		return mcp.NewToolResultErrorf("Can't undo: %v.", err), nil
+	}
+
+	sess.claudeCtrl.responseCh <- UndoResponse{}
+	sess.lastAnswered = nil
+
+	resp, err := sess.waitForPending()
+	if err != nil {
+		return mcp.NewToolResultErrorf("Error waiting for next decision: %v", err), nil
+	}
+	return mcp.NewToolResultText(respondJSON(resp)), nil
+}
+
+func handleGetSeed(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if activeSession == nil {
+		return mcp.NewToolResultError("No game is running. Use start_game first."), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf(`{"seed": %d}`, activeSession.Seed())), nil
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+	tcgxnet "github.com/peterkuimelis/tcgx/internal/net"
+)
+
+// zoneCards lists a player's scrapheap, purged zone, or hand as viewer may see
+// it, oldest card first. side is "you" or "opponent", relative to viewer; the
+// opponent's hand is hidden and can't be listed.
+func zoneCards(state *game.GameState, viewer int, side, zone string) ([]tcgxnet.CardView, error) {
+	var player int
+	switch side {
+	case "you":
+		player = viewer
+	case "opponent":
+		player = 1 - viewer
+	default:
+		return nil, fmt.Errorf("player must be 'you' or 'opponent', got %q", side)
+	}
+
+	p := state.Players[player]
+	var cards []*game.CardInstance
+	switch zone {
+	case "scrapheap":
+		cards = p.Scrapheap
+	case "purged":
+		cards = p.Purged
+	case "hand":
+		if player != viewer {
+			return nil, fmt.Errorf("the opponent's hand is hidden")
+		}
+		cards = p.Hand
+	default:
+		return nil, fmt.Errorf("zone must be 'scrapheap', 'purged' or 'hand', got %q", zone)
+	}
+
+	views := tcgxnet.BuildCardViews(cards)
+	if views == nil {
+		views = []tcgxnet.CardView{}
+	}
+	return views, nil
+}
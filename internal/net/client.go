@@ -9,16 +9,25 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/peterkuimelis/tcgx/internal/log"
 )
 
 // Client connects to a game server and provides a terminal REPL.
 type Client struct {
 	conn       net.Conn
 	playerName string // "P1" or "P2"
+	spectating bool   // watching only; the server never prompts
 }
 
 // Connect connects to a server, sends the deck choice, and runs the REPL.
-func Connect(ctx context.Context, addr string, deckNumber int) error {
+// A deck source naming a local decks file is sent to the server inline.
+func Connect(ctx context.Context, addr string, deck DeckSource) error {
+	joinMsg, err := deck.joinMessage()
+	if err != nil {
+		return err
+	}
+
 	conn, err := net.Dial("tcp", addr)
 	if err != nil {
 		return fmt.Errorf("connect: %w", err)
@@ -27,7 +36,7 @@ func Connect(ctx context.Context, addr string, deckNumber int) error {
 
 	// Send join message with deck choice
 	enc := json.NewEncoder(conn)
-	if err := enc.Encode(ClientMessage{Type: "join", DeckNumber: deckNumber}); err != nil {
+	if err := enc.Encode(joinMsg); err != nil {
 		return fmt.Errorf("send join: %w", err)
 	}
 
@@ -37,6 +46,44 @@ func Connect(ctx context.Context, addr string, deckNumber int) error {
 	return client.RunREPL(ctx)
 }
 
+// Reconnect rejoins a duel after the connection dropped, using the session
+// token the server sent on joining. The pending prompt, if any, is resent.
+func Reconnect(ctx context.Context, addr, token string) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(ClientMessage{Type: "reconnect", Token: token}); err != nil {
+		return fmt.Errorf("send reconnect: %w", err)
+	}
+
+	fmt.Println("Reconnected! Waiting for the duel to resume...")
+
+	client := &Client{conn: conn, playerName: "P2"}
+	return client.RunREPL(ctx)
+}
+
+// Spectate connects to a server as a spectator and prints the duel as it is
+// played. Spectators see what both players can see, and are never prompted.
+func Spectate(ctx context.Context, addr string) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(ClientMessage{Type: "spectate"}); err != nil {
+		return fmt.Errorf("send spectate: %w", err)
+	}
+
+	fmt.Println("Connected as a spectator.")
+
+	client := &Client{conn: conn, spectating: true}
+	return client.RunREPL(ctx)
+}
+
 // RunREPL reads server messages and handles them interactively.
 func (c *Client) RunREPL(ctx context.Context) error {
 	dec := json.NewDecoder(c.conn)
@@ -52,12 +99,31 @@ func (c *Client) RunREPL(ctx context.Context) error {
 		switch msg.Type {
 		case "notify":
 			c.renderEvent(msg.Event)
+			// Spectators get no prompts, so show the board at each phase
+			if c.spectating && msg.Event != nil && msg.Event.Type == log.EventPhaseChange.String() {
+				c.renderState(msg.State)
+			}
+
+		case "state":
+			c.renderState(msg.State)
+
+		case "session":
+			fmt.Printf("Session token: %s (rejoin with --token if you get disconnected)\n", msg.Token)
 
 		case "choose_action":
 			c.renderState(msg.State)
 			c.renderActions(msg.Actions)
-			idx := c.readChoice(reader, len(msg.Actions))
-			if err := enc.Encode(ClientMessage{Type: "action", Index: idx}); err != nil {
+			if msg.CanRewind {
+				fmt.Println("  (type 'rewind' to undo to the start of this turn)")
+			}
+			idx := c.readChoice(reader, len(msg.Actions), msg.CanRewind)
+			if idx < 0 {
+				if err := enc.Encode(ClientMessage{Type: "rewind", Seq: msg.Seq}); err != nil {
+					return fmt.Errorf("send rewind: %w", err)
+				}
+				continue
+			}
+			if err := enc.Encode(ClientMessage{Type: "action", Seq: msg.Seq, Index: idx}); err != nil {
 				return fmt.Errorf("send action: %w", err)
 			}
 
@@ -67,14 +133,14 @@ func (c *Client) RunREPL(ctx context.Context) error {
 			}
 			c.renderCardChoice(msg.Prompt, msg.Candidates, msg.Min, msg.Max)
 			indices := c.readCardIndices(reader, len(msg.Candidates), msg.Min, msg.Max)
-			if err := enc.Encode(ClientMessage{Type: "cards", Indices: indices}); err != nil {
+			if err := enc.Encode(ClientMessage{Type: "cards", Seq: msg.Seq, Indices: indices}); err != nil {
 				return fmt.Errorf("send cards: %w", err)
 			}
 
 		case "choose_yes_no":
 			fmt.Printf("\n%s (y/n): ", msg.Prompt)
 			answer := c.readYesNo(reader)
-			if err := enc.Encode(ClientMessage{Type: "yes_no", Answer: answer}); err != nil {
+			if err := enc.Encode(ClientMessage{Type: "yes_no", Seq: msg.Seq, Answer: answer}); err != nil {
 				return fmt.Errorf("send yes_no: %w", err)
 			}
 
@@ -85,6 +151,10 @@ func (c *Client) RunREPL(ctx context.Context) error {
 			fmt.Println("═══════════════════════════════════")
 			fmt.Println(msg.Result)
 			fmt.Println("═══════════════════════════════════")
+			if msg.NextGame {
+				fmt.Printf("Match score: P1 %d – %d P2. Next game...\n", msg.Score[0], msg.Score[1])
+				continue
+			}
 			return nil
 		}
 	}
@@ -105,18 +175,32 @@ func (c *Client) renderEvent(ev *EventView) {
 	fmt.Printf("T%-2d %s| %s\n", ev.Turn, phase, ev.Details)
 }
 
+// player returns the client's player index.
+func (c *Client) player() int {
+	if c.playerName == "P2" {
+		return 1
+	}
+	return 0
+}
+
 func (c *Client) renderState(sv *StateView) {
 	if sv == nil {
 		return
 	}
 
+	// A spectator's view has P1 as "you" and P2 as the opponent
+	youLabel, oppLabel := "YOU", "OPPONENT"
+	if c.spectating {
+		youLabel, oppLabel = "P1", "P2"
+	}
+
 	fmt.Println()
 	fmt.Println("╔══════════════════════════════════════════════════════╗")
 
 	// Opponent info
 	opp := sv.Opponent
-	fmt.Printf("║  OPPONENT (HP: %d)  Hand: %d  Deck: %d  Scrapheap: %d\n",
-		opp.HP, opp.HandCount, opp.DeckCount, opp.ScrapheapCount)
+	fmt.Printf("║  %s (HP: %d)  Hand: %d  Deck: %d  Scrapheap: %d\n",
+		oppLabel, opp.HP, opp.HandCount, opp.DeckCount, opp.ScrapheapCount)
 
 	// Opponent agents
 	fmt.Printf("║  Agent:   ")
@@ -157,15 +241,17 @@ func (c *Client) renderState(sv *StateView) {
 	}
 	fmt.Println()
 
-	fmt.Printf("║  YOU (HP: %d)  Hand: %d  Deck: %d  Scrapheap: %d\n",
-		you.HP, you.HandCount, you.DeckCount, you.ScrapheapCount)
+	fmt.Printf("║  %s (HP: %d)  Hand: %d  Deck: %d  Scrapheap: %d\n",
+		youLabel, you.HP, you.HandCount, you.DeckCount, you.ScrapheapCount)
 	fmt.Println("╚══════════════════════════════════════════════════════╝")
 
 	turnInfo := fmt.Sprintf("Turn %d | %s", sv.Turn, sv.Phase)
-	if sv.IsYourTurn {
-		turnInfo += " | Your turn"
-	} else {
-		turnInfo += " | Opponent's turn"
+	if !c.spectating {
+		if sv.IsYourTurn {
+			turnInfo += " | Your turn"
+		} else {
+			turnInfo += " | Opponent's turn"
+		}
 	}
 	fmt.Println(turnInfo)
 
@@ -212,11 +298,16 @@ func (c *Client) renderActions(actions []ActionView) {
 	}
 }
 
-func (c *Client) readChoice(reader *bufio.Reader, count int) int {
+// readChoice reads a 1-indexed action number. Returns -1 if the player asked
+// to rewind and canRewind is set.
+func (c *Client) readChoice(reader *bufio.Reader, count int, canRewind bool) int {
 	for {
 		fmt.Print("> ")
 		line, _ := reader.ReadString('\n')
 		line = strings.TrimSpace(line)
+		if canRewind && line == "rewind" {
+			return -1
+		}
 		n, err := strconv.Atoi(line)
 		if err != nil || n < 1 || n > count {
 			fmt.Printf("Enter a number between 1 and %d\n", count)
@@ -232,12 +323,9 @@ func (c *Client) renderCardChoice(prompt string, candidates []CardView, min, max
 		fmt.Printf("-%d", max)
 	}
 	fmt.Println(")")
+	bothSides := spansBothSides(candidates)
 	for _, cv := range candidates {
-		if cv.ATK > 0 || cv.DEF > 0 {
-			fmt.Printf("  %d) %s (ATK %d / DEF %d)\n", cv.Index+1, cv.Name, cv.ATK, cv.DEF)
-		} else {
-			fmt.Printf("  %d) %s\n", cv.Index+1, cv.Name)
-		}
+		fmt.Printf("  %d) %s\n", cv.Index+1, CandidateLabel(cv, c.player(), bothSides))
 	}
 }
 
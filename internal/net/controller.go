@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/peterkuimelis/tcgx/internal/game"
 	"github.com/peterkuimelis/tcgx/internal/log"
@@ -18,16 +19,52 @@ type NetworkController struct {
 	dec    *json.Decoder
 	player int // which player this controller is (0 or 1)
 	mu     sync.Mutex
+
+	// AllowRewind offers the debug "rewind" command on action prompts.
+	AllowRewind bool
+
+	// Token identifies this player's session: a client that reconnects with
+	// it is handed to Reattach.
+	Token string
+	// GracePeriod is how long a prompt waits for the player to reconnect
+	// after their connection drops. Zero fails the prompt straight away.
+	GracePeriod time.Duration
+	reattach    chan net.Conn // the latest reconnection, not yet in use
+
+	seq int // numbers the prompts, so a late reply isn't taken for the next one's
+
+	events []log.GameEvent // as this player saw them, for the timeline
 }
 
 // NewNetworkController creates a new controller for the given connection.
 func NewNetworkController(conn net.Conn, player int) *NetworkController {
-	return &NetworkController{
-		conn:   conn,
-		enc:    json.NewEncoder(conn),
-		dec:    json.NewDecoder(conn),
-		player: player,
+	nc := &NetworkController{
+		player:   player,
+		Token:    newToken(),
+		reattach: make(chan net.Conn, 1),
 	}
+	nc.attach(conn)
+	return nc
+}
+
+// Reattach replaces the player's connection with conn, after they reconnect.
+// A prompt waiting on the dropped connection is resent on conn.
+func (nc *NetworkController) Reattach(conn net.Conn) {
+	for {
+		select {
+		case nc.reattach <- conn:
+			return
+		case stale := <-nc.reattach:
+			stale.Close() // superseded by a newer reconnection
+		}
+	}
+}
+
+// attach switches to conn. Must be called with mu held.
+func (nc *NetworkController) attach(conn net.Conn) {
+	nc.conn = conn
+	nc.enc = json.NewEncoder(conn)
+	nc.dec = json.NewDecoder(conn)
 }
 
 // BuildStateView creates a StateView from the perspective of the given player.
@@ -36,7 +73,6 @@ func BuildStateView(state *game.GameState, player int) *StateView {
 	opp := 1 - me
 
 	myPlayer := state.Players[me]
-	oppPlayer := state.Players[opp]
 
 	sv := &StateView{
 		Turn:       state.Turn,
@@ -55,6 +91,7 @@ func BuildStateView(state *game.GameState, player int) *StateView {
 	for _, c := range myPlayer.Hand {
 		sv.You.Hand = append(sv.You.Hand, c.Card.Name)
 	}
+	sv.You.Scrapheap = scrapheapNames(myPlayer)
 	// My agents
 	for i := 0; i < 5; i++ {
 		sv.You.Agents[i] = AgentZoneView(myPlayer.AgentZones[i], true)
@@ -69,26 +106,117 @@ func BuildStateView(state *game.GameState, player int) *StateView {
 	}
 
 	// Opponent view
-	sv.Opponent = PlayerView{
-		HP:             oppPlayer.HP,
-		HandCount:      len(oppPlayer.Hand),
-		ScrapheapCount: len(oppPlayer.Scrapheap),
-		DeckCount:      oppPlayer.DeckCount(),
+	sv.Opponent = publicPlayerView(state, opp)
+
+	return sv
+}
+
+// BuildSpectatorView creates a StateView for someone watching the duel: both
+// sides as an opponent sees them, with P1 as "you".
+func BuildSpectatorView(state *game.GameState) *StateView {
+	return &StateView{
+		You:      publicPlayerView(state, 0),
+		Opponent: publicPlayerView(state, 1),
+		Turn:     state.Turn,
+		Phase:    state.Phase.String(),
+	}
+}
+
+// publicPlayerView shows a player's side as their opponent sees it.
+func publicPlayerView(state *game.GameState, player int) PlayerView {
+	p := state.Players[player]
+	pv := PlayerView{
+		HP:             p.HP,
+		HandCount:      len(p.Hand),
+		ScrapheapCount: len(p.Scrapheap),
+		DeckCount:      p.DeckCount(),
+	}
+	// Hand names, only while revealed (Forced Disclosure, Total Surveillance, etc.)
+	if state.HandRevealed(player) {
+		for _, c := range p.Hand {
+			pv.Hand = append(pv.Hand, c.Card.Name)
+		}
 	}
-	// Opponent agents (face-down info hidden)
+	// Scrapheaps are public information
+	pv.Scrapheap = scrapheapNames(p)
+	// Agents (face-down info hidden)
 	for i := 0; i < 5; i++ {
-		sv.Opponent.Agents[i] = AgentZoneView(oppPlayer.AgentZones[i], false)
+		pv.Agents[i] = AgentZoneView(p.AgentZones[i], false)
 	}
-	// Opponent Tech
+	// Tech
 	for i := 0; i < 5; i++ {
-		sv.Opponent.TechZone[i] = TechZoneView(oppPlayer.TechZones[i], false)
+		pv.TechZone[i] = TechZoneView(p.TechZones[i], false)
 	}
-	if oppPlayer.OS != nil {
-		fv := TechZoneView(oppPlayer.OS, false)
-		sv.Opponent.OS = &fv
+	if p.OS != nil {
+		fv := TechZoneView(p.OS, false)
+		pv.OS = &fv
 	}
+	return pv
+}
 
-	return sv
+// scrapheapNames lists the card names in a player's scrapheap, oldest first.
+func scrapheapNames(p *game.Player) []string {
+	var names []string
+	for _, c := range p.Scrapheap {
+		names = append(names, c.Card.Name)
+	}
+	return names
+}
+
+// BuildCardViews creates the numbered candidate list for a card choice. Each
+// view carries its controller, since candidates may come from both fields.
+func BuildCardViews(candidates []*game.CardInstance) []CardView {
+	var views []CardView
+	for i, c := range candidates {
+		cv := CardView{Index: i, Name: c.Card.Name, Controller: c.Controller}
+		if c.Card.CardType == game.CardTypeAgent {
+			cv.ATK = c.CurrentATK()
+			cv.DEF = c.CurrentDEF()
+		}
+		views = append(views, cv)
+	}
+	return views
+}
+
+// CandidateLabel describes a card choice for viewer, naming whose card it is
+// when the choice spans both players' cards.
+func CandidateLabel(cv CardView, viewer int, bothSides bool) string {
+	label := cv.Name
+	if cv.ATK > 0 || cv.DEF > 0 {
+		label += fmt.Sprintf(" (ATK %d / DEF %d)", cv.ATK, cv.DEF)
+	}
+	if bothSides {
+		if cv.Controller == viewer {
+			label += " [yours]"
+		} else {
+			label += " [opponent's]"
+		}
+	}
+	return label
+}
+
+// spansBothSides reports whether candidates include cards of both players.
+func spansBothSides(candidates []CardView) bool {
+	for _, cv := range candidates {
+		if cv.Controller != candidates[0].Controller {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildActionViews creates the numbered action list sent to clients.
+func BuildActionViews(actions []game.Action) []ActionView {
+	var views []ActionView
+	for i, a := range actions {
+		views = append(views, ActionView{
+			Index:           i,
+			Desc:            a.String(),
+			OpensChain:      a.OpensChain(),
+			RequiresTargets: a.RequiresTargets(),
+		})
+	}
+	return views
 }
 
 // buildStateView creates a StateView from the perspective of this controller's player.
@@ -135,8 +263,15 @@ func TechZoneView(ci *game.CardInstance, isOwner bool) ZoneView {
 	return ZoneView{Name: ci.Card.Name}
 }
 
-// send sends a server message to the client. Must be called with mu held.
+// send sends a server message to the client, over the player's latest
+// connection. Must be called with mu held.
 func (nc *NetworkController) send(msg ServerMessage) error {
+	select {
+	case conn := <-nc.reattach:
+		nc.conn.Close()
+		nc.attach(conn)
+	default:
+	}
 	return nc.enc.Encode(msg)
 }
 
@@ -147,28 +282,94 @@ func (nc *NetworkController) recv() (ClientMessage, error) {
 	return msg, err
 }
 
+// prompt sends msg and reads the player's reply. If the connection drops, it
+// waits up to GracePeriod for the player to reconnect, then sends msg again.
+// Cancelling ctx (the decision timed out) stops the wait at once; the reply,
+// if it comes later, echoes an old Seq and is dropped. Must be called with mu
+// held.
+func (nc *NetworkController) prompt(ctx context.Context, msg ServerMessage) (ClientMessage, error) {
+	nc.seq++
+	msg.Seq = nc.seq
+	for {
+		resp, err := nc.exchange(ctx, msg)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return ClientMessage{}, ctx.Err()
+		}
+		if err := nc.awaitReconnect(ctx, err); err != nil {
+			return ClientMessage{}, err
+		}
+	}
+}
+
+// exchange sends msg and reads the reply to it, skipping replies to earlier
+// prompts. Cancelling ctx interrupts the read. Must be called with mu held.
+func (nc *NetworkController) exchange(ctx context.Context, msg ServerMessage) (ClientMessage, error) {
+	if err := nc.send(msg); err != nil {
+		return ClientMessage{}, err
+	}
+	conn := nc.conn
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Now())
+		close(interrupted)
+	})
+	defer func() {
+		if !stop() {
+			// The read was cut short: clear the deadline and start a fresh
+			// decoder, since the old one keeps returning its error
+			<-interrupted
+			conn.SetReadDeadline(time.Time{})
+			nc.dec = json.NewDecoder(conn)
+		}
+	}()
+	for {
+		resp, err := nc.recv()
+		if err != nil || resp.Seq == 0 || resp.Seq == msg.Seq {
+			return resp, err
+		}
+	}
+}
+
+// awaitReconnect waits for the player to reconnect after cause broke their
+// connection. Must be called with mu held.
+func (nc *NetworkController) awaitReconnect(ctx context.Context, cause error) error {
+	if nc.GracePeriod <= 0 {
+		return cause
+	}
+	nc.conn.Close()
+	timer := time.NewTimer(nc.GracePeriod)
+	defer timer.Stop()
+	select {
+	case conn := <-nc.reattach:
+		nc.attach(conn)
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("player %d did not reconnect: %w", nc.player+1, cause)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // ChooseAction implements game.PlayerController.
 func (nc *NetworkController) ChooseAction(ctx context.Context, state *game.GameState, actions []game.Action) (game.Action, error) {
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
 
-	var views []ActionView
-	for i, a := range actions {
-		views = append(views, ActionView{Index: i, Desc: a.String()})
-	}
-
 	msg := ServerMessage{
-		Type:    "choose_action",
-		Actions: views,
-		State:   nc.buildStateView(state),
-	}
-	if err := nc.send(msg); err != nil {
-		return game.Action{}, fmt.Errorf("send choose_action: %w", err)
+		Type:      "choose_action",
+		Actions:   BuildActionViews(actions),
+		State:     nc.buildStateView(state),
+		CanRewind: nc.AllowRewind,
 	}
-
-	resp, err := nc.recv()
+	resp, err := nc.prompt(ctx, msg)
 	if err != nil {
-		return game.Action{}, fmt.Errorf("recv action: %w", err)
+		return game.Action{}, fmt.Errorf("choose_action: %w", err)
+	}
+	if resp.Type == "rewind" && nc.AllowRewind {
+		return game.Action{}, game.ErrRewindTurn
 	}
 
 	if resp.Index < 0 || resp.Index >= len(actions) {
@@ -182,31 +383,17 @@ func (nc *NetworkController) ChooseCards(ctx context.Context, state *game.GameSt
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
 
-	var views []CardView
-	for i, c := range candidates {
-		cv := CardView{Index: i, Name: c.Card.Name}
-		if c.Card.CardType == game.CardTypeAgent {
-			cv.ATK = c.CurrentATK()
-			cv.DEF = c.CurrentDEF()
-		}
-		views = append(views, cv)
-	}
-
 	msg := ServerMessage{
 		Type:       "choose_cards",
 		Prompt:     prompt,
-		Candidates: views,
+		Candidates: BuildCardViews(candidates),
 		Min:        min,
 		Max:        max,
 		State:      nc.buildStateView(state),
 	}
-	if err := nc.send(msg); err != nil {
-		return nil, fmt.Errorf("send choose_cards: %w", err)
-	}
-
-	resp, err := nc.recv()
+	resp, err := nc.prompt(ctx, msg)
 	if err != nil {
-		return nil, fmt.Errorf("recv cards: %w", err)
+		return nil, fmt.Errorf("choose_cards: %w", err)
 	}
 
 	var result []*game.CardInstance
@@ -228,23 +415,27 @@ func (nc *NetworkController) ChooseYesNo(ctx context.Context, state *game.GameSt
 		Prompt: prompt,
 		State:  nc.buildStateView(state),
 	}
-	if err := nc.send(msg); err != nil {
-		return false, fmt.Errorf("send choose_yes_no: %w", err)
-	}
-
-	resp, err := nc.recv()
+	resp, err := nc.prompt(ctx, msg)
 	if err != nil {
-		return false, fmt.Errorf("recv yes_no: %w", err)
+		return false, fmt.Errorf("choose_yes_no: %w", err)
 	}
 
 	return resp.Answer, nil
 }
 
 // SendGameOver sends a game_over message to the client.
-func (nc *NetworkController) SendGameOver(winner int, result string) error {
+func (nc *NetworkController) SendGameOver(winner int, result string, reason game.WinReason) error {
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
-	return nc.send(ServerMessage{Type: "game_over", Winner: winner, Result: result})
+	return nc.sendResult(ServerMessage{Type: "game_over", Winner: winner, Result: result, WinReason: reason.String()})
+}
+
+// sendResult sends a game_over message with the game's timeline, which then
+// starts afresh for the next game. Must be called with mu held.
+func (nc *NetworkController) sendResult(msg ServerMessage) error {
+	msg.Timeline = buildTimeline(nc.events)
+	nc.events = nil
+	return nc.send(msg)
 }
 
 // Notify implements game.PlayerController.
@@ -252,16 +443,37 @@ func (nc *NetworkController) Notify(ctx context.Context, event log.GameEvent) er
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
 
-	msg := ServerMessage{
-		Type: "notify",
-		Event: &EventView{
-			Turn:    event.Turn,
-			Phase:   event.Phase,
-			Player:  event.Player,
-			Type:    event.Type.String(),
-			Card:    event.Card,
-			Details: event.Details,
-		},
+	event = log.RedactFor([]log.GameEvent{event}, nc.player)[0]
+	if event.Type == log.EventNewTurn && len(nc.events) > 0 {
+		if err := nc.send(ServerMessage{Type: "timeline", Timeline: buildTimeline(nc.events)}); err != nil {
+			return err
+		}
+	}
+	nc.events = append(nc.events, event)
+	return nc.send(ServerMessage{Type: "notify", Event: newEventView(event)})
+}
+
+// buildTimeline converts events' per-turn summaries for the client.
+func buildTimeline(events []log.GameEvent) []TurnView {
+	var views []TurnView
+	for _, turn := range log.Timeline(events) {
+		tv := TurnView{Turn: turn.Turn, Player: turn.Player, Actions: []EventView{}}
+		for _, e := range turn.Actions {
+			tv.Actions = append(tv.Actions, *newEventView(e))
+		}
+		views = append(views, tv)
+	}
+	return views
+}
+
+// newEventView converts a game event for the client.
+func newEventView(event log.GameEvent) *EventView {
+	return &EventView{
+		Turn:    event.Turn,
+		Phase:   event.Phase,
+		Player:  event.Player,
+		Type:    event.Type.String(),
+		Card:    event.Card,
+		Details: event.Details,
 	}
-	return nc.send(msg)
 }
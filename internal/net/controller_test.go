@@ -0,0 +1,174 @@
+package net
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+func TestActionViewFlags(t *testing.T) {
+	actions := []game.Action{
+		{Type: game.ActionActivate, Card: &game.CardInstance{Card: game.ICEBreaker()}, Desc: "Activate ICE Breaker"},
+		{Type: game.ActionActivate, Card: &game.CardInstance{Card: game.GreedProtocol()}, Desc: "Activate Greed Protocol"},
+		{Type: game.ActionActivate, Card: &game.CardInstance{Card: game.PolarityInvert()}, Desc: "Activate Polarity Invert"},
+		{Type: game.ActionEndTurn},
+	}
+	views := BuildActionViews(actions)
+
+	// Targeting quick-play
+	if v := views[0]; !v.OpensChain || !v.RequiresTargets {
+		t.Errorf("ICE Breaker: expected opens_chain and requires_targets, got %+v", v)
+	}
+	// Non-targeting normal program
+	if v := views[1]; !v.OpensChain || v.RequiresTargets {
+		t.Errorf("Greed Protocol: expected opens_chain only, got %+v", v)
+	}
+	// Non-targeting SS2 quick-play
+	if v := views[2]; !v.OpensChain || v.RequiresTargets {
+		t.Errorf("Polarity Invert: expected opens_chain only, got %+v", v)
+	}
+	if v := views[3]; v.OpensChain || v.RequiresTargets {
+		t.Errorf("End Turn: expected no flags, got %+v", v)
+	}
+}
+
+func TestStateViewShowsBothScrapheaps(t *testing.T) {
+	gs := game.NewGameState()
+	gs.Players[0].SendToScrapheap(&game.CardInstance{Card: game.GreedProtocol()})
+	gs.Players[1].SendToScrapheap(&game.CardInstance{Card: game.ICEBreaker()})
+	gs.Players[1].SendToScrapheap(&game.CardInstance{Card: game.GreedProtocol()})
+
+	sv := BuildStateView(gs, 0)
+	if got := sv.You.Scrapheap; len(got) != 1 || got[0] != "Greed Protocol" {
+		t.Errorf("Expected own scrapheap [Greed Protocol], got %v", got)
+	}
+	// The opponent's scrapheap is public, in full
+	if got := sv.Opponent.Scrapheap; len(got) != 2 || got[0] != "ICE Breaker" || got[1] != "Greed Protocol" {
+		t.Errorf("Expected opponent scrapheap [ICE Breaker Greed Protocol], got %v", got)
+	}
+	if sv.Opponent.ScrapheapCount != 2 {
+		t.Errorf("Expected opponent scrapheap count 2, got %d", sv.Opponent.ScrapheapCount)
+	}
+}
+
+// TestCardViewsCarryController: Chrome Paladin's target prompt spans both
+// fields, and each candidate sent to the client names its controller.
+func TestCardViewsCarryController(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	nc := NewNetworkController(server, 0)
+	duel := game.NewDuel(game.DuelConfig{}, nc, game.NewRandomController(1))
+	gs := duel.State
+
+	paladin := gs.CreateCardInstance(game.ChromePaladinEnvoy(), 0)
+	gs.Players[0].PlaceAgent(paladin, 0)
+	gs.Players[0].PlaceAgent(gs.CreateCardInstance(game.ICEBreaker(), 0), 1)
+	gs.Players[1].PlaceAgent(gs.CreateCardInstance(game.GreedProtocol(), 1), 0)
+
+	got := make(chan ServerMessage, 1)
+	go func() {
+		var msg ServerMessage
+		_ = json.NewDecoder(client).Decode(&msg)
+		got <- msg
+		_ = json.NewEncoder(client).Encode(ClientMessage{Type: "cards", Indices: []int{1}})
+	}()
+
+	var target func(*game.Duel, *game.CardInstance, int) ([]*game.CardInstance, error)
+	for _, eff := range paladin.Card.Effects {
+		if eff.Target != nil {
+			target = eff.Target
+		}
+	}
+	chosen, err := target(duel, paladin, 0)
+	if err != nil {
+		t.Fatalf("Target: %v", err)
+	}
+
+	msg := <-got
+	want := []struct {
+		name       string
+		controller int
+	}{{"ICE Breaker", 0}, {"Greed Protocol", 1}}
+	if len(msg.Candidates) != len(want) {
+		t.Fatalf("Expected %d candidates, got %+v", len(want), msg.Candidates)
+	}
+	for i, w := range want {
+		if cv := msg.Candidates[i]; cv.Name != w.name || cv.Controller != w.controller {
+			t.Errorf("Candidate %d: expected %s controlled by %d, got %+v", i, w.name, w.controller, cv)
+		}
+	}
+	if len(chosen) != 1 || chosen[0].Controller != 1 {
+		t.Errorf("Expected the opponent's card chosen, got %v", chosen)
+	}
+	if label := CandidateLabel(msg.Candidates[1], 0, spansBothSides(msg.Candidates)); !strings.HasSuffix(label, "[opponent's]") {
+		t.Errorf("Expected the opponent's card labeled as theirs, got %q", label)
+	}
+}
+
+// TestLateReplyDropped: a prompt whose context is cancelled stops waiting at
+// once, and the reply that turns up afterwards isn't taken as the answer to
+// the next prompt.
+func TestLateReplyDropped(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	nc := NewNetworkController(server, 0)
+	gs := game.NewGameState()
+	actions := []game.Action{{Type: game.ActionEndTurn}, {Type: game.ActionConcede}}
+
+	dec, enc := json.NewDecoder(client), json.NewEncoder(client)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := nc.ChooseAction(ctx, gs, actions)
+		done <- err
+	}()
+	var first ServerMessage
+	if err := dec.Decode(&first); err != nil {
+		t.Fatalf("read prompt: %v", err)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Expected the cancelled prompt to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the cancelled prompt to stop waiting for a reply")
+	}
+
+	got := make(chan game.Action, 1)
+	go func() {
+		a, err := nc.ChooseAction(context.Background(), gs, actions)
+		if err != nil {
+			t.Errorf("ChooseAction: %v", err)
+		}
+		got <- a
+	}()
+	// The late reply to the first prompt concedes; the reply to the second ends the turn
+	late := make(chan error, 1)
+	go func() { late <- enc.Encode(ClientMessage{Type: "action", Seq: first.Seq, Index: 1}) }()
+	var second ServerMessage
+	if err := dec.Decode(&second); err != nil {
+		t.Fatalf("read second prompt: %v", err)
+	}
+	if second.Seq == first.Seq {
+		t.Fatalf("Expected a new Seq for the second prompt, got %d twice", first.Seq)
+	}
+	if err := <-late; err != nil {
+		t.Fatalf("send late reply: %v", err)
+	}
+	go enc.Encode(ClientMessage{Type: "action", Seq: second.Seq, Index: 0})
+	select {
+	case a := <-got:
+		if a.Type != game.ActionEndTurn {
+			t.Errorf("Expected the second prompt's own answer (End Turn), got %v", a.Type)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the second prompt to be answered")
+	}
+}
@@ -0,0 +1,143 @@
+package net
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+// DeckSource describes where a player's deck comes from: a numbered deck in a
+// decks file, or an inline deck entry sent over the wire.
+type DeckSource struct {
+	File   string          // decks YAML file; empty means the server's default file
+	Number int             // 1-indexed deck number within File
+	Inline *game.DeckEntry // inline deck; takes precedence over File/Number
+}
+
+// Load resolves the deck, using defaultFile when the source names no file.
+func (ds DeckSource) Load(defaultFile string) (string, []*game.Card, error) {
+	entry, err := ds.entry(defaultFile)
+	if err != nil {
+		return "", nil, err
+	}
+	cards, err := game.BuildDeck(entry)
+	if err != nil {
+		return "", nil, err
+	}
+	return entry.Name, cards, nil
+}
+
+// LoadSide resolves the deck's side deck, for match play. It may hold up to
+// MaxSideDeck cards, and no card more than game.MaxCopies times counting the
+// main deck's copies too.
+func (ds DeckSource) LoadSide(defaultFile string) ([]*game.Card, error) {
+	entry, err := ds.entry(defaultFile)
+	if err != nil {
+		return nil, err
+	}
+	main, err := game.BuildDeck(entry)
+	if err != nil {
+		return nil, err
+	}
+	side, err := game.BuildSideDeck(entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSide(main, side); err != nil {
+		return nil, fmt.Errorf("deck %q: %w", entry.Name, err)
+	}
+	return side, nil
+}
+
+// validateSide checks a side deck's size and its copies of each card, counted
+// together with the main deck's.
+func validateSide(main, side []*game.Card) error {
+	if len(side) > MaxSideDeck {
+		return fmt.Errorf("side deck has %d cards (max %d)", len(side), MaxSideDeck)
+	}
+	counts := make(map[string]int)
+	for _, c := range main {
+		counts[c.Name]++
+	}
+	for _, c := range side {
+		counts[c.Name]++
+		if counts[c.Name] > game.MaxCopies {
+			return fmt.Errorf("main and side decks have %d copies of %q (max %d)", counts[c.Name], c.Name, game.MaxCopies)
+		}
+	}
+	return nil
+}
+
+// entry returns the raw deck entry, using defaultFile when the source names no file.
+func (ds DeckSource) entry(defaultFile string) (game.DeckEntry, error) {
+	if ds.Inline != nil {
+		return *ds.Inline, nil
+	}
+	file := ds.File
+	if file == "" {
+		file = defaultFile
+	}
+	return game.DeckEntryByNumber(file, ds.Number)
+}
+
+// joinMessage builds the join handshake for this deck source. A deck from a
+// local decks file is sent inline, since the server can't read the joiner's files.
+func (ds DeckSource) joinMessage() (ClientMessage, error) {
+	msg := ClientMessage{Type: "join", DeckNumber: ds.Number, Deck: ds.Inline}
+	if ds.Inline == nil && ds.File != "" {
+		entry, err := game.DeckEntryByNumber(ds.File, ds.Number)
+		if err != nil {
+			return ClientMessage{}, fmt.Errorf("load deck: %w", err)
+		}
+		msg.Deck = &entry
+	}
+	if msg.Deck != nil {
+		main, err := game.BuildDeck(*msg.Deck)
+		if err != nil {
+			return ClientMessage{}, fmt.Errorf("build deck: %w", err)
+		}
+		side, err := game.BuildSideDeck(*msg.Deck)
+		if err != nil {
+			return ClientMessage{}, fmt.Errorf("build side deck: %w", err)
+		}
+		msg.DeckHash = game.DeckHash(MatchDeck{Main: main, Side: side}.pool())
+	}
+	return msg, nil
+}
+
+// checkCommitment checks the deck hash a joiner committed to against the deck
+// the server built from their join message. A joiner playing a deck from the
+// host's own file sends no hash, and has nothing to check.
+func checkCommitment(msg ClientMessage, deck MatchDeck) error {
+	if msg.DeckHash != "" && msg.DeckHash != game.DeckHash(deck.pool()) {
+		return fmt.Errorf("deck doesn't match the hash committed to at join")
+	}
+	return nil
+}
+
+// revealDecks lists each player's main and side cards by name, sorted as
+// game.DeckHash sorts them, so anyone can check the hashes committed to.
+func revealDecks(decks [2]MatchDeck) [][]string {
+	lists := make([][]string, 2)
+	for p, deck := range decks {
+		for _, c := range deck.pool() {
+			lists[p] = append(lists[p], c.Name)
+		}
+		sort.Strings(lists[p])
+	}
+	return lists
+}
+
+// DeckSourceFromJoin extracts the joiner's deck source from a join message.
+// A bare deck number defaults to deck 2 of the host's decks file.
+func DeckSourceFromJoin(msg ClientMessage) DeckSource {
+	if msg.Deck != nil {
+		return DeckSource{Inline: msg.Deck}
+	}
+	n := msg.DeckNumber
+	if n == 0 {
+		n = 2
+	}
+	return DeckSource{Number: n}
+}
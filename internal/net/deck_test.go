@@ -0,0 +1,127 @@
+package net
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+func writeDecksFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestPerPlayerDeckSources(t *testing.T) {
+	hostFile := writeDecksFile(t, "host.yaml", `decks:
+  - name: Host Deck
+    cards:
+      - name: Void Drifter
+        count: 3
+`)
+	joinerFile := writeDecksFile(t, "joiner.yaml", `decks:
+  - name: Unused
+    cards:
+      - name: Void Drifter
+        count: 1
+  - name: Joiner Deck
+    cards:
+      - name: Greed Protocol
+        count: 2
+`)
+
+	// Host loads from the server's default decks file
+	hostName, hostCards, err := DeckSource{Number: 1}.Load(hostFile)
+	if err != nil {
+		t.Fatalf("load host deck: %v", err)
+	}
+	if hostName != "Host Deck" || len(hostCards) != 3 {
+		t.Fatalf("host deck = %q (%d cards), want Host Deck (3 cards)", hostName, len(hostCards))
+	}
+
+	// Joiner picks deck 2 of its own file; the deck travels inline over the wire
+	joinMsg, err := DeckSource{File: joinerFile, Number: 2}.joinMessage()
+	if err != nil {
+		t.Fatalf("build join message: %v", err)
+	}
+	data, err := json.Marshal(joinMsg)
+	if err != nil {
+		t.Fatalf("marshal join: %v", err)
+	}
+	var received ClientMessage
+	if err := json.Unmarshal(data, &received); err != nil {
+		t.Fatalf("unmarshal join: %v", err)
+	}
+
+	joinerName, joinerCards, err := DeckSourceFromJoin(received).Load(hostFile)
+	if err != nil {
+		t.Fatalf("load joiner deck: %v", err)
+	}
+	if joinerName != "Joiner Deck" || len(joinerCards) != 2 {
+		t.Fatalf("joiner deck = %q (%d cards), want Joiner Deck (2 cards)", joinerName, len(joinerCards))
+	}
+	for _, c := range joinerCards {
+		if c.Name != "Greed Protocol" {
+			t.Errorf("joiner card = %q, want Greed Protocol", c.Name)
+		}
+	}
+	if err := checkCommitment(received, MatchDeck{Main: joinerCards}); err != nil {
+		t.Errorf("joiner commitment: %v", err)
+	}
+
+	// A joiner whose hash doesn't match the deck they sent is caught at join
+	received.DeckHash = game.DeckHash(hostCards)
+	if err := checkCommitment(received, MatchDeck{Main: joinerCards}); err == nil {
+		t.Error("expected a mismatched commitment to be rejected")
+	}
+}
+
+func TestDeckSourceFromJoinDefaults(t *testing.T) {
+	src := DeckSourceFromJoin(ClientMessage{Type: "join"})
+	if src.Number != 2 || src.Inline != nil || src.File != "" {
+		t.Errorf("bare join source = %+v, want deck 2 of host file", src)
+	}
+}
+
+func TestInlineDeckUnknownCard(t *testing.T) {
+	var msg ClientMessage
+	if err := json.Unmarshal([]byte(`{"type":"join","deck":{"name":"Bad","cards":[{"name":"No Such Card","count":1}]}}`), &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, _, err := DeckSourceFromJoin(msg).Load("unused.yaml"); err == nil {
+		t.Error("expected error for unknown card in inline deck")
+	}
+}
+
+// TestLoadSideRejectsIllegalSideDecks: a side deck over MaxSideDeck cards, or
+// one taking a card past game.MaxCopies with the main deck's copies, fails.
+func TestLoadSideRejectsIllegalSideDecks(t *testing.T) {
+	main := []game.CardEntry{{Name: "Void Drifter", Count: 2}}
+	for _, tc := range []struct {
+		name string
+		side []game.CardEntry
+		ok   bool
+	}{
+		{"legal", []game.CardEntry{{Name: "Void Drifter", Count: 1}, {Name: "Flip Jammer", Count: 3}}, true},
+		{"fourth copy", []game.CardEntry{{Name: "Void Drifter", Count: 2}}, false},
+		{"too many cards", []game.CardEntry{
+			{Name: "Flip Jammer", Count: 3}, {Name: "Greed Protocol", Count: 3}, {Name: "Void Purge", Count: 3},
+			{Name: "EMP Cascade", Count: 3}, {Name: "ICE Breaker", Count: 3}, {Name: "Blackout Patch", Count: 1},
+		}, false},
+	} {
+		src := DeckSource{Inline: &game.DeckEntry{Name: "Sided", Cards: main, Side: tc.side}}
+		side, err := src.LoadSide("unused.yaml")
+		if tc.ok && (err != nil || len(side) != 4) {
+			t.Errorf("%s: expected a 4-card side deck, got %d cards, %v", tc.name, len(side), err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("%s: expected the side deck to be rejected", tc.name)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+// MaxSideDeck is the most cards a side deck may hold, and so the most a
+// player can swap between games.
+const MaxSideDeck = 15
+
+// MatchDeck is one player's cards for a match: the main deck they duel with
+// and the side deck they may swap cards in from between games.
+type MatchDeck struct {
+	Main []*game.Card
+	Side []*game.Card
+}
+
+// pool returns the deck's main and side cards together. Siding only moves
+// cards between the two, so the pool is the same for every game of a match.
+func (md MatchDeck) pool() []*game.Card {
+	return append(slices.Clone(md.Main), md.Side...)
+}
+
+// Match plays a best-of-N match between two players, as in tournament play.
+// Game 1 starts as Config says (FirstPlayer, or a coin toss). After that, the
+// choice of who goes first alternates between the players, starting with
+// player 1. Between games, each player may swap up to MaxSideDeck cards
+// between their main and side decks.
+type Match struct {
+	BestOf      int // games in the match; 3 if zero
+	Decks       [2]MatchDeck
+	Controllers [2]game.PlayerController
+	Banlist     game.Banlist // main decks are held to it after siding; nil restricts nothing
+
+	// Config holds the settings for every game. Its decks and FirstPlayer
+	// are set per game, and a nonzero Seed is offset by the game number.
+	Config game.DuelConfig
+
+	// GameStart, if set, is called with each game before it is played, and
+	// GameOver after it with the score so far.
+	GameStart func(duel *game.Duel)
+	GameOver  func(duel *game.Duel, res MatchResult)
+}
+
+// MatchResult is the state of a match.
+type MatchResult struct {
+	Winner int    // player who won the match, or -1 if undecided or drawn
+	Score  [2]int // games won by each player
+	Games  []int  // each game's winner, or -1 for a drawn game
+}
+
+// Over reports whether the match is finished.
+func (r MatchResult) Over(bestOf int) bool {
+	return r.Winner >= 0 || len(r.Games) >= bestOf
+}
+
+// String reports the score, e.g. "2–1".
+func (r MatchResult) String() string {
+	return fmt.Sprintf("%d–%d", r.Score[0], r.Score[1])
+}
+
+// ParseBestOf parses a match format such as "bo3" into its number of games.
+func ParseBestOf(format string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(format), "bo"))
+	if err != nil || n < 1 || n%2 == 0 {
+		return 0, fmt.Errorf("unknown match format %q (want bo1, bo3, bo5, ...)", format)
+	}
+	return n, nil
+}
+
+// Play runs games until a player has won a majority of them. A drawn game
+// counts for neither player; if the games run out first, the match is drawn.
+func (m *Match) Play(ctx context.Context) (MatchResult, error) {
+	res := MatchResult{Winner: -1}
+	bestOf := m.BestOf
+	if bestOf == 0 {
+		bestOf = 3
+	}
+	for p, deck := range m.Decks {
+		if len(deck.Side) > MaxSideDeck {
+			return res, fmt.Errorf("P%d's side deck has %d cards (max %d)", p+1, len(deck.Side), MaxSideDeck)
+		}
+	}
+
+	var last *game.GameState
+	for i := 0; !res.Over(bestOf); i++ {
+		cfg := m.Config
+		if i > 0 {
+			first, err := m.chooseFirst(ctx, last, i)
+			if err != nil {
+				return res, err
+			}
+			cfg.FirstPlayer, cfg.CoinToss = first, false
+			for p := 0; p < 2; p++ {
+				if err := m.sideDeck(ctx, last, p); err != nil {
+					return res, err
+				}
+			}
+		}
+		cfg.Deck0, cfg.Deck1 = m.Decks[0].Main, m.Decks[1].Main
+		if cfg.Seed != 0 {
+			cfg.Seed += int64(i)
+		}
+
+		duel := game.NewDuel(cfg, m.Controllers[0], m.Controllers[1])
+		if m.GameStart != nil {
+			m.GameStart(duel)
+		}
+		winner, err := duel.Run(ctx)
+		if err != nil {
+			return res, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		res.Games = append(res.Games, winner)
+		if winner >= 0 {
+			res.Score[winner]++
+			if res.Score[winner] > bestOf/2 {
+				res.Winner = winner
+			}
+		}
+		if m.GameOver != nil {
+			m.GameOver(duel, res)
+		}
+		last = duel.State
+	}
+	return res, nil
+}
+
+// chooseFirst asks the player whose turn it is to choose who goes first in
+// game i (0-indexed), given the final state of the previous game.
+func (m *Match) chooseFirst(ctx context.Context, last *game.GameState, i int) (int, error) {
+	chooser := i % 2
+	yes, err := m.Controllers[chooser].ChooseYesNo(ctx, last, fmt.Sprintf("Go first in game %d?", i+1))
+	if err != nil {
+		return 0, err
+	}
+	if yes {
+		return chooser, nil
+	}
+	return 1 - chooser, nil
+}
+
+// sideDeck lets player swap cards between their main and side decks: any
+// number out of the main deck, then as many in from the side deck. The main
+// deck must still be legal afterwards.
+func (m *Match) sideDeck(ctx context.Context, last *game.GameState, player int) error {
+	deck := &m.Decks[player]
+	if len(deck.Side) == 0 {
+		return nil
+	}
+	ctrl := m.Controllers[player]
+	main, side := deckChoices(deck.Main, player), deckChoices(deck.Side, player)
+
+	out, err := ctrl.ChooseCards(ctx, last, "Side deck: choose cards to take out of your main deck", main, 0, min(len(side), MaxSideDeck))
+	if err != nil || len(out) == 0 {
+		return err
+	}
+	in, err := ctrl.ChooseCards(ctx, last, fmt.Sprintf("Side deck: choose %d cards to bring in", len(out)), side, len(out), len(out))
+	if err != nil {
+		return err
+	}
+	if len(in) != len(out) {
+		return fmt.Errorf("P%d swapped %d cards out for %d in", player+1, len(out), len(in))
+	}
+	sided := swapCards(main, out, in)
+	if err := game.ValidateDeckWithBanlist(sided, m.Banlist); err != nil {
+		return fmt.Errorf("P%d's sided deck: %w", player+1, err)
+	}
+	deck.Main, deck.Side = sided, swapCards(side, in, out)
+	return nil
+}
+
+// deckChoices wraps a deck's cards as instances a controller can choose from.
+func deckChoices(cards []*game.Card, owner int) []*game.CardInstance {
+	choices := make([]*game.CardInstance, len(cards))
+	for i, c := range cards {
+		choices[i] = &game.CardInstance{Card: c, ID: i + 1, Owner: owner, Controller: owner, Zone: game.ZoneDeck}
+	}
+	return choices
+}
+
+// swapCards returns the cards of deck without those taken out, plus those put in.
+func swapCards(deck, out, in []*game.CardInstance) []*game.Card {
+	removed := make(map[*game.CardInstance]bool, len(out))
+	for _, c := range out {
+		removed[c] = true
+	}
+	var cards []*game.Card
+	for _, c := range deck {
+		if !removed[c] {
+			cards = append(cards, c.Card)
+		}
+	}
+	for _, c := range in {
+		cards = append(cards, c.Card)
+	}
+	return cards
+}
@@ -0,0 +1,161 @@
+package net
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+// matchScript plays randomly, except that it concedes the games it is scripted
+// to lose, always chooses to go first, and sides in its side deck once.
+type matchScript struct {
+	*game.RandomController
+	loses   map[int]bool // by game number, from 1
+	games   int
+	state   *game.GameState
+	sideOut string // main deck card to swap out for the side deck, once
+}
+
+func (ms *matchScript) ChooseAction(ctx context.Context, state *game.GameState, actions []game.Action) (game.Action, error) {
+	if state != ms.state {
+		ms.state = state
+		ms.games++
+	}
+	for _, a := range actions {
+		if a.Type == game.ActionConcede && ms.loses[ms.games] {
+			return a, nil
+		}
+	}
+	return ms.RandomController.ChooseAction(ctx, state, actions)
+}
+
+func (ms *matchScript) ChooseYesNo(ctx context.Context, state *game.GameState, prompt string) (bool, error) {
+	if strings.HasPrefix(prompt, "Go first") {
+		return true, nil
+	}
+	return ms.RandomController.ChooseYesNo(ctx, state, prompt)
+}
+
+func (ms *matchScript) ChooseCards(ctx context.Context, state *game.GameState, prompt string, candidates []*game.CardInstance, min, max int) ([]*game.CardInstance, error) {
+	switch {
+	case strings.HasPrefix(prompt, "Side deck: choose cards to take out"):
+		for _, c := range candidates {
+			if c.Card.Name == ms.sideOut {
+				ms.sideOut = ""
+				return []*game.CardInstance{c}, nil
+			}
+		}
+		return nil, nil
+	case strings.HasPrefix(prompt, "Side deck:"):
+		return candidates[:min], nil
+	}
+	return ms.RandomController.ChooseCards(ctx, state, prompt, candidates, min, max)
+}
+
+// TestBestOfThreeMatch: P2 concedes games 1 and 3 and P1 game 2, so P1 wins
+// the match 2–1. The choice of who goes first alternates after game 1, and P2
+// sides in a card before game 2.
+func TestBestOfThreeMatch(t *testing.T) {
+	_, deck0, err := game.DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	_, deck1, err := game.DeckByNumber("../../decks.yaml", 2)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	out := deck1[0].Name
+
+	p0 := &matchScript{RandomController: game.NewRandomController(1), loses: map[int]bool{2: true}}
+	p1 := &matchScript{RandomController: game.NewRandomController(2), loses: map[int]bool{1: true, 3: true}, sideOut: out}
+	m := &Match{
+		BestOf: 3,
+		Decks: [2]MatchDeck{
+			{Main: deck0},
+			{Main: deck1, Side: []*game.Card{game.FlipJammer()}},
+		},
+		Controllers: [2]game.PlayerController{p0, p1},
+		Config:      game.DuelConfig{Seed: 5, MaxTurns: 20},
+	}
+	var first []int
+	m.GameStart = func(duel *game.Duel) {
+		first = append(first, duel.State.TurnPlayer)
+	}
+	var scores []string
+	m.GameOver = func(duel *game.Duel, res MatchResult) {
+		scores = append(scores, res.String())
+	}
+
+	res, err := m.Play(context.Background())
+	if err != nil {
+		t.Fatalf("Match error: %v", err)
+	}
+	if res.Winner != 0 || res.String() != "2–1" || !reflect.DeepEqual(res.Games, []int{0, 1, 0}) {
+		t.Errorf("Expected P1 to win 2–1 over games [0 1 0], got winner %d, %s, %v", res.Winner, res, res.Games)
+	}
+	if want := []string{"1–0", "1–1", "2–1"}; !reflect.DeepEqual(scores, want) {
+		t.Errorf("Expected scores %v after each game, got %v", want, scores)
+	}
+	// P1 goes first in game 1; then P2 chooses, then P1
+	if want := []int{0, 1, 0}; !reflect.DeepEqual(first, want) {
+		t.Errorf("Expected first players %v, got %v", want, first)
+	}
+
+	sided := m.Decks[1]
+	if len(sided.Main) != len(deck1) || len(sided.Side) != 1 || sided.Side[0].Name != out {
+		t.Fatalf("Expected P2 to swap %s out, got a %d-card main deck and side %v", out, len(sided.Main), sided.Side)
+	}
+	if sided.Main[len(sided.Main)-1].Name != "Flip Jammer" {
+		t.Error("Expected Flip Jammer sided into P2's main deck")
+	}
+}
+
+// TestSideDeckRevalidated: siding in a card the banlist forbids stops the
+// match before the next game.
+func TestSideDeckRevalidated(t *testing.T) {
+	_, deck0, err := game.DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+	_, deck1, err := game.DeckByNumber("../../decks.yaml", 2)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+
+	p0 := &matchScript{RandomController: game.NewRandomController(1)}
+	p1 := &matchScript{RandomController: game.NewRandomController(2), loses: map[int]bool{1: true}, sideOut: deck1[0].Name}
+	m := &Match{
+		BestOf: 3,
+		Decks: [2]MatchDeck{
+			{Main: deck0},
+			{Main: deck1, Side: []*game.Card{game.FlipJammer()}},
+		},
+		Controllers: [2]game.PlayerController{p0, p1},
+		Banlist:     game.Banlist{"Flip Jammer": 0},
+		Config:      game.DuelConfig{Seed: 5, MaxTurns: 20},
+	}
+
+	res, err := m.Play(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("Expected the sided deck to be rejected as forbidden, got %v", err)
+	}
+	if len(res.Games) != 1 || len(m.Decks[1].Main) != len(deck1) || m.Decks[1].Side[0].Name != "Flip Jammer" {
+		t.Error("Expected the match to stop after game 1 with P2's decks unchanged")
+	}
+}
+
+func TestParseBestOf(t *testing.T) {
+	for format, want := range map[string]int{"bo1": 1, "bo3": 3, "BO5": 5} {
+		if n, err := ParseBestOf(format); err != nil || n != want {
+			t.Errorf("ParseBestOf(%q) = %d, %v; want %d", format, n, err, want)
+		}
+	}
+	for _, format := range []string{"bo2", "bo0", "best"} {
+		if _, err := ParseBestOf(format); err == nil {
+			t.Errorf("Expected ParseBestOf(%q) to fail", format)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package net
 
+import "github.com/peterkuimelis/tcgx/internal/game"
+
 // Message types for the JSON protocol over TCP.
 
 // --- Server → Client messages ---
@@ -12,8 +14,14 @@ type ServerMessage struct {
 	Event *EventView `json:"event,omitempty"`
 
 	// For "choose_action"
-	Actions []ActionView `json:"actions,omitempty"`
-	State   *StateView   `json:"state,omitempty"`
+	Actions   []ActionView `json:"actions,omitempty"`
+	State     *StateView   `json:"state,omitempty"`
+	CanRewind bool         `json:"can_rewind,omitempty"` // debug: "rewind" is accepted
+
+	// For "choose_action", "choose_cards" and "choose_yes_no": numbers the
+	// prompt. The reply echoes it, so a reply that arrives after its prompt
+	// timed out isn't taken as the answer to the next one.
+	Seq int `json:"seq,omitempty"`
 
 	// For "choose_cards"
 	Prompt     string     `json:"prompt,omitempty"`
@@ -21,9 +29,28 @@ type ServerMessage struct {
 	Min        int        `json:"min,omitempty"`
 	Max        int        `json:"max,omitempty"`
 
+	// "state" carries only State: the public view sent to a spectator on connect
+
+	// For "session": the token a joiner reconnects with if their connection drops
+	Token string `json:"token,omitempty"`
+
+	// For "timeline", sent as each turn begins: the turns completed so far.
+	// "game_over" carries every turn.
+	Timeline []TurnView `json:"timeline,omitempty"`
+
 	// For "game_over"
-	Winner int    `json:"winner,omitempty"`
-	Result string `json:"result,omitempty"`
+	Winner    int    `json:"winner,omitempty"`
+	Result    string `json:"result,omitempty"`
+	WinReason string `json:"win_reason,omitempty"` // game.WinReason wire name, e.g. "deckout"
+
+	// For "game_over" in a match: games won by P1 and P2 so far, and whether
+	// another game follows
+	Score    []int `json:"score,omitempty"`
+	NextGame bool  `json:"next_game,omitempty"`
+
+	// For the last "game_over": each player's main and side cards, sorted by
+	// name, revealing what their deck hash committed to
+	Decklists [][]string `json:"decklists,omitempty"`
 }
 
 // EventView is a simplified game event for the client.
@@ -36,18 +63,28 @@ type EventView struct {
 	Details string `json:"details"`
 }
 
+// TurnView is one turn's major actions, for a client's timeline.
+type TurnView struct {
+	Turn    int         `json:"turn"`
+	Player  int         `json:"player"` // the turn player
+	Actions []EventView `json:"actions"`
+}
+
 // ActionView is a numbered action choice.
 type ActionView struct {
-	Index int    `json:"index"`
-	Desc  string `json:"desc"`
+	Index           int    `json:"index"`
+	Desc            string `json:"desc"`
+	OpensChain      bool   `json:"opens_chain,omitempty"`      // activation starts or joins a chain
+	RequiresTargets bool   `json:"requires_targets,omitempty"` // a target prompt follows
 }
 
 // CardView describes a card candidate for selection.
 type CardView struct {
-	Index int    `json:"index"`
-	Name  string `json:"name"`
-	ATK   int    `json:"atk,omitempty"`
-	DEF   int    `json:"def,omitempty"`
+	Index      int    `json:"index"`
+	Name       string `json:"name"`
+	ATK        int    `json:"atk,omitempty"`
+	DEF        int    `json:"def,omitempty"`
+	Controller int    `json:"controller"` // player index (0 or 1) controlling the card
 }
 
 // StateView is the game state from one player's perspective.
@@ -63,11 +100,12 @@ type StateView struct {
 type PlayerView struct {
 	HP             int         `json:"hp"`
 	HandCount      int         `json:"hand_count"`
-	Hand           []string    `json:"hand,omitempty"` // card names (only for "you")
+	Hand           []string    `json:"hand,omitempty"` // card names (for "you", or a revealed opponent hand)
 	Agents         [5]ZoneView `json:"agents"`
 	TechZone       [5]ZoneView `json:"tech_zone"`
 	OS             *ZoneView   `json:"os,omitempty"`
 	ScrapheapCount int         `json:"scrapheap_count"`
+	Scrapheap      []string    `json:"scrapheap,omitempty"` // card names, oldest first; public to both players
 	DeckCount      int         `json:"deck_count"`
 }
 
@@ -87,7 +125,12 @@ type ZoneView struct {
 type ClientMessage struct {
 	Type string `json:"type"`
 
-	// For "action"
+	// For replies to a prompt ("action", "rewind", "cards", "yes_no"): the
+	// prompt's Seq. A reply without one is taken as the answer to the current
+	// prompt.
+	Seq int `json:"seq,omitempty"`
+
+	// For "action" ("rewind" and "spectate" carry no payload)
 	Index int `json:"index,omitempty"`
 
 	// For "cards"
@@ -96,6 +139,16 @@ type ClientMessage struct {
 	// For "yes_no"
 	Answer bool `json:"answer,omitempty"`
 
-	// For "join" (initial handshake)
-	DeckNumber int `json:"deck_number,omitempty"`
+	// For "join" (initial handshake): a deck number in the host's decks file,
+	// or an inline deck from the joiner's own collection
+	DeckNumber int             `json:"deck_number,omitempty"`
+	Deck       *game.DeckEntry `json:"deck,omitempty"`
+
+	// For "join": the joiner's commitment to an inline deck's main and side
+	// cards (game.DeckHash), checked against the deck the server builds and
+	// again against the cards they own as each game ends
+	DeckHash string `json:"deck_hash,omitempty"`
+
+	// For "reconnect": the session token from the server's "session" message
+	Token string `json:"token,omitempty"`
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sync/atomic"
+	"time"
 
 	"github.com/peterkuimelis/tcgx/internal/game"
 	"github.com/peterkuimelis/tcgx/internal/log"
@@ -13,12 +15,28 @@ import (
 
 // Server hosts a duel between two TCP clients.
 type Server struct {
-	DeckFile string
-	Port     string
-	HostDeck int // host's deck number (1-indexed)
+	DeckFile     string // default decks file for both players
+	Port         string
+	HostDeck     int    // host's deck number (1-indexed)
+	HostDeckFile string // host's own decks file (defaults to DeckFile)
+	DebugRewind  bool   // let players rewind to the start of the current turn
+	Seed         int64  // duel RNG seed (0 for random); the seed in effect is printed
+
+	// BestOf plays a match of this many games instead of a single duel
+	// (see Match); 0 or 1 plays one duel.
+	BestOf int
+
+	// ReconnectGrace is how long the duel waits for a dropped joiner to
+	// reconnect (DefaultReconnectGrace if zero).
+	ReconnectGrace time.Duration
 }
 
+// DefaultReconnectGrace is how long a duel waits for a dropped player by default.
+const DefaultReconnectGrace = 2 * time.Minute
+
 // Run starts the server, waits for a client to join, then runs the duel.
+// Spectators may connect at any time and watch without being prompted, and a
+// joiner whose connection drops may reconnect with their session token.
 func (s *Server) Run(ctx context.Context) error {
 	ln, err := net.Listen("tcp", ":"+s.Port)
 	if err != nil {
@@ -28,37 +46,61 @@ func (s *Server) Run(ctx context.Context) error {
 
 	fmt.Printf("Waiting for opponent on port %s...\n", s.Port)
 
-	// Accept exactly one connection (the joiner)
-	conn, err := ln.Accept()
-	if err != nil {
-		return fmt.Errorf("accept: %w", err)
+	watchers := newSpectators(log.NewTextLogger(os.Stdout))
+	defer watchers.close()
+	players := newSessions()
+	joins := make(chan joinRequest, 1)
+	go acceptClients(ln, watchers, players, joins)
+
+	// The first connection to join is the opponent
+	var join joinRequest
+	select {
+	case join = <-joins:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+	conn, joinMsg := join.conn, join.msg
 	defer conn.Close()
 
 	fmt.Printf("Opponent connected from %s\n", conn.RemoteAddr())
-
-	// Read the joiner's deck choice
-	dec := json.NewDecoder(conn)
-	var joinMsg ClientMessage
-	if err := dec.Decode(&joinMsg); err != nil {
-		return fmt.Errorf("read join message: %w", err)
-	}
-	joinerDeck := joinMsg.DeckNumber
-	if joinerDeck == 0 {
-		joinerDeck = 2
-	}
-
-	fmt.Printf("Opponent chose deck %d\n", joinerDeck)
+	joinerSource := DeckSourceFromJoin(joinMsg)
 
 	// Load decks
-	hostDeckName, hostCards, err := game.DeckByNumber(s.DeckFile, s.HostDeck)
+	hostDeckName, hostCards, err := s.hostDeckSource().Load(s.DeckFile)
 	if err != nil {
 		return fmt.Errorf("load host deck: %w", err)
 	}
-	joinerDeckName, joinerCards, err := game.DeckByNumber(s.DeckFile, joinerDeck)
+	joinerDeckName, joinerCards, err := joinerSource.Load(s.DeckFile)
 	if err != nil {
 		return fmt.Errorf("load joiner deck: %w", err)
 	}
+	// Both decks are held to the banlist the server's decks file names
+	banlist, err := game.DeckFileBanlist(s.DeckFile)
+	if err != nil {
+		return fmt.Errorf("load banlist: %w", err)
+	}
+	if err := game.ValidateDeckWithBanlist(hostCards, banlist); err != nil {
+		return fmt.Errorf("host deck %q: %w", hostDeckName, err)
+	}
+	if err := game.ValidateDeckWithBanlist(joinerCards, banlist); err != nil {
+		return fmt.Errorf("joiner deck %q: %w", joinerDeckName, err)
+	}
+
+	var decks [2]MatchDeck
+	decks[0].Main, decks[1].Main = hostCards, joinerCards
+	if decks[0].Side, err = s.hostDeckSource().LoadSide(s.DeckFile); err != nil {
+		return fmt.Errorf("load host side deck: %w", err)
+	}
+	if decks[1].Side, err = joinerSource.LoadSide(s.DeckFile); err != nil {
+		return fmt.Errorf("load joiner side deck: %w", err)
+	}
+	if err := checkCommitment(joinMsg, decks[1]); err != nil {
+		return fmt.Errorf("joiner deck %q: %w", joinerDeckName, err)
+	}
+
+	// Commit to both players' cards now; they are verified as each game ends
+	// and revealed after the last
+	commitments := [2]string{game.DeckHash(decks[0].pool()), game.DeckHash(decks[1].pool())}
 
 	fmt.Printf("Host: %s (%d cards)\n", hostDeckName, len(hostCards))
 	fmt.Printf("Joiner: %s (%d cards)\n", joinerDeckName, len(joinerCards))
@@ -70,14 +112,27 @@ func (s *Server) Run(ctx context.Context) error {
 	// Player 0 = host, Player 1 = joiner
 	hostCtrl := NewNetworkController(hostServerConn, 0)
 	joinerCtrl := NewNetworkController(conn, 1)
+	hostCtrl.AllowRewind = s.DebugRewind
+	joinerCtrl.AllowRewind = s.DebugRewind
+	joinerCtrl.GracePeriod = s.ReconnectGrace
+	if joinerCtrl.GracePeriod == 0 {
+		joinerCtrl.GracePeriod = DefaultReconnectGrace
+	}
+	players.add(joinerCtrl)
+	joinerCtrl.mu.Lock()
+	err = joinerCtrl.send(ServerMessage{Type: "session", Token: joinerCtrl.Token})
+	joinerCtrl.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("send session: %w", err)
+	}
 
-	// Create duel
-	logger := log.NewTextLogger(os.Stdout)
-	duel := game.NewDuel(game.DuelConfig{
-		Deck0:  hostCards,
-		Deck1:  joinerCards,
-		Logger: logger,
-	}, hostCtrl, joinerCtrl)
+	cfg := game.DuelConfig{
+		Logger:      watchers,
+		DebugRewind: s.DebugRewind,
+		Seed:        s.Seed,
+		CoinToss:    true,
+	}
+	ctrls := [2]*NetworkController{hostCtrl, joinerCtrl}
 
 	// Run the host's local REPL in a goroutine
 	errCh := make(chan error, 2)
@@ -86,6 +141,19 @@ func (s *Server) Run(ctx context.Context) error {
 		errCh <- client.RunREPL(ctx)
 	}()
 
+	if s.BestOf > 1 {
+		go func() {
+			errCh <- s.playMatch(ctx, cfg, ctrls, decks, banlist, commitments, watchers)
+		}()
+		return <-errCh
+	}
+
+	// Create duel
+	cfg.Deck0, cfg.Deck1 = hostCards, joinerCards
+	duel := game.NewDuel(cfg, hostCtrl, joinerCtrl)
+	watchers.state = duel.State
+	fmt.Printf("Seed: %d\n", duel.Seed())
+
 	// Run the duel
 	go func() {
 		winner, err := duel.Run(ctx)
@@ -94,22 +162,16 @@ func (s *Server) Run(ctx context.Context) error {
 			return
 		}
 
-		// Send game_over to both players
-		gameOverMsg := ServerMessage{
-			Type:   "game_over",
-			Winner: winner,
-			Result: duel.State.Result,
-		}
-
-		// Send to joiner
-		joinerCtrl.mu.Lock()
-		_ = joinerCtrl.send(gameOverMsg)
-		joinerCtrl.mu.Unlock()
+		duel.VerifyDecks(commitments, [2][]*game.Card{decks[0].Side, decks[1].Side})
 
-		// Send to host
-		hostCtrl.mu.Lock()
-		_ = hostCtrl.send(gameOverMsg)
-		hostCtrl.mu.Unlock()
+		// Send game_over to both players and any spectators
+		sendGameOver(ctrls, watchers, ServerMessage{
+			Type:      "game_over",
+			Winner:    winner,
+			Result:    duel.State.Result,
+			WinReason: duel.State.WinReason.String(),
+			Decklists: revealDecks(decks),
+		})
 
 		errCh <- nil
 	}()
@@ -118,3 +180,102 @@ func (s *Server) Run(ctx context.Context) error {
 	err = <-errCh
 	return err
 }
+
+// playMatch plays a best-of-s.BestOf match, sending each game's result and
+// the score to both players and any spectators.
+func (s *Server) playMatch(ctx context.Context, cfg game.DuelConfig, ctrls [2]*NetworkController, decks [2]MatchDeck, banlist game.Banlist, commitments [2]string, watchers *spectators) error {
+	m := &Match{
+		BestOf:      s.BestOf,
+		Decks:       decks,
+		Controllers: [2]game.PlayerController{ctrls[0], ctrls[1]},
+		Banlist:     banlist,
+		Config:      cfg,
+	}
+	games := 0
+	m.GameStart = func(duel *game.Duel) {
+		games++
+		watchers.state = duel.State
+		fmt.Printf("Game %d seed: %d\n", games, duel.Seed())
+	}
+	m.GameOver = func(duel *game.Duel, res MatchResult) {
+		// Siding moves cards between the main and side decks, so each
+		// game is checked against the same commitment
+		duel.VerifyDecks(commitments, [2][]*game.Card{m.Decks[0].Side, m.Decks[1].Side})
+		msg := ServerMessage{
+			Type:      "game_over",
+			Winner:    duel.State.Winner,
+			Result:    fmt.Sprintf("%s (match %s)", duel.State.Result, res),
+			WinReason: duel.State.WinReason.String(),
+			Score:     res.Score[:],
+			NextGame:  !res.Over(m.BestOf),
+		}
+		if !msg.NextGame {
+			msg.Decklists = revealDecks(m.Decks)
+		}
+		sendGameOver(ctrls, watchers, msg)
+	}
+
+	res, err := m.Play(ctx)
+	if err != nil {
+		return fmt.Errorf("match error: %w", err)
+	}
+	fmt.Printf("Match over: %s\n", res)
+	return nil
+}
+
+// sendGameOver sends a game's result to both players and any spectators.
+// Spectators are disconnected once no game follows.
+func sendGameOver(ctrls [2]*NetworkController, watchers *spectators, msg ServerMessage) {
+	for _, nc := range ctrls {
+		nc.mu.Lock()
+		_ = nc.sendResult(msg)
+		nc.mu.Unlock()
+	}
+	watchers.gameOver(msg)
+}
+
+// joinRequest is a connection that asked to play, with its join message.
+type joinRequest struct {
+	conn net.Conn
+	msg  ClientMessage
+}
+
+// acceptClients accepts connections until the listener closes. Spectators are
+// handed to watchers and reconnecting players to their sessions; the first
+// joiner goes to joins and later ones are turned away.
+func acceptClients(ln net.Listener, watchers *spectators, players *sessions, joins chan<- joinRequest) {
+	var joined atomic.Bool
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			var msg ClientMessage
+			if err := json.NewDecoder(conn).Decode(&msg); err != nil {
+				conn.Close()
+				return
+			}
+			switch msg.Type {
+			case "spectate":
+				watchers.add(conn)
+				return
+			case "reconnect":
+				if !players.reattach(msg.Token, conn) {
+					conn.Close() // no such session
+				}
+				return
+			}
+			if !joined.CompareAndSwap(false, true) {
+				conn.Close() // the duel already has its opponent
+				return
+			}
+			joins <- joinRequest{conn: conn, msg: msg}
+		}()
+	}
+}
+
+// hostDeckSource returns the host's deck source.
+func (s *Server) hostDeckSource() DeckSource {
+	return DeckSource{File: s.HostDeckFile, Number: s.HostDeck}
+}
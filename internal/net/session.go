@@ -0,0 +1,46 @@
+package net
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"sync"
+)
+
+// sessions maps the tokens issued to joining players to their controllers, so
+// a player whose connection drops can rejoin the duel they left.
+type sessions struct {
+	mu      sync.Mutex
+	byToken map[string]*NetworkController
+}
+
+func newSessions() *sessions {
+	return &sessions{byToken: make(map[string]*NetworkController)}
+}
+
+// add registers nc under its Token.
+func (s *sessions) add(nc *NetworkController) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byToken[nc.Token] = nc
+}
+
+// reattach hands conn to the controller holding token, reporting whether the
+// token was known.
+func (s *sessions) reattach(token string, conn net.Conn) bool {
+	s.mu.Lock()
+	nc := s.byToken[token]
+	s.mu.Unlock()
+	if nc == nil {
+		return false
+	}
+	nc.Reattach(conn)
+	return true
+}
+
+// newToken returns a random session token.
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
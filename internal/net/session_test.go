@@ -0,0 +1,89 @@
+package net
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+// TestReconnectDuringPrompt: a player who drops during a card choice and
+// reconnects with their token is sent the same choice, and can answer it.
+func TestReconnectDuringPrompt(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	players := newSessions()
+	go acceptClients(ln, newSpectators(nil), players, make(chan joinRequest))
+
+	first, server := net.Pipe()
+	nc := NewNetworkController(server, 1)
+	nc.GracePeriod = 5 * time.Second
+	players.add(nc)
+
+	duel := game.NewDuel(game.DuelConfig{}, game.NewRandomController(1), nc)
+	gs := duel.State
+	candidates := []*game.CardInstance{
+		gs.CreateCardInstance(game.GreedProtocol(), 1),
+		gs.CreateCardInstance(game.ICEBreaker(), 1),
+	}
+
+	type result struct {
+		chosen []*game.CardInstance
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		chosen, err := nc.ChooseCards(context.Background(), gs, "Choose 1 card", candidates, 1, 1)
+		done <- result{chosen, err}
+	}()
+
+	// The prompt arrives, then the connection drops before the answer
+	var before ServerMessage
+	if err := json.NewDecoder(first).Decode(&before); err != nil || before.Type != "choose_cards" {
+		t.Fatalf("Expected a choose_cards prompt, got %+v (%v)", before, err)
+	}
+	first.Close()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(ClientMessage{Type: "reconnect", Token: nc.Token}); err != nil {
+		t.Fatalf("send reconnect: %v", err)
+	}
+
+	var after ServerMessage
+	if err := json.NewDecoder(conn).Decode(&after); err != nil {
+		t.Fatalf("Expected the prompt resent after reconnecting: %v", err)
+	}
+	if after.Type != "choose_cards" || !reflect.DeepEqual(after.Candidates, before.Candidates) {
+		t.Fatalf("Expected the same choice resent, got %+v", after)
+	}
+	if err := json.NewEncoder(conn).Encode(ClientMessage{Type: "cards", Indices: []int{1}}); err != nil {
+		t.Fatalf("send cards: %v", err)
+	}
+
+	res := <-done
+	if res.err != nil {
+		t.Fatalf("ChooseCards: %v", res.err)
+	}
+	if len(res.chosen) != 1 || res.chosen[0] != candidates[1] {
+		t.Errorf("Expected ICE Breaker chosen, got %v", res.chosen)
+	}
+
+	// Without a grace period, a dropped connection fails the prompt
+	gone, server := net.Pipe()
+	gone.Close()
+	strict := NewNetworkController(server, 1)
+	if _, err := strict.ChooseYesNo(context.Background(), gs, "Continue?"); err == nil {
+		t.Error("Expected a prompt to fail on a dropped connection without a grace period")
+	}
+}
@@ -0,0 +1,171 @@
+package net
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// Spectators are written to in the background so a slow one can't hold up the
+// duel. One that falls spectatorQueue messages behind, or takes longer than
+// spectatorWriteTimeout to accept a message, is dropped.
+const (
+	spectatorQueue        = 1024
+	spectatorWriteTimeout = 10 * time.Second
+)
+
+// spectators relays the public side of a duel to read-only connections. It
+// wraps the duel's logger: each event is logged, then sent to every spectator
+// redacted for neither player, with the public state view.
+type spectators struct {
+	log.EventLogger
+	state *game.GameState // set before the duel starts
+	queue int             // messages a spectator may fall behind by
+
+	mu     sync.Mutex
+	conns  []*spectatorConn
+	last   *StateView      // the view sent with the latest event, for late joiners
+	events []log.GameEvent // redacted, for the timeline
+	over   *ServerMessage
+}
+
+// spectatorConn is one spectator's connection and the queue of messages its
+// writer goroutine has yet to send.
+type spectatorConn struct {
+	conn net.Conn
+	out  chan ServerMessage
+}
+
+func newSpectators(inner log.EventLogger) *spectators {
+	return &spectators{EventLogger: inner, queue: spectatorQueue}
+}
+
+func newSpectatorConn(conn net.Conn, queue int) *spectatorConn {
+	sc := &spectatorConn{conn: conn, out: make(chan ServerMessage, queue)}
+	go sc.write()
+	return sc
+}
+
+// write sends queued messages until the queue is closed, then closes the
+// connection. After a failed write the rest of the queue is discarded.
+func (sc *spectatorConn) write() {
+	defer sc.conn.Close()
+	enc := json.NewEncoder(sc.conn)
+	for msg := range sc.out {
+		_ = sc.conn.SetWriteDeadline(time.Now().Add(spectatorWriteTimeout))
+		if err := enc.Encode(msg); err != nil {
+			sc.conn.Close()
+			for range sc.out {
+			}
+			return
+		}
+	}
+}
+
+// send queues msg, reporting false if the spectator's queue is full.
+func (sc *spectatorConn) send(msg ServerMessage) bool {
+	select {
+	case sc.out <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish closes the connection once the queued messages are sent.
+func (sc *spectatorConn) finish() {
+	close(sc.out)
+}
+
+// drop closes the connection, discarding anything still queued.
+func (sc *spectatorConn) drop() {
+	close(sc.out)
+	sc.conn.Close()
+}
+
+// Log implements log.EventLogger.
+func (s *spectators) Log(event log.GameEvent) {
+	s.EventLogger.Log(event)
+
+	var view *StateView
+	if s.state != nil {
+		view = BuildSpectatorView(s.state)
+	}
+	event = log.RedactFor([]log.GameEvent{event}, log.Spectator)[0]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if event.Type == log.EventNewTurn && len(s.events) > 0 {
+		s.broadcast(ServerMessage{Type: "timeline", Timeline: buildTimeline(s.events)})
+	}
+	s.events = append(s.events, event)
+	s.last = view
+	s.broadcast(ServerMessage{Type: "notify", Event: newEventView(event), State: view})
+}
+
+// add starts relaying the duel to conn, first sending the current state so a
+// spectator joining mid-duel can follow along.
+func (s *spectators) add(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc := newSpectatorConn(conn, s.queue)
+	if s.over != nil {
+		sc.send(*s.over)
+		sc.finish()
+		return
+	}
+	if s.last != nil {
+		sc.send(ServerMessage{Type: "state", State: s.last})
+	}
+	s.conns = append(s.conns, sc)
+}
+
+// gameOver sends a game's result, with its timeline, to every spectator.
+// Once no game follows, their connections are closed.
+func (s *spectators) gameOver(msg ServerMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg.Timeline = buildTimeline(s.events)
+	s.events = nil
+	s.broadcast(msg)
+	if msg.NextGame {
+		return
+	}
+	s.over = &msg
+	s.closeAll()
+}
+
+// close disconnects every spectator.
+func (s *spectators) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeAll()
+}
+
+// broadcast queues msg for every spectator, dropping any that have fallen too
+// far behind. Must be called with mu held.
+func (s *spectators) broadcast(msg ServerMessage) {
+	live := s.conns[:0]
+	for _, sc := range s.conns {
+		if !sc.send(msg) {
+			sc.drop()
+			continue
+		}
+		live = append(live, sc)
+	}
+	s.conns = live
+}
+
+// closeAll disconnects every spectator once its queued messages are sent.
+// Must be called with mu held.
+func (s *spectators) closeAll() {
+	for _, sc := range s.conns {
+		sc.finish()
+	}
+	s.conns = nil
+}
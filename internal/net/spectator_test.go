@@ -0,0 +1,224 @@
+package net
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+	"github.com/peterkuimelis/tcgx/internal/log"
+)
+
+// watch reads a spectator's messages until game over or disconnect.
+func watch(conn net.Conn) <-chan []ServerMessage {
+	done := make(chan []ServerMessage, 1)
+	go func() {
+		var msgs []ServerMessage
+		dec := json.NewDecoder(conn)
+		for {
+			var msg ServerMessage
+			if err := dec.Decode(&msg); err != nil {
+				break
+			}
+			msgs = append(msgs, msg)
+			if msg.Type == "game_over" {
+				break
+			}
+		}
+		done <- msgs
+	}()
+	return done
+}
+
+// lateWatcher plays randomly and connects a second spectator on Turn 3.
+type lateWatcher struct {
+	*game.RandomController
+	watchers *spectators
+	conn     net.Conn
+}
+
+func (lw *lateWatcher) ChooseAction(ctx context.Context, state *game.GameState, actions []game.Action) (game.Action, error) {
+	if state.Turn == 3 && lw.conn != nil {
+		lw.watchers.add(lw.conn)
+		lw.conn = nil
+	}
+	return lw.RandomController.ChooseAction(ctx, state, actions)
+}
+
+// TestSpectators: a spectator gets every event, redacted so it shows only what
+// both players can see, and is never prompted; a late spectator starts from a
+// state snapshot.
+func TestSpectators(t *testing.T) {
+	_, deck, err := game.DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+
+	logger := log.NewMemoryLogger()
+	watchers := newSpectators(logger)
+	early, earlyServer := net.Pipe()
+	late, lateServer := net.Pipe()
+	watchers.add(earlyServer)
+	earlyMsgs, lateMsgs := watch(early), watch(late)
+
+	p0 := &lateWatcher{RandomController: game.NewRandomController(1), watchers: watchers, conn: lateServer}
+	duel := game.NewDuel(game.DuelConfig{Deck0: deck, Deck1: deck, Seed: 3, MaxTurns: 8, Logger: watchers},
+		p0, game.NewRandomController(2))
+	watchers.state = duel.State
+	winner, err := duel.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+	watchers.gameOver(ServerMessage{Type: "game_over", Winner: winner, Result: duel.State.Result})
+
+	events := logger.Events()
+	views := [2][]log.GameEvent{log.RedactFor(events, 0), log.RedactFor(events, 1)}
+	msgs := <-earlyMsgs
+	var seen []*EventView
+	for _, msg := range msgs {
+		if strings.HasPrefix(msg.Type, "choose_") {
+			t.Fatalf("Expected a spectator never to be prompted, got %q", msg.Type)
+		}
+		if msg.Type == "notify" {
+			seen = append(seen, msg.Event)
+		}
+	}
+	if len(seen) != len(events) {
+		t.Fatalf("Expected %d events, got %d", len(events), len(seen))
+	}
+	hidden := 0
+	for i, ev := range seen {
+		public := views[0][i] == events[i] && views[1][i] == events[i]
+		if public && *ev != *newEventView(events[i]) {
+			t.Errorf("Event %d: expected public %q, got %q", i, events[i].Details, ev.Details)
+		}
+		if !public {
+			hidden++
+			if ev.Card != "" || strings.Contains(ev.Details, events[i].Card) {
+				t.Errorf("Event %d: expected %q hidden from spectators, got %q", i, events[i].Card, ev.Details)
+			}
+		}
+	}
+	if hidden == 0 {
+		t.Error("Expected some draws to be hidden from spectators")
+	}
+	if last := msgs[len(msgs)-1]; last.Type != "game_over" || last.Result != duel.State.Result {
+		t.Errorf("Expected the result last, got %+v", last)
+	}
+
+	lateSeen := <-lateMsgs
+	if len(lateSeen) == 0 || lateSeen[0].Type != "state" || lateSeen[0].State == nil || lateSeen[0].State.Turn != 3 {
+		t.Fatalf("Expected a late spectator to start with a Turn 3 state snapshot, got %+v", lateSeen)
+	}
+	if lateSeen[0].State.You.Hand != nil || lateSeen[0].State.Opponent.Hand != nil {
+		t.Error("Expected the spectator snapshot to hide both hands")
+	}
+}
+
+// TestSpectatorTimeline: a spectator gets the timeline of completed turns as
+// each turn begins, and the whole duel's timeline with the result.
+func TestSpectatorTimeline(t *testing.T) {
+	_, deck, err := game.DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+
+	logger := log.NewMemoryLogger()
+	watchers := newSpectators(logger)
+	conn, server := net.Pipe()
+	watchers.add(server)
+	msgs := watch(conn)
+
+	duel := game.NewDuel(game.DuelConfig{Deck0: deck, Deck1: deck, Seed: 5, MaxTurns: 2, Logger: watchers},
+		game.NewRandomController(1), game.NewRandomController(2))
+	watchers.state = duel.State
+	winner, err := duel.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Duel error: %v", err)
+	}
+	watchers.gameOver(ServerMessage{Type: "game_over", Winner: winner, Result: duel.State.Result})
+
+	var timelines [][]TurnView
+	for _, msg := range <-msgs {
+		if msg.Type == "timeline" || msg.Type == "game_over" {
+			timelines = append(timelines, msg.Timeline)
+		}
+	}
+	if len(timelines) != 2 || len(timelines[0]) != 1 || len(timelines[1]) != 2 {
+		t.Fatalf("Expected a 1-turn timeline on Turn 2 and a 2-turn one at the end, got %+v", timelines)
+	}
+
+	// Each turn's summary holds that turn's summons, Sets, activations,
+	// attacks and phase changes
+	var want [2]int
+	for _, e := range logger.Events() {
+		switch e.Type {
+		case log.EventPhaseChange, log.EventNormalSummon, log.EventSacrificeSummon, log.EventFlipSummon,
+			log.EventSpecialSummon, log.EventSetAgent, log.EventSetTech, log.EventActivate,
+			log.EventAttackDeclare, log.EventDirectAttackDeclare:
+			want[e.Turn-1]++
+		}
+	}
+	for i, turn := range timelines[1] {
+		if turn.Turn != i+1 || len(turn.Actions) != want[i] {
+			t.Errorf("Expected Turn %d with %d actions, got Turn %d with %d", i+1, want[i], turn.Turn, len(turn.Actions))
+		}
+	}
+	if got := len(timelines[0][0].Actions); got != want[0] {
+		t.Errorf("Expected Turn 1's summary on Turn 2 to have %d actions, got %d", want[0], got)
+	}
+}
+
+// TestStalledSpectator: a spectator that never reads is dropped once it falls
+// behind, without holding up the duel or the other spectators.
+func TestStalledSpectator(t *testing.T) {
+	_, deck, err := game.DeckByNumber("../../decks.yaml", 1)
+	if err != nil {
+		t.Fatalf("load deck: %v", err)
+	}
+
+	watchers := newSpectators(log.NewMemoryLogger())
+	watchers.queue = 4 // the stalled spectator falls behind fast
+	stalled, stalledServer := net.Pipe()
+	defer stalled.Close()
+	watchers.add(stalledServer)
+	watchers.queue = spectatorQueue
+	conn, server := net.Pipe()
+	watchers.add(server)
+	msgs := watch(conn)
+
+	duel := game.NewDuel(game.DuelConfig{Deck0: deck, Deck1: deck, Seed: 3, MaxTurns: 4, Logger: watchers},
+		game.NewRandomController(1), game.NewRandomController(2))
+	watchers.state = duel.State
+	done := make(chan error, 1)
+	go func() {
+		_, err := duel.Run(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Duel error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the duel not to wait on a stalled spectator")
+	}
+
+	watchers.mu.Lock()
+	n := len(watchers.conns)
+	watchers.mu.Unlock()
+	if n != 1 {
+		t.Errorf("Expected only the reading spectator to be left, got %d", n)
+	}
+	if _, err := stalled.Read(make([]byte, 1)); err == nil {
+		t.Error("Expected the stalled spectator's connection to be closed")
+	}
+
+	watchers.gameOver(ServerMessage{Type: "game_over", Result: duel.State.Result})
+	if got := <-msgs; len(got) == 0 || got[len(got)-1].Type != "game_over" {
+		t.Error("Expected the reading spectator to get the result")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/coder/websocket"
@@ -32,6 +33,23 @@ type CardInfo struct {
 	IsEffect    bool   `json:"isEffect,omitempty"`
 	Subtype     string `json:"subtype,omitempty"`
 	ArtPath     string `json:"artPath,omitempty"`
+
+	Effects []EffectInfo `json:"effects,omitempty"`
+}
+
+// EffectInfo describes one of a card's effects: how fast it is, what kind it
+// is, what it triggers on, and the battle abilities it grants.
+type EffectInfo struct {
+	Name            string   `json:"name,omitempty"`
+	ExecSpeed       int      `json:"execSpeed"`
+	EffectType      string   `json:"effectType,omitempty"`
+	Trigger         bool     `json:"trigger,omitempty"`
+	TriggerEvents   []string `json:"triggerEvents,omitempty"`
+	Mandatory       bool     `json:"mandatory,omitempty"`
+	HandTrap        bool     `json:"handTrap,omitempty"`
+	OncePerTurn     bool     `json:"oncePerTurn,omitempty"`
+	HasPiercing     bool     `json:"hasPiercing,omitempty"`
+	CanDirectAttack bool     `json:"canDirectAttack,omitempty"`
 }
 
 // DeckInfo is the JSON representation of a deck for the /api/decks endpoint.
@@ -39,6 +57,7 @@ type DeckInfo struct {
 	Number int      `json:"number"`
 	Name   string   `json:"name"`
 	Cards  []string `json:"cards"`
+	Error  string   `json:"error,omitempty"` // why the deck can't be played, if it can't
 }
 
 // Server is the tcgx web UI server.
@@ -100,7 +119,9 @@ func (s *Server) setupRoutes() {
 
 	// API endpoints
 	s.mux.HandleFunc("GET /api/cards", s.handleCards)
+	s.mux.HandleFunc("GET /api/cards/{name}", s.handleCard)
 	s.mux.HandleFunc("GET /api/decks", s.handleDecks)
+	s.mux.HandleFunc("GET /api/deck-stats", s.handleDeckStats)
 
 	// WebSocket proxy
 	s.mux.HandleFunc("GET /ws", s.handleWebSocket)
@@ -109,37 +130,81 @@ func (s *Server) setupRoutes() {
 func (s *Server) handleCards(w http.ResponseWriter, r *http.Request) {
 	var cards []CardInfo
 	for name, ctor := range game.CardRegistry {
-		c := ctor()
-		ci := CardInfo{
-			Name:        name,
-			Description: c.Description,
-			Level:       c.Level,
-			Attribute:   c.Attribute.String(),
-			AgentType:   c.AgentType,
-			ATK:         c.ATK,
-			DEF:         c.DEF,
-			IsEffect:    c.IsEffect,
-		}
-		switch c.CardType {
-		case game.CardTypeAgent:
-			ci.CardType = "Agent"
-		case game.CardTypeProgram:
-			ci.CardType = "Program"
-			ci.Subtype = programSubtypeString(c.ProgramSub)
-		case game.CardTypeTrap:
-			ci.CardType = "Trap"
-			ci.Subtype = trapSubtypeString(c.TrapSub)
-		}
-		// Art path: strip "card_art/" prefix since we serve from /art/
-		if artPath, ok := s.artMapping[name]; ok {
-			ci.ArtPath = "/art/" + strings.TrimPrefix(artPath, "card_art/")
-		}
-		cards = append(cards, ci)
+		cards = append(cards, s.cardInfo(name, ctor()))
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(cards)
 }
 
+func (s *Server) handleCard(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	ctor, ok := game.CardRegistry[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown card %q", name), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.cardInfo(name, ctor()))
+}
+
+// cardInfo describes a card for the card database endpoints.
+func (s *Server) cardInfo(name string, c *game.Card) CardInfo {
+	ci := CardInfo{
+		Name:        name,
+		Description: c.Description,
+		Level:       c.Level,
+		Attribute:   c.Attribute.String(),
+		AgentType:   c.AgentType,
+		ATK:         c.ATK,
+		DEF:         c.DEF,
+		IsEffect:    c.IsEffect,
+	}
+	switch c.CardType {
+	case game.CardTypeAgent:
+		ci.CardType = "Agent"
+	case game.CardTypeProgram:
+		ci.CardType = "Program"
+		ci.Subtype = programSubtypeString(c.ProgramSub)
+	case game.CardTypeTrap:
+		ci.CardType = "Trap"
+		ci.Subtype = trapSubtypeString(c.TrapSub)
+	}
+	// Art path: strip "card_art/" prefix since we serve from /art/
+	if artPath, ok := s.artMapping[name]; ok {
+		ci.ArtPath = "/art/" + strings.TrimPrefix(artPath, "card_art/")
+	}
+	for _, eff := range c.Effects {
+		ci.Effects = append(ci.Effects, effectInfo(c, eff))
+	}
+	return ci
+}
+
+func effectInfo(c *game.Card, eff *game.CardEffect) EffectInfo {
+	ei := EffectInfo{
+		Name:            eff.Name,
+		ExecSpeed:       int(eff.ExecSpeed),
+		EffectType:      effectTypeString(eff.EffectType),
+		Trigger:         eff.IsTrigger || eff.Trigger != nil || eff.EffectType == game.EffectTrigger,
+		Mandatory:       eff.IsMandatory,
+		HandTrap:        eff.HandTrap,
+		OncePerTurn:     eff.OncePerTurn,
+		HasPiercing:     eff.HasPiercing || eff.Piercing != nil,
+		CanDirectAttack: eff.CanDirectAttack != nil,
+	}
+	// Most effects leave their speed to the card's type
+	if ei.ExecSpeed == 0 {
+		ei.ExecSpeed = int(game.EffectExecSpeed(c))
+	}
+	if eff.Trigger != nil {
+		for _, on := range eff.Trigger.On {
+			ei.TriggerEvents = append(ei.TriggerEvents, on.String())
+		}
+	} else if eff.IsTrigger {
+		ei.TriggerEvents = []string{eff.TriggerEvent.String()}
+	}
+	return ei
+}
+
 func (s *Server) handleDecks(w http.ResponseWriter, r *http.Request) {
 	data, err := os.ReadFile(s.decksFile)
 	if err != nil {
@@ -152,6 +217,11 @@ func (s *Server) handleDecks(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "could not parse decks file", http.StatusInternalServerError)
 		return
 	}
+	banlist, err := game.DeckFileBanlist(s.decksFile)
+	if err != nil {
+		http.Error(w, "could not load banlist", http.StatusInternalServerError)
+		return
+	}
 
 	var decks []DeckInfo
 	for i, d := range df.Decks {
@@ -167,12 +237,32 @@ func (s *Server) handleDecks(w http.ResponseWriter, r *http.Request) {
 				seen[c.Name] = true
 			}
 		}
+		if cards, err := game.BuildDeck(d); err != nil {
+			di.Error = err.Error()
+		} else if err := game.ValidateDeckWithBanlist(cards, banlist); err != nil {
+			di.Error = err.Error()
+		}
 		decks = append(decks, di)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(decks)
 }
 
+func (s *Server) handleDeckStats(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("deck"))
+	if err != nil {
+		http.Error(w, "deck must be a deck number", http.StatusBadRequest)
+		return
+	}
+	_, cards, err := game.DeckByNumber(s.decksFile, n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.AnalyzeDeck(cards))
+}
+
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	wsConn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
 		InsecureSkipVerify: true, // Allow connections from any origin
@@ -288,6 +378,23 @@ func programSubtypeString(sub game.ProgramSubtype) string {
 	}
 }
 
+func effectTypeString(t game.EffectType) string {
+	switch t {
+	case game.EffectFlip:
+		return "Flip"
+	case game.EffectIgnition:
+		return "Ignition"
+	case game.EffectTrigger:
+		return "Trigger"
+	case game.EffectContinuous:
+		return "Continuous"
+	case game.EffectQuick:
+		return "Quick"
+	default:
+		return ""
+	}
+}
+
 func trapSubtypeString(sub game.TrapSubtype) string {
 	switch sub {
 	case game.TrapNormal:
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/peterkuimelis/tcgx/internal/game"
+)
+
+// TestCardEndpoint: a single card is served with its effects' metadata, and an
+// unknown name is a 404.
+func TestCardEndpoint(t *testing.T) {
+	s, err := NewServer(t.TempDir(), "../../decks.yaml", "no-mapping.json")
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/cards/"+url.PathEscape("Firewall Sentinel"), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var ci CardInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &ci); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if ci.CardType != "Trap" || ci.Subtype != "Counter" {
+		t.Errorf("Expected a Counter Trap, got %s %s", ci.Subtype, ci.CardType)
+	}
+	if len(ci.Effects) != 1 || ci.Effects[0].ExecSpeed != 3 {
+		t.Errorf("Expected one ExecSpeed 3 effect, got %+v", ci.Effects)
+	}
+
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/cards/No%20Such%20Card", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown card, got %d", rec.Code)
+	}
+}
+
+// TestDeckStatsEndpoint: deck 1's stats add up to its 40 cards.
+func TestDeckStatsEndpoint(t *testing.T) {
+	s, err := NewServer(t.TempDir(), "../../decks.yaml", "no-mapping.json")
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/deck-stats?deck=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var st game.DeckStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	types := st.ByCardType["Agent"] + st.ByCardType["Program"] + st.ByCardType["Trap"]
+	if st.Total != 40 || types != 40 || st.Effect+st.Vanilla != st.ByCardType["Agent"] {
+		t.Errorf("Expected stats covering 40 cards, got %+v", st)
+	}
+
+	for _, q := range []string{"deck=x", "deck=99"} {
+		rec = httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/deck-stats?"+q, nil))
+		if rec.Code == http.StatusOK {
+			t.Errorf("Expected an error for %s", q)
+		}
+	}
+}
@@ -6,6 +6,7 @@
   let cardCatalog = {};  // name → CardInfo
   let currentState = null;
   let selectionMode = null;  // { candidates, min, max, selected: Set }
+  let promptSeq = 0;         // seq of the prompt being answered, echoed in replies
 
   // ─── DOM refs ───
   const $ = id => document.getElementById(id);
@@ -77,6 +78,10 @@
           const opt = document.createElement('option');
           opt.value = d.number;
           opt.textContent = d.number + ': ' + d.name;
+          if (d.error) {
+            opt.textContent += ' (' + d.error + ')';
+            opt.disabled = true;
+          }
           deckSelect.appendChild(opt);
         });
       })
@@ -145,6 +150,7 @@
   }
 
   function send(msg) {
+    if (promptSeq) msg.seq = promptSeq;
     if (ws && ws.readyState === WebSocket.OPEN) {
       ws.send(JSON.stringify(msg));
     }
@@ -153,6 +159,7 @@
   // ─── Message Handling ───
 
   function handleServerMessage(msg) {
+    if (msg.seq) promptSeq = msg.seq;
     switch (msg.type) {
       case 'notify':
         appendEvent(msg.event);
@@ -189,6 +196,7 @@
     oppHandCount.textContent = opp.hand_count;
     oppDeckCount.textContent = opp.deck_count;
     oppSH.textContent = opp.scrapheap_count;
+    oppSH.title = (opp.scrapheap || []).join('\n');
 
     // Opponent hand visual
     renderOppHandBacks(opp.hand_count);
@@ -202,6 +210,7 @@
     yourHP.textContent = you.hp;
     yourDeckCount.textContent = you.deck_count;
     yourSH.textContent = you.scrapheap_count;
+    yourSH.title = (you.scrapheap || []).join('\n');
 
     // Your field
     renderZones(yourAgents, you.agents, true, 'agent');